@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/accio/internal/output"
+)
+
+// runDiff runs the diff subcommand and returns the exit code: 0 without
+// changes, 1 with changes and 2 on errors, like diff(1)
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	format := fs.String("format", string(output.FormatText), "Output format (text, json, markdown)")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: accio diff [options] old.json new.json\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	switch output.FormatType(*format) {
+	case output.FormatText, output.FormatJSON, output.FormatMarkdown:
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format %q, expected one of text, json, markdown\n", *format)
+		return 2
+	}
+
+	old, err := readResultsFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	current, err := readResultsFile(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	diff := output.DiffResults(old, current)
+	switch output.FormatType(*format) {
+	case output.FormatText:
+		output.WriteDiffText(os.Stdout, diff, !*noColor)
+	case output.FormatJSON:
+		if err := output.WriteDiffJSON(os.Stdout, diff); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	case output.FormatMarkdown:
+		output.WriteDiffMarkdown(os.Stdout, diff)
+	}
+
+	if diff.HasChanges() {
+		return 1
+	}
+	return 0
+}
+
+// readResultsFile reads results saved with -format json or ndjson
+func readResultsFile(filename string) ([]output.Result, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	results, err := output.ReadResults(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	return results, nil
+}
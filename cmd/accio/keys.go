@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	appservice "github.com/accio/internal/application/service"
+	"github.com/accio/internal/infrastructure/persistence"
+)
+
+// runKeys runs the keys subcommand and returns the exit code
+func runKeys(args []string) int {
+	if len(args) == 0 {
+		keysUsage()
+		return 2
+	}
+
+	db, err := persistence.NewDatabase()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	if os.Getenv("TURSO_DATABASE_URL") == "" {
+		fmt.Fprintln(os.Stderr, "Warning: TURSO_DATABASE_URL is not set, keys are kept in an in-memory database and lost on exit")
+	}
+
+	apiKeyService := appservice.NewAPIKeyService(persistence.NewGormAPIKeyRepository(db.DB))
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	switch args[0] {
+	case "create":
+		err = createKey(ctx, apiKeyService, args[1:])
+	case "list":
+		err = listKeys(ctx, apiKeyService)
+	case "revoke":
+		err = revokeKey(ctx, apiKeyService, args[1:])
+	default:
+		keysUsage()
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// keysUsage prints the keys subcommand help
+func keysUsage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n")
+	fmt.Fprintf(os.Stderr, "  accio keys create -name <name> [-scopes read,scan] [-rate-limit n] [-daily-quota n]\n")
+	fmt.Fprintf(os.Stderr, "  accio keys list\n")
+	fmt.Fprintf(os.Stderr, "  accio keys revoke -id <id>\n")
+}
+
+// createKey creates an API key and prints it once
+func createKey(ctx context.Context, apiKeyService appservice.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("keys create", flag.ContinueOnError)
+	name := fs.String("name", "", "Name describing the key's owner")
+	scopes := fs.String("scopes", "read", "Comma-separated scopes (read, scan)")
+	rateLimit := fs.Int("rate-limit", 60, "Requests per minute, 0 for unlimited")
+	dailyQuota := fs.Int("daily-quota", 1000, "Requests per day, 0 for unlimited")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	var scopeList []string
+	for _, scope := range strings.Split(*scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopeList = append(scopeList, scope)
+		}
+	}
+	if len(scopeList) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+
+	rawKey, apiKey, err := apiKeyService.CreateKey(ctx, *name, scopeList, *rateLimit, *dailyQuota)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created API key %d (%s) with scopes %s\n", apiKey.ID, apiKey.Name, apiKey.Scopes)
+	fmt.Printf("\n  %s\n\n", rawKey)
+	fmt.Println("Store this key now, it cannot be shown again.")
+	return nil
+}
+
+// listKeys prints all API keys without their secrets
+func listKeys(ctx context.Context, apiKeyService appservice.APIKeyService) error {
+	apiKeys, err := apiKeyService.ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tRATE LIMIT\tDAILY QUOTA\tLAST USED\tSTATUS")
+	for _, apiKey := range apiKeys {
+		lastUsed := "never"
+		if apiKey.LastUsedAt != nil {
+			lastUsed = apiKey.LastUsedAt.Format(time.RFC3339)
+		}
+		status := "active"
+		if apiKey.IsRevoked() {
+			status = "revoked"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			apiKey.ID, apiKey.Name, apiKey.Prefix, apiKey.Scopes, apiKey.RateLimit, apiKey.DailyQuota, lastUsed, status)
+	}
+	return w.Flush()
+}
+
+// revokeKey revokes an API key
+func revokeKey(ctx context.Context, apiKeyService appservice.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("keys revoke", flag.ContinueOnError)
+	id := fs.Uint("id", 0, "ID of the key to revoke")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *id == 0 {
+		return fmt.Errorf("-id is required")
+	}
+
+	if err := apiKeyService.RevokeKey(ctx, *id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Revoked API key %d\n", *id)
+	return nil
+}
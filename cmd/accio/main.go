@@ -0,0 +1,472 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/accio/internal/checker"
+	"github.com/accio/internal/crosslink"
+	"github.com/accio/internal/domain/model"
+	domainservice "github.com/accio/internal/domain/service"
+	"github.com/accio/internal/image"
+	"github.com/accio/internal/infrastructure/container"
+	"github.com/accio/internal/intersection"
+	"github.com/accio/internal/matcher"
+	"github.com/accio/internal/output"
+	httpserver "github.com/accio/internal/presentation/http"
+	"github.com/accio/internal/scanner"
+	"github.com/accio/internal/sites"
+)
+
+// version is the application version
+const version = "1.0.0"
+
+func main() {
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "keys":
+			os.Exit(runKeys(os.Args[2:]))
+		case "train":
+			os.Exit(runTrain(os.Args[2:]))
+		case "resolve":
+			os.Exit(runResolve(os.Args[2:]))
+		case "diff":
+			os.Exit(runDiff(os.Args[2:]))
+		}
+	}
+
+	// Command-line flags
+	username := flag.String("username", "", "Username to search for")
+	realName := flag.String("real-name", "", "Real name of the person, compared with the names on profiles fetched from platform APIs")
+	linkDepth := flag.Int("link-depth", 0, "Follow links between found profiles to other usernames up to this depth (0 disables)")
+	fetchProfiles := flag.Bool("profiles", false, "Fetch the name, bio, picture and follower count of found profiles from platform APIs and save them with the results (implied by graph formats)")
+	verbose := flag.Bool("verbose", false, "Enable verbose output")
+	timeout := flag.Int("timeout", scanner.DefaultTimeout, "Timeout in seconds for HTTP requests")
+	outputFile := flag.String("output", "", "Output file to save results")
+	format := flag.String("format", string(output.FormatText), "Output format ("+formatList()+")")
+	templateFile := flag.String("template", "", "Go text/template file used by -format template")
+	noColor := flag.Bool("no-color", false, "Disable colored output")
+	concurrency := flag.Int("concurrency", runtime.NumCPU(), "Number of concurrent requests")
+	retries := flag.Int("retries", scanner.DefaultRetries, "Number of retries for failed requests")
+	showVersion := flag.Bool("version", false, "Show version information")
+	listSites := flag.Bool("list-sites", false, "List all available sites")
+	web := flag.Bool("web", false, "Start the web server")
+	port := flag.Int("port", 8080, "Port for the web server")
+	useDatabase := flag.Bool("use-database", false, "Connect to the profile database and record searches (implied by -web)")
+	seedDatabase := flag.Bool("seed-database", false, "Seed the profile database with sample data")
+	noCache := flag.Bool("no-cache", false, "Fetch profiles from platform APIs even if stored profiles are fresh (same as PROFILE_NO_CACHE=true)")
+	flag.Usage = usage
+	flag.Parse()
+
+	options := container.Options{NoCache: *noCache}
+
+	switch {
+	case *showVersion:
+		fmt.Printf("accio version %s\n", version)
+		return
+	case *listSites:
+		printSites()
+		return
+	case *web:
+		if err := runWeb(options, *port, *seedDatabase); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	case *username == "":
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if _, ok := output.Lookup(output.FormatType(*format)); !ok {
+		fmt.Fprintf(os.Stderr, "Unknown format %q, expected one of %s\n", *format, formatList())
+		os.Exit(2)
+	}
+
+	if (*format == string(output.FormatTemplate)) != (*templateFile != "") {
+		fmt.Fprintf(os.Stderr, "-format template and -template must be used together\n")
+		os.Exit(2)
+	}
+
+	formatter := output.NewFormatter(*verbose).
+		WithFormat(output.FormatType(*format)).
+		WithColor(!*noColor)
+	if *templateFile != "" {
+		tmpl, err := output.ParseTemplate(*templateFile)
+		if err != nil {
+			log.Fatalf("Error loading template: %v", err)
+		}
+		formatter.WithTemplate(tmpl)
+	}
+
+	s := scanner.NewScanner(scanner.Options{
+		Timeout:     *timeout,
+		Concurrency: *concurrency,
+		Retries:     *retries,
+		Verbose:     *verbose,
+	})
+
+	graphOutput := isGraphOutput(*format, *outputFile)
+	recording := *useDatabase || *seedDatabase
+
+	// The profile database and platform clients are opened once for every
+	// step of the scan that needs them
+	var c *container.Container
+	if *linkDepth > 0 || *fetchProfiles || graphOutput || *realName != "" || recording {
+		var err error
+		c, err = container.NewContainerWithOptions(options)
+		switch {
+		case err != nil && recording:
+			log.Fatalf("Error: %v", err)
+		case err != nil:
+			log.Printf("Warning: profiles not fetched from platform APIs: %v", err)
+		default:
+			defer c.Close()
+		}
+	}
+
+	fetch := *fetchProfiles || graphOutput
+	allResults, links, stats := scan(s, c, *username, *linkDepth, *timeout, fetch, formatter)
+	results := flatten(*username, allResults)
+	formatter.WithStats(stats).WithAvatars(cachedAvatars())
+	analysis := analyze(c, *username, *realName, fetch, allResults, links)
+	formatter.WithAnalysis(analysis)
+	if graphOutput {
+		formatter.WithGraph(buildGraph(*username, allResults, links, analysis))
+	}
+	formatter.PrintSummary(results)
+
+	if *outputFile != "" {
+		if err := formatter.SaveToFile(results, *outputFile); err != nil {
+			log.Fatalf("Error saving results: %v", err)
+		}
+	}
+
+	if recording {
+		if err := recordScan(c, *username, allResults[*username], *seedDatabase); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+}
+
+// usage prints the command-line help
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  accio -username <name> [-real-name \"First Last\"] [options]\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  accio -web [-port 8080] [-seed-database] [-no-cache]\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  accio keys <create|list|revoke> [options]\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  accio train [-output confidence_weights.json]\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  accio resolve [-threshold 0.7]\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  accio diff [-format text] old.json new.json\n\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "Options:\n")
+	flag.PrintDefaults()
+}
+
+// formatList returns the registered output formats separated by commas
+func formatList() string {
+	formats := output.Formats()
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, string(format))
+	}
+	return strings.Join(names, ", ")
+}
+
+// printSites prints every supported site
+func printSites() {
+	siteList := sites.GetSites()
+	for _, site := range siteList {
+		fmt.Printf("%-20s %s\n", site.Name, site.URL)
+	}
+	fmt.Printf("\n%d sites\n", len(siteList))
+}
+
+// scan checks the username on every site, printing results as they arrive,
+// and follows the links on found profiles to other usernames up to
+// linkDepth. With a container, profiles on platforms with API clients are
+// fetched for the links in their bios and platform data, and with
+// fetchProfiles saved with their results. An interrupt stops the scan and
+// keeps the results gathered so far.
+func scan(s *scanner.Scanner, c *container.Container, username string, linkDepth, timeout int, fetchProfiles bool, formatter *output.Formatter) (map[string][]output.Result, []output.Link, checker.CheckStats) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var profiles *apiProfiles
+	if c != nil && (linkDepth > 0 || fetchProfiles) {
+		profiles = newAPIProfiles(c.ProfileService)
+	}
+
+	options := crosslink.Options{Depth: linkDepth, Timeout: timeout}
+	if linkDepth > 0 && profiles != nil {
+		options.Profiles = profiles
+	}
+
+	var mu sync.Mutex
+	fetched := make(map[string]*output.Profile)
+	crawler := crosslink.NewCrawler(s, options)
+	allResults, links := crawler.Crawl(ctx, username, func(username string, result output.Result) {
+		if fetchProfiles && profiles != nil && result.Exists {
+			result.Profile = profiles.Profile(ctx, result.Site, username)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if result.Profile != nil {
+			fetched[result.Site+"/"+username] = result.Profile
+		}
+		formatter.PrintResult(result)
+	})
+
+	// The crawler keeps the results as they were found
+	for username, results := range allResults {
+		for i := range results {
+			results[i].Profile = fetched[results[i].Site+"/"+username]
+		}
+	}
+	return allResults, links, crawler.Stats()
+}
+
+// apiProfiles fetches found profiles for the crawler through the profile
+// service, which stores them like the profiles fetched for name matching
+type apiProfiles struct {
+	profileService domainservice.ProfileService
+	supported      map[string]bool
+}
+
+// newAPIProfiles creates an apiProfiles fetching from the platforms with API clients
+func newAPIProfiles(profileService domainservice.ProfileService) *apiProfiles {
+	supported := make(map[string]bool)
+	for _, platform := range profileService.GetSupportedPlatforms() {
+		supported[platform] = true
+	}
+	return &apiProfiles{profileService: profileService, supported: supported}
+}
+
+// FetchProfile returns the bio and platform data of a profile, or nothing if
+// its platform has no API client
+func (p *apiProfiles) FetchProfile(ctx context.Context, site, username string) (string, map[string]string, error) {
+	if !p.supported[site] {
+		return "", nil, nil
+	}
+
+	profile, err := p.profileService.GetProfileByUsername(ctx, username, site)
+	if err != nil || profile == nil {
+		return "", nil, err
+	}
+	return profile.Bio, profile.PlatformData, nil
+}
+
+// Profile returns the name, bio, picture and follower count of a found
+// profile, or nil if its platform has no API client or it cannot be fetched
+func (p *apiProfiles) Profile(ctx context.Context, site, username string) *output.Profile {
+	if !p.supported[site] {
+		return nil
+	}
+
+	profile, err := p.profileService.GetProfileByUsername(ctx, username, site)
+	if err != nil || profile == nil {
+		return nil
+	}
+	return &output.Profile{
+		Name:      profile.RealName,
+		Bio:       profile.Bio,
+		AvatarURL: profile.ImageURL,
+		Followers: profile.FollowerCount,
+	}
+}
+
+// avatarThumbnailSize is the width and height of profile pictures embedded
+// in the HTML report
+const avatarThumbnailSize = 64
+
+// cachedAvatars returns the profile pictures in the image cache of the web
+// server, scaled down to thumbnails
+func cachedAvatars() output.AvatarSource {
+	processor := &image.ImageProcessor{CacheDir: image.CacheDirFromEnv()}
+	return func(site, username string) []byte {
+		thumbnail, err := processor.CachedThumbnail(username, site, avatarThumbnailSize)
+		if err != nil {
+			return nil
+		}
+		return thumbnail
+	}
+}
+
+// flatten returns the results of the username followed by those of the
+// linked usernames in alphabetical order
+func flatten(username string, allResults map[string][]output.Result) []output.Result {
+	var linked []string
+	for other := range allResults {
+		if other != username {
+			linked = append(linked, other)
+		}
+	}
+	sort.Strings(linked)
+
+	results := allResults[username]
+	for _, other := range linked {
+		results = append(results, allResults[other]...)
+	}
+	return results
+}
+
+// analyze scores how likely the profiles found for the username belong to
+// the same person, or returns nil if none were found. With a real name and a
+// container, the profiles on platforms with API clients are fetched to
+// compare their names. Once profiles were fetched, either for their names or
+// with fetchProfiles, their pictures are compared too.
+func analyze(c *container.Container, username, realName string, fetchProfiles bool, allResults map[string][]output.Result, links []output.Link) *output.Analysis {
+	analysis := intersection.AnalyzeLinkedResults(allResults, links)
+
+	var match *intersection.ProfileMatch
+	for i := range analysis.Matches {
+		if analysis.Matches[i].Username == username {
+			match = &analysis.Matches[i]
+		}
+	}
+	if match == nil {
+		return nil
+	}
+
+	if c == nil {
+		return match.Analysis()
+	}
+
+	rescore := false
+	if realName != "" {
+		matchNames(c, match, matcher.ParseFullName(realName))
+		rescore = true
+	}
+	if (realName != "" || fetchProfiles) && compareAvatars(c, match) {
+		rescore = true
+	}
+	if rescore {
+		intersection.CurrentWeights().Apply(match)
+	}
+	return match.Analysis()
+}
+
+// compareAvatars sets the similarity of the pictures of the stored profiles
+// found for a match, hashing the pictures not hashed yet. It reports whether
+// at least two pictures were compared.
+func compareAvatars(c *container.Container, match *intersection.ProfileMatch) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stored, err := c.ProfileRepository.FindAllByUsername(ctx, match.Username)
+	if err != nil {
+		log.Printf("Warning: profile pictures not compared: %v", err)
+		return false
+	}
+
+	found := make(map[string]bool)
+	for _, result := range match.Results {
+		if result.Exists {
+			found[result.Site] = true
+		}
+	}
+	var profiles []*model.Profile
+	for _, profile := range stored {
+		if found[profile.Platform] {
+			profiles = append(profiles, profile)
+		}
+	}
+	c.IdentityService.HashAvatars(ctx, profiles)
+
+	var hashes []uint64
+	for _, profile := range profiles {
+		if hash, err := image.ParseHash(profile.ImageHash); err == nil && profile.ImageHash != "" {
+			hashes = append(hashes, hash)
+		}
+	}
+	if len(hashes) < 2 {
+		return false
+	}
+	match.AvatarSimilarity = intersection.AvatarSimilarity(hashes)
+	return true
+}
+
+// matchNames fills the name matches of a match from the profiles fetched
+// through the platform APIs
+func matchNames(c *container.Container, match *intersection.ProfileMatch, name *matcher.NameInfo) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	c.NameMatchService.MatchNames(ctx, match, name)
+}
+
+// isGraphOutput reports whether the results are printed or saved in a graph format
+func isGraphOutput(format, outputFile string) bool {
+	switch output.FormatType(format) {
+	case output.FormatGraphML, output.FormatGEXF, output.FormatDOT, output.FormatSTIX:
+		return true
+	}
+	switch fileFormat, _ := output.FormatForFile(outputFile); fileFormat {
+	case output.FormatGraphML, output.FormatGEXF, output.FormatDOT, output.FormatSTIX:
+		return true
+	}
+	return false
+}
+
+// buildGraph builds the graph of the found profiles with the confidence of
+// each username and the follower counts of the profiles fetched with the
+// results
+func buildGraph(username string, allResults map[string][]output.Result, links []output.Link, analysis *output.Analysis) *output.Graph {
+	graph := output.NewGraph(allResults, links)
+	for _, match := range intersection.AnalyzeLinkedResults(allResults, links).Matches {
+		graph.SetConfidence(match.Username, match.Confidence)
+	}
+	if analysis != nil {
+		// The searched username's confidence may include name matches
+		graph.SetConfidence(username, analysis.Confidence)
+	}
+	return graph
+}
+
+// recordScan records a scan in the search history
+func recordScan(c *container.Container, username string, results []output.Result, seed bool) error {
+	if seed {
+		if err := c.SeedDatabase(); err != nil {
+			return fmt.Errorf("failed to seed database: %w", err)
+		}
+	}
+
+	var found int
+	for _, result := range results {
+		if result.Exists {
+			found++
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return c.SearchHistoryService.RecordSearch(ctx, username, found)
+}
+
+// runWeb starts the web server and blocks until it is shut down
+func runWeb(options container.Options, port int, seed bool) error {
+	c, err := container.NewContainerWithOptions(options)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if seed {
+		if err := c.SeedDatabase(); err != nil {
+			return fmt.Errorf("failed to seed database: %w", err)
+		}
+	}
+
+	c.WatchlistService.Start()
+	c.WebhookService.Start()
+	return httpserver.NewServer(c, port).Start()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/accio/internal/application/dto"
+	appservice "github.com/accio/internal/application/service"
+	"github.com/accio/internal/identity"
+	"github.com/accio/internal/infrastructure/persistence"
+)
+
+// runResolve runs the resolve subcommand and returns the exit code
+func runResolve(args []string) int {
+	fs := flag.NewFlagSet("resolve", flag.ContinueOnError)
+	threshold := fs.Float64("threshold", identity.DefaultThreshold, "Edge weight at which two profiles are joined into one person")
+	limit := fs.Int("limit", 20, "Number of persons to print, largest first")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := resolve(*threshold, *limit); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// resolve clusters the stored profiles into persons and prints the largest
+func resolve(threshold float64, limit int) error {
+	db, err := persistence.NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if os.Getenv("TURSO_DATABASE_URL") == "" {
+		fmt.Fprintln(os.Stderr, "Warning: TURSO_DATABASE_URL is not set, resolving an empty in-memory database")
+	}
+
+	identityService := appservice.NewIdentityService(
+		persistence.NewGormProfileRepository(db.DB),
+		persistence.NewGormPersonRepository(db.DB),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	result, err := identityService.Resolve(ctx, dto.ResolveIdentitiesDTO{Threshold: threshold})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Resolved %d profiles into %d persons (%d with more than one profile) over %d edges at threshold %.2f\n",
+		result.Profiles, result.Persons, result.Merged, result.Edges, result.Threshold)
+	if limit <= 0 || result.Persons == 0 {
+		return nil
+	}
+
+	persons, _, err := identityService.ListPersons(ctx, limit, 0)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPROFILES")
+	for _, person := range persons {
+		profiles := make([]string, 0, len(person.Profiles))
+		for _, profile := range person.Profiles {
+			profiles = append(profiles, profile.Platform+"/"+profile.Username)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", person.ID, person.Name, strings.Join(profiles, ", "))
+	}
+	return w.Flush()
+}
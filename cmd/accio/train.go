@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	appservice "github.com/accio/internal/application/service"
+	"github.com/accio/internal/infrastructure/persistence"
+	"github.com/accio/internal/intersection"
+)
+
+// runTrain runs the train subcommand and returns the exit code
+func runTrain(args []string) int {
+	fs := flag.NewFlagSet("train", flag.ContinueOnError)
+	outputFile := fs.String("output", intersection.WeightsFile(), "File to write the trained weights to")
+	epochs := fs.Int("epochs", intersection.DefaultEpochs, "Passes of gradient descent over the feedback")
+	learningRate := fs.Float64("learning-rate", intersection.DefaultLearningRate, "Gradient descent step size")
+	l2 := fs.Float64("l2", intersection.DefaultL2, "L2 regularization strength")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := train(*outputFile, intersection.TrainOptions{
+		Epochs:       *epochs,
+		LearningRate: *learningRate,
+		L2:           *l2,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// train fits confidence weights to the stored feedback and saves them
+func train(outputFile string, options intersection.TrainOptions) error {
+	db, err := persistence.NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if os.Getenv("TURSO_DATABASE_URL") == "" {
+		fmt.Fprintln(os.Stderr, "Warning: TURSO_DATABASE_URL is not set, training on an empty in-memory database")
+	}
+
+	trainingService := appservice.NewConfidenceTrainingService(
+		persistence.NewGormUserFeedbackRepository(db.DB),
+		persistence.NewGormProfileRepository(db.DB),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	weights, err := trainingService.Train(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	if err := weights.Save(outputFile); err != nil {
+		return fmt.Errorf("failed to save weights: %w", err)
+	}
+
+	stats := weights.Training
+	fmt.Printf("Trained on %d matches (%d correct, %d incorrect)\n", stats.Samples, stats.Correct, stats.Incorrect)
+	fmt.Printf("Training accuracy %.1f%%, log loss %.4f\n\n", stats.Accuracy*100, stats.LogLoss)
+	printWeights(weights)
+	fmt.Printf("\nWrote %s\n", outputFile)
+	return nil
+}
+
+// printWeights prints the trained weights, largest platform weights first
+func printWeights(weights *intersection.Weights) {
+	fmt.Printf("Weights version %d", weights.Version)
+	if weights.TrainedAt != nil {
+		fmt.Printf(", trained at %s", weights.TrainedAt.Format(time.RFC3339))
+	}
+	fmt.Printf("\n\n")
+
+	platforms := make([]string, 0, len(weights.Platforms))
+	for platform := range weights.Platforms {
+		platforms = append(platforms, platform)
+	}
+	sort.Slice(platforms, func(i, j int) bool {
+		return weights.Platforms[platforms[i]] > weights.Platforms[platforms[j]]
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FEATURE\tWEIGHT")
+	fmt.Fprintf(w, "bias\t%+.4f\n", weights.Bias)
+	fmt.Fprintf(w, "log_match_count\t%+.4f\n", weights.LogMatchCount)
+	fmt.Fprintf(w, "name_match_ratio\t%+.4f\n", weights.NameMatchRatio)
+	fmt.Fprintf(w, "avatar_similarity\t%+.4f\n", weights.AvatarSimilarity)
+	fmt.Fprintf(w, "log_cross_links\t%+.4f\n", weights.LogCrossLinks)
+	for _, platform := range platforms {
+		fmt.Fprintf(w, "platform:%s\t%+.4f\n", platform, weights.Platforms[platform])
+	}
+	w.Flush()
+}
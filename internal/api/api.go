@@ -102,17 +102,15 @@ func SaveImageToFile(imageData io.ReadCloser, filePath string) error {
 	return nil
 }
 
-// GetClientForPlatform returns the appropriate client for a platform
+// GetClientForPlatform returns the appropriate client for a platform. GitHub
+// and Twitch profiles are fetched by the platform clients in
+// internal/infrastructure/api.
 func GetClientForPlatform(platform string) (ProfileClient, error) {
 	switch platform {
 	case "Twitter", "X":
 		return NewTwitterClient()
-	case "Twitch":
-		return NewTwitchClient()
 	case "Instagram":
 		return NewInstagramClient()
-	case "GitHub":
-		return NewGitHubClient()
 	default:
 		return nil, fmt.Errorf("unsupported platform: %s", platform)
 	}
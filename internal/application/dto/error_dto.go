@@ -0,0 +1,13 @@
+package dto
+
+// ErrorDTO represents the error envelope returned by every API endpoint
+type ErrorDTO struct {
+	Error ErrorDetailDTO `json:"error"`
+}
+
+// ErrorDetailDTO describes a single API error
+type ErrorDetailDTO struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// CreateFeedbackDTO represents a request to submit feedback on a profile
+type CreateFeedbackDTO struct {
+	Type    string `json:"type"` // 'correct', 'incorrect' or 'missing'
+	Comment string `json:"comment,omitempty"`
+}
+
+// FeedbackDTO represents a user feedback data transfer object
+type FeedbackDTO struct {
+	ID        uint      `json:"id"`
+	ProfileID uint      `json:"profile_id"`
+	Type      string    `json:"type"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FeedbackListDTO represents a paginated list of feedback entries
+type FeedbackListDTO struct {
+	Feedback []*FeedbackDTO `json:"feedback"`
+	Total    int64          `json:"total"`
+	Limit    int            `json:"limit"`
+	Offset   int            `json:"offset"`
+}
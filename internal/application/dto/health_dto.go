@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// HealthReportDTO represents the result of a liveness or readiness probe
+type HealthReportDTO struct {
+	Status    string           `json:"status"`
+	Checks    []HealthCheckDTO `json:"checks,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// HealthCheckDTO represents the result of a single dependency check
+type HealthCheckDTO struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Critical   bool   `json:"critical"`
+	Message    string `json:"message,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
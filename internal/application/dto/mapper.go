@@ -0,0 +1,211 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/accio/internal/domain/model"
+)
+
+// NewProfileDTO converts a profile entity to a profile data transfer object
+func NewProfileDTO(profile *model.Profile) *ProfileDTO {
+	if profile == nil {
+		return nil
+	}
+
+	profileDTO := &ProfileDTO{
+		ID:            profile.ID,
+		RealName:      profile.RealName,
+		Username:      profile.Username,
+		Platform:      profile.Platform,
+		ProfileURL:    profile.ProfileURL,
+		ImageURL:      profile.ImageURL,
+		Bio:           profile.Bio,
+		Verified:      profile.Verified,
+		FollowerCount: profile.FollowerCount,
+		PersonID:      profile.PersonID,
+		LastUpdated:   profile.LastUpdated,
+	}
+
+	for _, part := range profile.NameParts {
+		profileDTO.NameParts = append(profileDTO.NameParts, NamePartDTO{
+			NamePart: part.NamePart,
+			PartType: part.PartType,
+		})
+	}
+
+	for _, alias := range profile.Aliases {
+		profileDTO.Aliases = append(profileDTO.Aliases, alias.Alias)
+	}
+
+	if len(profile.PlatformData) > 0 {
+		profileDTO.PlatformData = profile.GetPlatformDataMap()
+	}
+
+	return profileDTO
+}
+
+// NewProfileDTOs converts a slice of profile entities to data transfer objects
+func NewProfileDTOs(profiles []*model.Profile) []*ProfileDTO {
+	profileDTOs := make([]*ProfileDTO, 0, len(profiles))
+	for _, profile := range profiles {
+		profileDTOs = append(profileDTOs, NewProfileDTO(profile))
+	}
+	return profileDTOs
+}
+
+// NewSearchHistoryDTO converts a search history entity to a data transfer object
+func NewSearchHistoryDTO(searchHistory *model.SearchHistory) *SearchHistoryDTO {
+	return &SearchHistoryDTO{
+		Query:        searchHistory.Query,
+		SearchCount:  searchHistory.SearchCount,
+		ResultCount:  searchHistory.ResultCount,
+		LastSearched: searchHistory.CreatedAt,
+	}
+}
+
+// NewFeedbackDTO converts a user feedback entity to a data transfer object
+func NewFeedbackDTO(userFeedback *model.UserFeedback) *FeedbackDTO {
+	return &FeedbackDTO{
+		ID:        userFeedback.ID,
+		ProfileID: userFeedback.ProfileID,
+		Type:      userFeedback.FeedbackType,
+		Comment:   userFeedback.Comment,
+		CreatedAt: userFeedback.CreatedAt,
+	}
+}
+
+// NewPersonDTO converts a person entity to a person data transfer object
+func NewPersonDTO(person *model.Person) *PersonDTO {
+	if person == nil {
+		return nil
+	}
+
+	personDTO := &PersonDTO{
+		ID:        person.ID,
+		Name:      person.Name,
+		Profiles:  make([]*ProfileDTO, 0, len(person.Profiles)),
+		CreatedAt: person.CreatedAt,
+		UpdatedAt: person.UpdatedAt,
+	}
+	for i := range person.Profiles {
+		personDTO.Profiles = append(personDTO.Profiles, NewProfileDTO(&person.Profiles[i]))
+	}
+
+	return personDTO
+}
+
+// NewProfileSnapshotDTO converts a profile snapshot entity to a data transfer object
+func NewProfileSnapshotDTO(snapshot *model.ProfileSnapshot) *ProfileSnapshotDTO {
+	return &ProfileSnapshotDTO{
+		ID:            snapshot.ID,
+		ProfileID:     snapshot.ProfileID,
+		RealName:      snapshot.RealName,
+		Bio:           snapshot.Bio,
+		ImageURL:      snapshot.ImageURL,
+		Verified:      snapshot.Verified,
+		FollowerCount: snapshot.FollowerCount,
+		PlatformData:  snapshot.PlatformDataMap(),
+		CapturedAt:    snapshot.CapturedAt,
+	}
+}
+
+// NewProfileFieldChangeDTOs converts profile changes to data transfer objects
+func NewProfileFieldChangeDTOs(changes []model.ProfileChange) []ProfileFieldChangeDTO {
+	changeDTOs := make([]ProfileFieldChangeDTO, 0, len(changes))
+	for _, change := range changes {
+		changeDTOs = append(changeDTOs, ProfileFieldChangeDTO{
+			Field: change.Field,
+			Old:   change.Old,
+			New:   change.New,
+		})
+	}
+	return changeDTOs
+}
+
+// NewWatchlistEntryDTO converts a watchlist entry entity to a data transfer object
+func NewWatchlistEntryDTO(entry *model.WatchlistEntry) *WatchlistEntryDTO {
+	return &WatchlistEntryDTO{
+		ID:        entry.ID,
+		Username:  entry.Username,
+		Sites:     entry.SiteList(),
+		Interval:  entry.Interval.String(),
+		NextRunAt: entry.NextRunAt,
+		LastRunAt: entry.LastRunAt,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+// NewWatchlistSnapshotDTO converts a snapshot entity to a data transfer object
+func NewWatchlistSnapshotDTO(snapshot *model.WatchlistSnapshot) *WatchlistSnapshotDTO {
+	snapshotDTO := &WatchlistSnapshotDTO{
+		ID:        snapshot.ID,
+		EntryID:   snapshot.EntryID,
+		Found:     snapshot.Found,
+		Total:     snapshot.Total,
+		Accounts:  make([]*WatchlistAccountDTO, 0, len(snapshot.Accounts)),
+		CreatedAt: snapshot.CreatedAt,
+	}
+	for _, account := range snapshot.Accounts {
+		snapshotDTO.Accounts = append(snapshotDTO.Accounts, &WatchlistAccountDTO{
+			Site:     account.Site,
+			URL:      account.URL,
+			Exists:   account.Exists,
+			Error:    account.Error,
+			Name:     account.Name,
+			Bio:      account.Bio,
+			ImageURL: account.ImageURL,
+		})
+	}
+
+	return snapshotDTO
+}
+
+// NewWatchlistAlertDTO converts an alert entity to a data transfer object
+func NewWatchlistAlertDTO(alert *model.WatchlistAlert) *WatchlistAlertDTO {
+	return &WatchlistAlertDTO{
+		ID:         alert.ID,
+		EntryID:    alert.EntryID,
+		SnapshotID: alert.SnapshotID,
+		Username:   alert.Username,
+		Kind:       alert.Kind,
+		Site:       alert.Site,
+		URL:        alert.URL,
+		Old:        alert.Old,
+		New:        alert.New,
+		CreatedAt:  alert.CreatedAt,
+	}
+}
+
+// NewWebhookDTO converts a webhook entity to a data transfer object. The
+// secret is left out.
+func NewWebhookDTO(webhook *model.Webhook) *WebhookDTO {
+	return &WebhookDTO{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.EventList(),
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// NewWebhookDeliveryDTO converts a delivery entity to a data transfer object
+func NewWebhookDeliveryDTO(delivery *model.WebhookDelivery) *WebhookDeliveryDTO {
+	deliveryDTO := &WebhookDeliveryDTO{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		Event:          delivery.Event,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == model.DeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		deliveryDTO.NextAttemptAt = &nextAttemptAt
+	}
+
+	return deliveryDTO
+}
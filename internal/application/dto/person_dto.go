@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// PersonDTO represents a person resolved from stored profiles
+type PersonDTO struct {
+	ID        uint             `json:"id"`
+	Name      string           `json:"name"`
+	Profiles  []*ProfileDTO    `json:"profiles"`
+	Edges     []*PersonEdgeDTO `json:"edges,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// PersonEdgeDTO represents the signals connecting two profiles of a person
+type PersonEdgeDTO struct {
+	FromProfileID uint     `json:"from_profile_id"`
+	ToProfileID   uint     `json:"to_profile_id"`
+	Weight        float64  `json:"weight"`
+	Signals       []string `json:"signals"`
+}
+
+// PersonListDTO represents a paginated list of persons
+type PersonListDTO struct {
+	Persons []*PersonDTO `json:"persons"`
+	Total   int64        `json:"total"`
+	Limit   int          `json:"limit"`
+	Offset  int          `json:"offset"`
+}
+
+// ResolveIdentitiesDTO represents a request to resolve stored profiles into persons
+type ResolveIdentitiesDTO struct {
+	Threshold float64 `json:"threshold,omitempty"` // Edge weight joining two profiles; 0 uses the default
+}
+
+// IdentityResolutionDTO summarizes a resolution of profiles into persons
+type IdentityResolutionDTO struct {
+	Profiles  int     `json:"profiles"`  // Profiles in the graph
+	Edges     int     `json:"edges"`     // Edges at or above the threshold
+	Persons   int     `json:"persons"`   // Persons the profiles were clustered into
+	Merged    int     `json:"merged"`    // Persons with more than one profile
+	Threshold float64 `json:"threshold"` // Threshold that was applied
+}
+
+// ResolveJobDTO represents a resolution of profiles into persons running in
+// the background
+type ResolveJobDTO struct {
+	ID         string                 `json:"id"`
+	Status     string                 `json:"status"`
+	Stage      string                 `json:"stage,omitempty"` // Step of a running resolution
+	Threshold  float64                `json:"threshold"`
+	Error      string                 `json:"error,omitempty"`
+	Result     *IdentityResolutionDTO `json:"result,omitempty"` // Summary of a completed resolution
+	CreatedAt  time.Time              `json:"created_at"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+}
@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/accio/internal/output"
+)
+
 // ProfileDTO represents a profile data transfer object
 type ProfileDTO struct {
 	ID            uint              `json:"id,omitempty"`
@@ -14,6 +20,20 @@ type ProfileDTO struct {
 	NameParts     []NamePartDTO     `json:"name_parts,omitempty"`
 	Aliases       []string          `json:"aliases,omitempty"`
 	PlatformData  map[string]string `json:"platform_data,omitempty"`
+	Flagged       bool              `json:"flagged,omitempty"`   // Repeatedly reported as an incorrect match
+	PersonID      *uint             `json:"person_id,omitempty"` // Person the profile was resolved to
+	LastUpdated   time.Time         `json:"last_updated"`        // When the profile was last fetched from its platform
+	Match         *IdentityMatchDTO `json:"match,omitempty"`
+}
+
+// IdentityMatchDTO represents the confidence that the stored profiles sharing
+// a username belong to the same person
+type IdentityMatchDTO struct {
+	Username   string            `json:"username"`
+	Platforms  []string          `json:"platforms"`
+	Confidence float64           `json:"confidence"`
+	Evidence   []output.Evidence `json:"evidence"`
+	Links      []output.Link     `json:"links,omitempty"`
 }
 
 // NamePartDTO represents a name part data transfer object
@@ -21,3 +41,48 @@ type NamePartDTO struct {
 	NamePart string `json:"name_part"`
 	PartType string `json:"part_type"`
 }
+
+// ProfileListDTO represents a paginated list of profiles
+type ProfileListDTO struct {
+	Profiles []*ProfileDTO `json:"profiles"`
+	Total    int64         `json:"total"`
+	Limit    int           `json:"limit"`
+	Offset   int           `json:"offset"`
+}
+
+// ProfileSnapshotDTO represents the state of a profile at one point in time
+type ProfileSnapshotDTO struct {
+	ID            uint                    `json:"id"`
+	ProfileID     uint                    `json:"profile_id"`
+	RealName      string                  `json:"real_name"`
+	Bio           string                  `json:"bio"`
+	ImageURL      string                  `json:"image_url"`
+	Verified      bool                    `json:"verified"`
+	FollowerCount int64                   `json:"follower_count"`
+	PlatformData  map[string]string       `json:"platform_data,omitempty"`
+	CapturedAt    time.Time               `json:"captured_at"`
+	PreviousID    *uint                   `json:"previous_id,omitempty"` // Snapshot before this one, if any
+	Changes       []ProfileFieldChangeDTO `json:"changes,omitempty"`     // Fields changed since the previous snapshot
+}
+
+// ProfileFieldChangeDTO represents a field whose value differs between two snapshots
+type ProfileFieldChangeDTO struct {
+	Field string `json:"field"` // 'real_name', 'bio', 'image_url', 'verified', 'follower_count' or 'platform_data.<key>'
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ProfileSnapshotListDTO represents a paginated list of snapshots
+type ProfileSnapshotListDTO struct {
+	Snapshots []*ProfileSnapshotDTO `json:"snapshots"`
+	Total     int64                 `json:"total"`
+	Limit     int                   `json:"limit"`
+	Offset    int                   `json:"offset"`
+}
+
+// ProfileSnapshotDiffDTO represents the changes between two snapshots of a profile
+type ProfileSnapshotDiffDTO struct {
+	From    *ProfileSnapshotDTO     `json:"from"`
+	To      *ProfileSnapshotDTO     `json:"to"`
+	Changes []ProfileFieldChangeDTO `json:"changes"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/accio/internal/output"
+)
+
+// CreateScanJobDTO represents a request to start a username scan
+type CreateScanJobDTO struct {
+	Username string   `json:"username"`
+	Sites    []string `json:"sites,omitempty"`
+}
+
+// ScanJobDTO represents a scan job data transfer object
+type ScanJobDTO struct {
+	ID         string          `json:"id"`
+	Username   string          `json:"username"`
+	Status     string          `json:"status"`
+	Error      string          `json:"error,omitempty"`
+	Results    []output.Result `json:"results,omitempty"`
+	Stats      ScanStatsDTO    `json:"stats"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+// ScanStatsDTO represents the progress counters of a scan job
+type ScanStatsDTO struct {
+	Total    int `json:"total"`
+	Checked  int `json:"checked"`
+	Found    int `json:"found"`
+	NotFound int `json:"not_found"`
+	Errors   int `json:"errors"`
+}
+
+// ScanEventDTO represents a single event streamed while a scan job runs
+type ScanEventDTO struct {
+	Type   string         `json:"type"`
+	Result *output.Result `json:"result,omitempty"`
+	Job    *ScanJobDTO    `json:"job,omitempty"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// SearchHistoryDTO represents a popular search data transfer object
+type SearchHistoryDTO struct {
+	Query        string    `json:"query"`
+	SearchCount  int64     `json:"search_count"`
+	ResultCount  int       `json:"result_count"`
+	LastSearched time.Time `json:"last_searched"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "github.com/accio/internal/output"
+
+// SearchRequestMessageDTO represents a message sent by a live search client
+type SearchRequestMessageDTO struct {
+	Action    string   `json:"action"` // 'search' or 'cancel'
+	Type      string   `json:"type"`   // 'username' or 'name'
+	Query     string   `json:"query"`
+	Platforms []string `json:"platforms,omitempty"`
+}
+
+// SearchMessageDTO represents a message sent to a live search client
+type SearchMessageDTO struct {
+	Type     string             `json:"type"` // 'started', 'profile', 'site', 'progress', 'error' or 'complete'
+	Query    string             `json:"query,omitempty"`
+	Profile  *ProfileDTO        `json:"profile,omitempty"`
+	Result   *output.Result     `json:"result,omitempty"`
+	Progress *SearchProgressDTO `json:"progress,omitempty"`
+	Error    string             `json:"error,omitempty"`
+}
+
+// SearchProgressDTO represents the progress counters of a live search
+type SearchProgressDTO struct {
+	Total    int `json:"total"`
+	Checked  int `json:"checked"`
+	Profiles int `json:"profiles"`
+	Sites    int `json:"sites"`
+}
@@ -0,0 +1,80 @@
+package dto
+
+import "time"
+
+// CreateWatchlistEntryDTO represents a request to watch a username
+type CreateWatchlistEntryDTO struct {
+	Username string   `json:"username"`
+	Sites    []string `json:"sites,omitempty"`    // Sites to scan; empty scans every site
+	Interval string   `json:"interval,omitempty"` // Time between runs, such as "24h"; empty uses the default
+}
+
+// WatchlistEntryDTO represents a watched username
+type WatchlistEntryDTO struct {
+	ID        uint       `json:"id"`
+	Username  string     `json:"username"`
+	Sites     []string   `json:"sites,omitempty"`
+	Interval  string     `json:"interval"`
+	NextRunAt time.Time  `json:"next_run_at"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// WatchlistEntryListDTO represents a paginated list of watchlist entries
+type WatchlistEntryListDTO struct {
+	Entries []*WatchlistEntryDTO `json:"entries"`
+	Total   int64                `json:"total"`
+	Limit   int                  `json:"limit"`
+	Offset  int                  `json:"offset"`
+}
+
+// WatchlistSnapshotDTO represents the state of a watched username after one run
+type WatchlistSnapshotDTO struct {
+	ID        uint                   `json:"id"`
+	EntryID   uint                   `json:"entry_id"`
+	Found     int                    `json:"found"`
+	Total     int                    `json:"total"`
+	Accounts  []*WatchlistAccountDTO `json:"accounts"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// WatchlistAccountDTO represents a site checked in a snapshot
+type WatchlistAccountDTO struct {
+	Site     string `json:"site"`
+	URL      string `json:"url"`
+	Exists   bool   `json:"exists"`
+	Error    string `json:"error,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Bio      string `json:"bio,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// WatchlistSnapshotListDTO represents a paginated list of snapshots
+type WatchlistSnapshotListDTO struct {
+	Snapshots []*WatchlistSnapshotDTO `json:"snapshots"`
+	Total     int64                   `json:"total"`
+	Limit     int                     `json:"limit"`
+	Offset    int                     `json:"offset"`
+}
+
+// WatchlistAlertDTO represents a change found between two snapshots
+type WatchlistAlertDTO struct {
+	ID         uint      `json:"id"`
+	EntryID    uint      `json:"entry_id"`
+	SnapshotID uint      `json:"snapshot_id"`
+	Username   string    `json:"username"`
+	Kind       string    `json:"kind"` // 'appeared', 'disappeared', 'name_changed', 'bio_changed' or 'avatar_changed'
+	Site       string    `json:"site"`
+	URL        string    `json:"url"`
+	Old        string    `json:"old,omitempty"`
+	New        string    `json:"new,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WatchlistAlertListDTO represents a paginated list of alerts
+type WatchlistAlertListDTO struct {
+	Alerts []*WatchlistAlertDTO `json:"alerts"`
+	Total  int64                `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/accio/internal/output"
+)
+
+// CreateWebhookDTO represents a request to create a webhook
+type CreateWebhookDTO struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"` // Signing key; empty generates one
+	Events []string `json:"events"`
+}
+
+// WebhookDTO represents a webhook
+type WebhookDTO struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // Only returned when the webhook is created
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookListDTO represents a paginated list of webhooks
+type WebhookListDTO struct {
+	Webhooks []*WebhookDTO `json:"webhooks"`
+	Total    int64         `json:"total"`
+	Limit    int           `json:"limit"`
+	Offset   int           `json:"offset"`
+}
+
+// WebhookDeliveryDTO represents an event sent, or waiting to be sent, to a webhook
+type WebhookDeliveryDTO struct {
+	ID             uint            `json:"id"`
+	WebhookID      uint            `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"` // 'pending', 'succeeded' or 'failed'
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// WebhookDeliveryListDTO represents a paginated list of deliveries
+type WebhookDeliveryListDTO struct {
+	Deliveries []*WebhookDeliveryDTO `json:"deliveries"`
+	Total      int64                 `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+}
+
+// WebhookEventDTO is the JSON body posted to webhooks
+type WebhookEventDTO struct {
+	ID        string      `json:"id"` // Same for every retry and replay of the event
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// AccountFoundEventDTO is the data of an account.found event
+type AccountFoundEventDTO struct {
+	Username         string `json:"username"`
+	Site             string `json:"site"`
+	URL              string `json:"url"`
+	ScanID           string `json:"scan_id,omitempty"`            // Set when found by a scan job
+	WatchlistEntryID uint   `json:"watchlist_entry_id,omitempty"` // Set when found by a watchlist run
+}
+
+// ScanCompletedEventDTO is the data of a scan.completed event
+type ScanCompletedEventDTO struct {
+	Scan  *ScanJobDTO     `json:"scan"`
+	Found []output.Result `json:"found"` // Accounts found by the scan
+}
+
+// WatchlistChangedEventDTO is the data of a watchlist.changed event
+type WatchlistChangedEventDTO struct {
+	Entry      *WatchlistEntryDTO   `json:"entry"`
+	SnapshotID uint                 `json:"snapshot_id"`
+	Alerts     []*WatchlistAlertDTO `json:"alerts"`
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+)
+
+// apiKeyPrefix marks accio API keys, e.g. accio_1a2b3c4d_<secret>
+const apiKeyPrefix = "accio_"
+
+// API key errors
+var (
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrQuotaExceeded  = errors.New("daily quota exceeded")
+	ErrInvalidScope   = errors.New("invalid scope")
+)
+
+// APIKeyService defines the interface for API key operations
+type APIKeyService interface {
+	// CreateKey creates a key and returns it in plain text. The plain text key
+	// is never stored and cannot be retrieved later.
+	CreateKey(ctx context.Context, name string, scopes []string, rateLimit, dailyQuota int) (string, *model.APIKey, error)
+
+	// Authenticate returns the key matching a plain text key
+	Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error)
+
+	// ConsumeQuota records a request made with a key and returns the number of
+	// requests left today, or -1 if the key has no daily quota
+	ConsumeQuota(ctx context.Context, apiKey *model.APIKey) (int, error)
+
+	// ListKeys lists all keys
+	ListKeys(ctx context.Context) ([]*model.APIKey, error)
+
+	// RevokeKey revokes a key
+	RevokeKey(ctx context.Context, id uint) error
+}
+
+// APIKeyServiceImpl implements the APIKeyService interface
+type APIKeyServiceImpl struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewAPIKeyService creates a new APIKeyServiceImpl
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) APIKeyService {
+	return &APIKeyServiceImpl{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// CreateKey creates a key and returns it in plain text
+func (s *APIKeyServiceImpl) CreateKey(ctx context.Context, name string, scopes []string, rateLimit, dailyQuota int) (string, *model.APIKey, error) {
+	for _, scope := range scopes {
+		if scope != model.ScopeRead && scope != model.ScopeScan {
+			return "", nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	prefix, err := randomHex(4)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rawKey := apiKeyPrefix + prefix + "_" + secret
+	apiKey := model.NewAPIKey(name, prefix, hashAPIKey(rawKey), scopes, rateLimit, dailyQuota)
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return "", nil, err
+	}
+
+	return rawKey, apiKey, nil
+}
+
+// Authenticate returns the key matching a plain text key
+func (s *APIKeyServiceImpl) Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error) {
+	prefix, ok := parseAPIKeyPrefix(rawKey)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepo.FindByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey == nil || apiKey.IsRevoked() ||
+		subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(hashAPIKey(rawKey))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return apiKey, nil
+}
+
+// ConsumeQuota records a request made with a key and returns the number of requests left today
+func (s *APIKeyServiceImpl) ConsumeQuota(ctx context.Context, apiKey *model.APIKey) (int, error) {
+	count, err := s.apiKeyRepo.IncrementUsage(ctx, apiKey.ID, time.Now().UTC().Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID); err != nil {
+		return 0, err
+	}
+
+	if apiKey.DailyQuota <= 0 {
+		return -1, nil
+	}
+
+	if count > apiKey.DailyQuota {
+		return 0, ErrQuotaExceeded
+	}
+
+	return apiKey.DailyQuota - count, nil
+}
+
+// ListKeys lists all keys
+func (s *APIKeyServiceImpl) ListKeys(ctx context.Context) ([]*model.APIKey, error) {
+	return s.apiKeyRepo.FindAll(ctx)
+}
+
+// RevokeKey revokes a key
+func (s *APIKeyServiceImpl) RevokeKey(ctx context.Context, id uint) error {
+	apiKey, err := s.apiKeyRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if apiKey == nil {
+		return ErrAPIKeyNotFound
+	}
+
+	return s.apiKeyRepo.Revoke(ctx, id)
+}
+
+// parseAPIKeyPrefix extracts the public prefix from a plain text key
+func parseAPIKeyPrefix(rawKey string) (string, bool) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return "", false
+	}
+
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(rawKey, apiKeyPrefix), "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", false
+	}
+
+	return prefix, true
+}
+
+// hashAPIKey hashes a plain text key. Keys are long random strings, so a
+// fast hash is sufficient.
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomHex returns n random bytes encoded as hex
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/accio/internal/crosslink"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+	"github.com/accio/internal/image"
+	"github.com/accio/internal/intersection"
+	"github.com/accio/internal/matcher"
+	"github.com/accio/internal/output"
+	"github.com/accio/internal/sites"
+)
+
+// feedbackPageSize is the number of feedback entries read at a time when
+// collecting training samples
+const feedbackPageSize = 500
+
+// ConfidenceTrainingService defines the interface for training the
+// intersection confidence model from user feedback
+type ConfidenceTrainingService interface {
+	// CollectSamples turns every "correct" and "incorrect" feedback entry into
+	// a labelled match of the reported profile's username across platforms
+	CollectSamples(ctx context.Context) ([]intersection.Sample, error)
+
+	// Train collects samples and fits confidence weights to them
+	Train(ctx context.Context, options intersection.TrainOptions) (*intersection.Weights, error)
+}
+
+// ConfidenceTrainingServiceImpl implements the ConfidenceTrainingService interface
+type ConfidenceTrainingServiceImpl struct {
+	feedbackRepo repository.UserFeedbackRepository
+	profileRepo  repository.ProfileRepository
+}
+
+// NewConfidenceTrainingService creates a new ConfidenceTrainingServiceImpl
+func NewConfidenceTrainingService(feedbackRepo repository.UserFeedbackRepository, profileRepo repository.ProfileRepository) ConfidenceTrainingService {
+	return &ConfidenceTrainingServiceImpl{
+		feedbackRepo: feedbackRepo,
+		profileRepo:  profileRepo,
+	}
+}
+
+// CollectSamples builds labelled matches from stored feedback
+func (s *ConfidenceTrainingServiceImpl) CollectSamples(ctx context.Context) ([]intersection.Sample, error) {
+	profiles := make(map[uint]*model.Profile)
+	siblings := make(map[string][]*model.Profile)
+
+	var samples []intersection.Sample
+	for _, feedbackType := range []string{model.FeedbackCorrect, model.FeedbackIncorrect} {
+		for offset := 0; ; offset += feedbackPageSize {
+			entries, err := s.feedbackRepo.FindAll(ctx, feedbackType, feedbackPageSize, offset)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, entry := range entries {
+				profile, ok := profiles[entry.ProfileID]
+				if !ok {
+					if profile, err = s.profileRepo.FindByID(ctx, entry.ProfileID); err != nil {
+						return nil, err
+					}
+					profiles[entry.ProfileID] = profile
+				}
+				if profile == nil {
+					// The profile was deleted after the feedback was given
+					continue
+				}
+
+				key := strings.ToLower(profile.Username)
+				same, ok := siblings[key]
+				if !ok {
+					if same, err = s.profileRepo.FindAllByUsername(ctx, profile.Username); err != nil {
+						return nil, err
+					}
+					siblings[key] = same
+				}
+
+				samples = append(samples, intersection.Sample{
+					Match:   newStoredMatch(profile, same),
+					Correct: entry.FeedbackType == model.FeedbackCorrect,
+				})
+			}
+
+			if len(entries) < feedbackPageSize {
+				break
+			}
+		}
+	}
+
+	return samples, nil
+}
+
+// Train collects samples and fits confidence weights to them
+func (s *ConfidenceTrainingServiceImpl) Train(ctx context.Context, options intersection.TrainOptions) (*intersection.Weights, error) {
+	samples, err := s.CollectSamples(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return intersection.Train(samples, options)
+}
+
+// storedLinkExtractor finds links between stored profiles
+var storedLinkExtractor = crosslink.NewExtractor(sites.GetSites())
+
+// newStoredMatch builds the match a scan of the profile's username would
+// have produced from the stored profiles sharing that username, with name
+// matches against the real name of the given profile, the similarity of the
+// hashed profile pictures and the links between the profiles found in their
+// platform data and bios.
+func newStoredMatch(profile *model.Profile, same []*model.Profile) intersection.ProfileMatch {
+	match := intersection.ProfileMatch{
+		Username:    profile.Username,
+		NameMatches: make(map[string]bool),
+	}
+
+	name := matcher.ParseFullName(profile.RealName)
+	var (
+		links  []output.Link
+		hashes []uint64
+	)
+	for _, other := range same {
+		if hash, err := image.ParseHash(other.ImageHash); err == nil && other.ImageHash != "" {
+			hashes = append(hashes, hash)
+		}
+		match.Results = append(match.Results, output.Result{
+			Site:   other.Platform,
+			URL:    other.ProfileURL,
+			Exists: true,
+		})
+		if other.ID != profile.ID && name.MatchesName(other.RealName) {
+			match.NameMatches[other.Platform] = true
+		}
+
+		links = append(links, crosslink.Links(other.Platform, other.Username, storedLinkExtractor.FromPlatformData(other.GetPlatformDataMap()), crosslink.SourcePlatformData)...)
+		links = append(links, crosslink.Links(other.Platform, other.Username, storedLinkExtractor.FromText(other.Bio), crosslink.SourceBio)...)
+	}
+	match.MatchCount = len(match.Results)
+	match.AvatarSimilarity = intersection.AvatarSimilarity(hashes)
+
+	// The profile's own name only counts once another profile confirms it
+	if len(match.NameMatches) > 0 {
+		match.NameMatches[profile.Platform] = true
+	}
+
+	matches := []intersection.ProfileMatch{match}
+	intersection.LinkMatches(matches, links)
+	return matches[0]
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/accio/internal/domain/model"
+)
+
+func TestNewStoredMatchAvatarSimilarity(t *testing.T) {
+	profile := &model.Profile{ID: 1, Username: "johndoe", Platform: "GitHub", ImageHash: "00000000000000ff"}
+	same := []*model.Profile{
+		profile,
+		{ID: 2, Username: "johndoe", Platform: "Twitter", ImageHash: "00000000000000ff"},
+		{ID: 3, Username: "johndoe", Platform: "Twitch"},
+	}
+
+	match := newStoredMatch(profile, same)
+	if match.AvatarSimilarity != 1 {
+		t.Errorf("Expected identical pictures to be fully similar, got %f", match.AvatarSimilarity)
+	}
+
+	// Profiles without hashed pictures are not compared
+	match = newStoredMatch(profile, same[:1])
+	if match.AvatarSimilarity != 0 {
+		t.Errorf("Expected no similarity from a single picture, got %f", match.AvatarSimilarity)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+)
+
+// Feedback limits
+const (
+	maxFeedbackCommentLength = 1000
+
+	// flagIncorrectThreshold is the number of "incorrect" reports, outnumbering
+	// the "correct" ones, after which a profile is flagged. Each API key
+	// reports at most once per profile.
+	flagIncorrectThreshold = 2
+)
+
+// Feedback errors
+var (
+	ErrProfileNotFound = errors.New("profile not found")
+	ErrInvalidFeedback = errors.New("invalid feedback")
+)
+
+// FeedbackService defines the interface for user feedback operations
+type FeedbackService interface {
+	// SubmitFeedback records feedback on a stored profile from an API key, or
+	// from anyone if the key ID is 0. A key has one say per profile: feedback
+	// it submits again replaces its earlier feedback.
+	SubmitFeedback(ctx context.Context, profileID, apiKeyID uint, request dto.CreateFeedbackDTO) (*dto.FeedbackDTO, error)
+
+	// ListFeedback lists feedback entries of a type, or of all types if the
+	// type is empty, along with the total count
+	ListFeedback(ctx context.Context, feedbackType string, limit, offset int) ([]*dto.FeedbackDTO, int64, error)
+}
+
+// FeedbackServiceImpl implements the FeedbackService interface
+type FeedbackServiceImpl struct {
+	feedbackRepo repository.UserFeedbackRepository
+	profileRepo  repository.ProfileRepository
+}
+
+// NewFeedbackService creates a new FeedbackServiceImpl
+func NewFeedbackService(feedbackRepo repository.UserFeedbackRepository, profileRepo repository.ProfileRepository) FeedbackService {
+	return &FeedbackServiceImpl{
+		feedbackRepo: feedbackRepo,
+		profileRepo:  profileRepo,
+	}
+}
+
+// SubmitFeedback records feedback on a stored profile, replacing the earlier
+// feedback of the same API key
+func (s *FeedbackServiceImpl) SubmitFeedback(ctx context.Context, profileID, apiKeyID uint, request dto.CreateFeedbackDTO) (*dto.FeedbackDTO, error) {
+	if !model.IsValidFeedbackType(request.Type) {
+		return nil, fmt.Errorf("%w: type must be correct, incorrect or missing", ErrInvalidFeedback)
+	}
+
+	comment := strings.TrimSpace(request.Comment)
+	if utf8.RuneCountInString(comment) > maxFeedbackCommentLength {
+		return nil, fmt.Errorf("%w: comment must be at most %d characters", ErrInvalidFeedback, maxFeedbackCommentLength)
+	}
+
+	profile, err := s.profileRepo.FindByID(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrProfileNotFound
+	}
+
+	// Without authentication there is no key to count feedback by
+	if apiKeyID != 0 {
+		existing, err := s.feedbackRepo.FindByProfileAndAPIKey(ctx, profileID, apiKeyID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			existing.FeedbackType = request.Type
+			existing.Comment = comment
+			existing.CreatedAt = time.Now()
+			if err := s.feedbackRepo.Update(ctx, existing); err != nil {
+				return nil, err
+			}
+			return dto.NewFeedbackDTO(existing), nil
+		}
+	}
+
+	userFeedback := model.NewUserFeedback(profileID, apiKeyID, request.Type, comment)
+	if err := s.feedbackRepo.Create(ctx, userFeedback); err != nil {
+		return nil, err
+	}
+
+	return dto.NewFeedbackDTO(userFeedback), nil
+}
+
+// ListFeedback lists feedback entries of a type along with the total count
+func (s *FeedbackServiceImpl) ListFeedback(ctx context.Context, feedbackType string, limit, offset int) ([]*dto.FeedbackDTO, int64, error) {
+	if feedbackType != "" && !model.IsValidFeedbackType(feedbackType) {
+		return nil, 0, fmt.Errorf("%w: type must be correct, incorrect or missing", ErrInvalidFeedback)
+	}
+
+	total, err := s.feedbackRepo.Count(ctx, feedbackType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries, err := s.feedbackRepo.FindAll(ctx, feedbackType, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	feedbackDTOs := make([]*dto.FeedbackDTO, 0, len(entries))
+	for _, entry := range entries {
+		feedbackDTOs = append(feedbackDTOs, dto.NewFeedbackDTO(entry))
+	}
+
+	return feedbackDTOs, total, nil
+}
+
+// findFlaggedProfiles returns the IDs of the profiles repeatedly reported as
+// incorrect matches
+func findFlaggedProfiles(ctx context.Context, feedbackRepo repository.UserFeedbackRepository, profiles []*model.Profile) (map[uint]bool, error) {
+	ids := make([]uint, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.ID != 0 {
+			ids = append(ids, profile.ID)
+		}
+	}
+
+	counts, err := feedbackRepo.CountByProfileIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	correct := make(map[uint]int64)
+	incorrect := make(map[uint]int64)
+	for _, count := range counts {
+		switch count.FeedbackType {
+		case model.FeedbackCorrect:
+			correct[count.ProfileID] = count.Count
+		case model.FeedbackIncorrect:
+			incorrect[count.ProfileID] = count.Count
+		}
+	}
+
+	flagged := make(map[uint]bool)
+	for id, n := range incorrect {
+		if n >= flagIncorrectThreshold && n > correct[id] {
+			flagged[id] = true
+		}
+	}
+
+	return flagged, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+)
+
+// memoryFeedbackRepository keeps feedback in memory
+type memoryFeedbackRepository struct {
+	repository.UserFeedbackRepository
+	entries []*model.UserFeedback
+}
+
+func (r *memoryFeedbackRepository) Create(ctx context.Context, userFeedback *model.UserFeedback) error {
+	userFeedback.ID = uint(len(r.entries) + 1)
+	r.entries = append(r.entries, userFeedback)
+	return nil
+}
+
+func (r *memoryFeedbackRepository) Update(ctx context.Context, userFeedback *model.UserFeedback) error {
+	r.entries[userFeedback.ID-1] = userFeedback
+	return nil
+}
+
+func (r *memoryFeedbackRepository) FindByProfileAndAPIKey(ctx context.Context, profileID, apiKeyID uint) (*model.UserFeedback, error) {
+	for _, entry := range r.entries {
+		if entry.ProfileID == profileID && entry.APIKeyID == apiKeyID {
+			copied := *entry
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryFeedbackRepository) CountByProfileIDs(ctx context.Context, profileIDs []uint) ([]repository.FeedbackCount, error) {
+	counts := make(map[repository.FeedbackCount]int64)
+	for _, entry := range r.entries {
+		for _, id := range profileIDs {
+			if entry.ProfileID == id {
+				counts[repository.FeedbackCount{ProfileID: id, FeedbackType: entry.FeedbackType}]++
+			}
+		}
+	}
+
+	var result []repository.FeedbackCount
+	for count, n := range counts {
+		count.Count = n
+		result = append(result, count)
+	}
+	return result, nil
+}
+
+// memoryProfileRepository finds profiles by ID in memory
+type memoryProfileRepository struct {
+	repository.ProfileRepository
+	profiles map[uint]*model.Profile
+}
+
+func (r *memoryProfileRepository) FindByID(ctx context.Context, id uint) (*model.Profile, error) {
+	return r.profiles[id], nil
+}
+
+func TestSubmitFeedbackFlagsProfile(t *testing.T) {
+	profile := &model.Profile{ID: 1, Username: "johndoe", Platform: "GitHub", RealName: "John Doe"}
+	feedbackRepo := &memoryFeedbackRepository{}
+	profileRepo := &memoryProfileRepository{profiles: map[uint]*model.Profile{1: profile}}
+	s := NewFeedbackService(feedbackRepo, profileRepo)
+
+	steps := []struct {
+		name     string
+		apiKeyID uint
+		feedback string
+		entries  int
+		flagged  bool
+	}{
+		{"one report is below the threshold", 1, model.FeedbackIncorrect, 1, false},
+		{"a key reporting again replaces its report", 1, model.FeedbackIncorrect, 1, false},
+		{"a second key reaches the threshold", 2, model.FeedbackIncorrect, 2, true},
+		{"fewer correct reports keep the flag", 3, model.FeedbackCorrect, 3, true},
+		{"as many correct reports lift the flag", 4, model.FeedbackCorrect, 4, false},
+		{"a key changing its mind flags again", 4, model.FeedbackIncorrect, 4, true},
+	}
+
+	for _, step := range steps {
+		request := dto.CreateFeedbackDTO{Type: step.feedback}
+		if _, err := s.SubmitFeedback(context.Background(), profile.ID, step.apiKeyID, request); err != nil {
+			t.Fatalf("%s: SubmitFeedback() error: %v", step.name, err)
+		}
+		if len(feedbackRepo.entries) != step.entries {
+			t.Errorf("%s: expected %d feedback entries, got %d", step.name, step.entries, len(feedbackRepo.entries))
+		}
+
+		flagged, err := findFlaggedProfiles(context.Background(), feedbackRepo, []*model.Profile{profile})
+		if err != nil {
+			t.Fatalf("%s: findFlaggedProfiles() error: %v", step.name, err)
+		}
+		if flagged[profile.ID] != step.flagged {
+			t.Errorf("%s: expected flagged %v, got %v", step.name, step.flagged, flagged[profile.ID])
+		}
+	}
+}
+
+func TestSubmitFeedbackWithoutAuthentication(t *testing.T) {
+	profile := &model.Profile{ID: 1, Username: "johndoe", Platform: "GitHub"}
+	feedbackRepo := &memoryFeedbackRepository{}
+	s := NewFeedbackService(feedbackRepo, &memoryProfileRepository{profiles: map[uint]*model.Profile{1: profile}})
+
+	// Without a key every report counts on its own
+	for i := 0; i < flagIncorrectThreshold; i++ {
+		if _, err := s.SubmitFeedback(context.Background(), profile.ID, 0, dto.CreateFeedbackDTO{Type: model.FeedbackIncorrect}); err != nil {
+			t.Fatalf("SubmitFeedback() error: %v", err)
+		}
+	}
+
+	flagged, err := findFlaggedProfiles(context.Background(), feedbackRepo, []*model.Profile{profile})
+	if err != nil {
+		t.Fatalf("findFlaggedProfiles() error: %v", err)
+	}
+	if !flagged[profile.ID] {
+		t.Errorf("Expected %d anonymous reports to flag the profile", flagIncorrectThreshold)
+	}
+}
+
+func TestSubmitFeedbackRejectsInvalidRequests(t *testing.T) {
+	s := NewFeedbackService(&memoryFeedbackRepository{}, &memoryProfileRepository{profiles: map[uint]*model.Profile{1: {ID: 1}}})
+
+	testCases := []struct {
+		name      string
+		profileID uint
+		feedback  string
+		expected  error
+	}{
+		{"unknown type", 1, "spam", ErrInvalidFeedback},
+		{"unknown profile", 2, model.FeedbackCorrect, ErrProfileNotFound},
+	}
+
+	for _, tc := range testCases {
+		_, err := s.SubmitFeedback(context.Background(), tc.profileID, 1, dto.CreateFeedbackDTO{Type: tc.feedback})
+		if !errors.Is(err, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, err)
+		}
+	}
+}
+
+func TestRankedProfilesDemoteFlagged(t *testing.T) {
+	feedbackRepo := &memoryFeedbackRepository{}
+	s := NewProfileService(DefaultProfileServiceConfig(), nil, feedbackRepo).(*ProfileServiceImpl)
+
+	profiles := []*model.Profile{
+		{ID: 1, RealName: "John Doe", Verified: true, FollowerCount: 1000},
+		{ID: 2, RealName: "Johnny Doe"},
+		{ID: 3, RealName: "John Doe"},
+	}
+	for apiKeyID := uint(1); apiKeyID <= flagIncorrectThreshold; apiKeyID++ {
+		feedbackRepo.Create(context.Background(), model.NewUserFeedback(1, apiKeyID, model.FeedbackIncorrect, ""))
+	}
+	feedbackRepo.Create(context.Background(), model.NewUserFeedback(3, 1, model.FeedbackIncorrect, ""))
+
+	ranked, err := s.rankedProfileDTOs(context.Background(), "John Doe", profiles)
+	if err != nil {
+		t.Fatalf("rankedProfileDTOs() error: %v", err)
+	}
+
+	expected := []struct {
+		id      uint
+		flagged bool
+	}{{3, false}, {2, false}, {1, true}}
+	for i, want := range expected {
+		if ranked[i].ID != want.id || ranked[i].Flagged != want.flagged {
+			t.Errorf("Position %d: expected profile %d flagged %v, got %d flagged %v", i, want.id, want.flagged, ranked[i].ID, ranked[i].Flagged)
+		}
+	}
+}
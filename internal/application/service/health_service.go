@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/accio/internal/application/dto"
+)
+
+// Health statuses
+const (
+	HealthOK       = "ok"       // Every check passed
+	HealthDegraded = "degraded" // Only non-critical checks failed
+	HealthFail     = "fail"     // A critical check failed
+	HealthWarn     = "warn"     // A non-critical check failed
+)
+
+// healthCheckTimeout bounds each readiness check
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck is a named dependency check run by the readiness probe
+type HealthCheck struct {
+	Name     string
+	Critical bool // A failing critical check makes the service not ready
+	Check    func(ctx context.Context) error
+}
+
+// HealthService defines the interface for liveness and readiness probes
+type HealthService interface {
+	// Liveness reports whether the process is running. It checks no
+	// dependencies, so an outage never gets healthy instances restarted.
+	Liveness(ctx context.Context) *dto.HealthReportDTO
+
+	// Readiness runs every dependency check concurrently
+	Readiness(ctx context.Context) *dto.HealthReportDTO
+}
+
+// HealthServiceImpl implements the HealthService interface
+type HealthServiceImpl struct {
+	checks []HealthCheck
+}
+
+// NewHealthService creates a new HealthServiceImpl
+func NewHealthService(checks []HealthCheck) HealthService {
+	return &HealthServiceImpl{
+		checks: checks,
+	}
+}
+
+// Liveness reports whether the process is running
+func (s *HealthServiceImpl) Liveness(ctx context.Context) *dto.HealthReportDTO {
+	return &dto.HealthReportDTO{
+		Status:    HealthOK,
+		Timestamp: time.Now(),
+	}
+}
+
+// Readiness runs every dependency check concurrently
+func (s *HealthServiceImpl) Readiness(ctx context.Context) *dto.HealthReportDTO {
+	results := make([]dto.HealthCheckDTO, len(s.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			results[i] = runHealthCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	status := HealthOK
+	for _, result := range results {
+		switch result.Status {
+		case HealthFail:
+			status = HealthFail
+		case HealthWarn:
+			if status == HealthOK {
+				status = HealthDegraded
+			}
+		}
+	}
+
+	return &dto.HealthReportDTO{
+		Status:    status,
+		Checks:    results,
+		Timestamp: time.Now(),
+	}
+}
+
+// runHealthCheck runs a single check with a timeout
+func runHealthCheck(ctx context.Context, check HealthCheck) dto.HealthCheckDTO {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+
+	result := dto.HealthCheckDTO{
+		Name:       check.Name,
+		Status:     HealthOK,
+		Critical:   check.Critical,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = HealthWarn
+		if check.Critical {
+			result.Status = HealthFail
+		}
+		result.Message = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubCheck returns a health check that fails with err, if not nil
+func stubCheck(name string, critical bool, err error) HealthCheck {
+	return HealthCheck{
+		Name:     name,
+		Critical: critical,
+		Check: func(ctx context.Context) error {
+			return err
+		},
+	}
+}
+
+func TestHealthReadiness(t *testing.T) {
+	down := errors.New("connection refused")
+
+	testCases := []struct {
+		name     string
+		checks   []HealthCheck
+		status   string
+		statuses []string
+	}{
+		{
+			name:   "no checks",
+			status: HealthOK,
+		},
+		{
+			name:     "all pass",
+			checks:   []HealthCheck{stubCheck("database", true, nil), stubCheck("image_cache", false, nil)},
+			status:   HealthOK,
+			statuses: []string{HealthOK, HealthOK},
+		},
+		{
+			name:     "non-critical fails",
+			checks:   []HealthCheck{stubCheck("database", true, nil), stubCheck("image_cache", false, down)},
+			status:   HealthDegraded,
+			statuses: []string{HealthOK, HealthWarn},
+		},
+		{
+			name:     "critical fails, non-critical passes",
+			checks:   []HealthCheck{stubCheck("database", true, down), stubCheck("image_cache", false, nil)},
+			status:   HealthFail,
+			statuses: []string{HealthFail, HealthOK},
+		},
+		{
+			name:     "critical and non-critical fail",
+			checks:   []HealthCheck{stubCheck("image_cache", false, down), stubCheck("database", true, down)},
+			status:   HealthFail,
+			statuses: []string{HealthWarn, HealthFail},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := NewHealthService(tc.checks).Readiness(context.Background())
+			if report.Status != tc.status {
+				t.Errorf("Expected status %s, got %s", tc.status, report.Status)
+			}
+			if len(report.Checks) != len(tc.checks) {
+				t.Fatalf("Expected %d check results, got %d", len(tc.checks), len(report.Checks))
+			}
+			for i, result := range report.Checks {
+				if result.Name != tc.checks[i].Name || result.Status != tc.statuses[i] {
+					t.Errorf("Check %d: expected %s %s, got %s %s", i, tc.checks[i].Name, tc.statuses[i], result.Name, result.Status)
+				}
+				if (result.Status != HealthOK) != (result.Message == down.Error()) {
+					t.Errorf("Check %s: unexpected message %q", result.Name, result.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthReadinessTimesOutChecks(t *testing.T) {
+	slow := HealthCheck{
+		Name:     "platform:Twitter",
+		Critical: false,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := NewHealthService([]HealthCheck{slow}).Readiness(ctx)
+	if report.Status != HealthDegraded || report.Checks[0].Status != HealthWarn {
+		t.Errorf("Expected a degraded report with a warning, got %+v", report)
+	}
+}
+
+func TestHealthLiveness(t *testing.T) {
+	failing := []HealthCheck{stubCheck("database", true, errors.New("down"))}
+	report := NewHealthService(failing).Liveness(context.Background())
+	if report.Status != HealthOK || len(report.Checks) != 0 {
+		t.Errorf("Expected liveness to pass without running checks, got %+v", report)
+	}
+}
@@ -0,0 +1,514 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+	"github.com/accio/internal/identity"
+	"github.com/accio/internal/image"
+	"github.com/accio/internal/infrastructure/api"
+)
+
+// Identity resolution settings
+const (
+	// profilePageSize is the number of profiles loaded at a time
+	profilePageSize = 500
+
+	// avatarConcurrency is the number of profile pictures downloaded in parallel
+	avatarConcurrency = 4
+
+	// resolveJobTimeout bounds a resolution running in the background
+	resolveJobTimeout = 30 * time.Minute
+
+	// resolveJobRetention is how long finished resolution jobs are kept
+	resolveJobRetention = time.Hour
+)
+
+// Resolution job statuses
+const (
+	ResolveJobRunning   = "running"
+	ResolveJobCompleted = "completed"
+	ResolveJobFailed    = "failed"
+	ResolveJobCancelled = "cancelled"
+)
+
+// Resolution stages
+const (
+	ResolveStageLoading    = "loading_profiles"
+	ResolveStageHashing    = "hashing_avatars"
+	ResolveStageClustering = "clustering"
+	ResolveStageSaving     = "saving_persons"
+)
+
+// Identity errors
+var (
+	ErrPersonNotFound     = errors.New("person not found")
+	ErrInvalidThreshold   = errors.New("invalid threshold")
+	ErrResolveJobNotFound = errors.New("resolution job not found")
+	ErrResolveInProgress  = errors.New("a resolution is already running")
+	ErrResolveStopped     = errors.New("identity service is stopped")
+)
+
+// IdentityService defines the interface for resolving stored profiles into persons
+type IdentityService interface {
+	// Resolve builds the identity graph of every stored profile, clusters it
+	// and replaces the stored persons with the clusters. Persons keep their
+	// ID as long as most of their profiles stay together.
+	Resolve(ctx context.Context, request dto.ResolveIdentitiesDTO) (*dto.IdentityResolutionDTO, error)
+
+	// StartResolve validates a resolution request and runs it in the
+	// background. Only one resolution runs at a time.
+	StartResolve(ctx context.Context, request dto.ResolveIdentitiesDTO) (*dto.ResolveJobDTO, error)
+
+	// GetResolveJob gets a background resolution with its result once completed
+	GetResolveJob(ctx context.Context, id string) (*dto.ResolveJobDTO, error)
+
+	// GetPerson gets a person with its profiles and the edges between them
+	GetPerson(ctx context.Context, id uint) (*dto.PersonDTO, error)
+
+	// ListPersons lists persons, largest first, along with the total count
+	ListPersons(ctx context.Context, limit, offset int) ([]*dto.PersonDTO, int64, error)
+
+	// HashAvatars downloads and hashes the pictures of stored profiles that
+	// have none hashed yet, and stores the hashes for later resolutions
+	HashAvatars(ctx context.Context, profiles []*model.Profile)
+
+	// Stop cancels a background resolution and waits for it to exit
+	Stop()
+}
+
+// resolveJob is the in-memory state of a background resolution
+type resolveJob struct {
+	id         string
+	status     string
+	stage      string
+	threshold  float64
+	result     *dto.IdentityResolutionDTO
+	err        string
+	createdAt  time.Time
+	finishedAt time.Time
+}
+
+// IdentityServiceImpl implements the IdentityService interface
+type IdentityServiceImpl struct {
+	profileRepo repository.ProfileRepository
+	personRepo  repository.PersonRepository
+	images      *api.BaseClient
+	weights     identity.Weights
+
+	// Background resolutions, guarded by jobsMu
+	jobs    map[string]*resolveJob
+	jobsMu  sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	stopped bool
+}
+
+// NewIdentityService creates a new IdentityServiceImpl
+func NewIdentityService(profileRepo repository.ProfileRepository, personRepo repository.PersonRepository) IdentityService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &IdentityServiceImpl{
+		profileRepo: profileRepo,
+		personRepo:  personRepo,
+		images:      api.NewBaseClient(),
+		weights:     identity.DefaultWeights(),
+		jobs:        make(map[string]*resolveJob),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Resolve clusters every stored profile into persons
+func (s *IdentityServiceImpl) Resolve(ctx context.Context, request dto.ResolveIdentitiesDTO) (*dto.IdentityResolutionDTO, error) {
+	threshold, err := resolveThreshold(request.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, threshold, func(string) {})
+}
+
+// StartResolve starts a resolution in the background
+func (s *IdentityServiceImpl) StartResolve(ctx context.Context, request dto.ResolveIdentitiesDTO) (*dto.ResolveJobDTO, error) {
+	threshold, err := resolveThreshold(request.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	if s.stopped {
+		return nil, ErrResolveStopped
+	}
+
+	s.pruneJobsLocked()
+	for _, job := range s.jobs {
+		if job.status == ResolveJobRunning {
+			return nil, fmt.Errorf("%w: %s", ErrResolveInProgress, job.id)
+		}
+	}
+
+	job := &resolveJob{
+		id:        id,
+		status:    ResolveJobRunning,
+		stage:     ResolveStageLoading,
+		threshold: threshold,
+		createdAt: time.Now(),
+	}
+	s.jobs[id] = job
+
+	s.wg.Add(1)
+	go s.runJob(job)
+
+	return job.toDTO(), nil
+}
+
+// GetResolveJob gets a background resolution
+func (s *IdentityServiceImpl) GetResolveJob(ctx context.Context, id string) (*dto.ResolveJobDTO, error) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrResolveJobNotFound
+	}
+	return job.toDTO(), nil
+}
+
+// Stop cancels a background resolution and waits for it to exit
+func (s *IdentityServiceImpl) Stop() {
+	s.jobsMu.Lock()
+	if s.stopped {
+		s.jobsMu.Unlock()
+		return
+	}
+	s.stopped = true
+	s.jobsMu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+}
+
+// runJob runs a background resolution and records its outcome
+func (s *IdentityServiceImpl) runJob(job *resolveJob) {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithTimeout(s.ctx, resolveJobTimeout)
+	defer cancel()
+
+	result, err := s.resolve(ctx, job.threshold, func(stage string) {
+		s.jobsMu.Lock()
+		job.stage = stage
+		s.jobsMu.Unlock()
+	})
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	job.stage = ""
+	job.finishedAt = time.Now()
+	switch {
+	case err == nil:
+		job.status = ResolveJobCompleted
+		job.result = result
+	case s.ctx.Err() != nil:
+		job.status = ResolveJobCancelled
+		job.err = ErrResolveStopped.Error()
+	default:
+		log.Printf("Error resolving identities: %v", err)
+		job.status = ResolveJobFailed
+		job.err = err.Error()
+	}
+}
+
+// pruneJobsLocked removes finished resolution jobs older than the retention
+// period. The caller must hold jobsMu.
+func (s *IdentityServiceImpl) pruneJobsLocked() {
+	cutoff := time.Now().Add(-resolveJobRetention)
+	for id, job := range s.jobs {
+		if job.status != ResolveJobRunning && job.finishedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// toDTO converts the job to a data transfer object. The caller must hold
+// the service's jobsMu.
+func (j *resolveJob) toDTO() *dto.ResolveJobDTO {
+	jobDTO := &dto.ResolveJobDTO{
+		ID:        j.id,
+		Status:    j.status,
+		Stage:     j.stage,
+		Threshold: j.threshold,
+		Error:     j.err,
+		Result:    j.result,
+		CreatedAt: j.createdAt,
+	}
+	if !j.finishedAt.IsZero() {
+		finishedAt := j.finishedAt
+		jobDTO.FinishedAt = &finishedAt
+	}
+	return jobDTO
+}
+
+// resolveThreshold validates a requested threshold, defaulting to
+// identity.DefaultThreshold
+func resolveThreshold(threshold float64) (float64, error) {
+	if threshold < 0 {
+		return 0, fmt.Errorf("%w: threshold must not be negative", ErrInvalidThreshold)
+	} else if threshold == 0 {
+		return identity.DefaultThreshold, nil
+	}
+	return threshold, nil
+}
+
+// resolve clusters every stored profile into persons, reporting each stage
+// as it starts
+func (s *IdentityServiceImpl) resolve(ctx context.Context, threshold float64, setStage func(string)) (*dto.IdentityResolutionDTO, error) {
+	setStage(ResolveStageLoading)
+	var profiles []*model.Profile
+	for offset := 0; ; offset += profilePageSize {
+		page, err := s.profileRepo.FindAll(ctx, repository.ProfileFilter{}, profilePageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, page...)
+		if len(page) < profilePageSize {
+			break
+		}
+	}
+
+	setStage(ResolveStageHashing)
+	s.HashAvatars(ctx, profiles)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	setStage(ResolveStageClustering)
+
+	byID := make(map[uint]*model.Profile, len(profiles))
+	nodes := make([]identity.Node, 0, len(profiles))
+	for _, profile := range profiles {
+		byID[profile.ID] = profile
+		nodes = append(nodes, identityNode(profile))
+	}
+
+	graph := identity.Build(nodes, s.weights)
+	clusters := graph.Clusters(threshold)
+
+	result := &dto.IdentityResolutionDTO{
+		Profiles:  len(profiles),
+		Persons:   len(clusters),
+		Threshold: threshold,
+	}
+	for _, edge := range graph.Edges {
+		if edge.Weight >= threshold {
+			result.Edges++
+		}
+	}
+
+	claimed := make(map[uint]bool)
+	personClusters := make([]repository.PersonCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		members := make([]*model.Profile, 0, len(cluster))
+		ids := make([]uint, 0, len(cluster))
+		for _, node := range cluster {
+			members = append(members, byID[node.ProfileID])
+			ids = append(ids, node.ProfileID)
+		}
+		if len(cluster) > 1 {
+			result.Merged++
+		}
+
+		personID := existingPersonID(members, claimed)
+		claimed[personID] = true
+		personClusters = append(personClusters, repository.PersonCluster{
+			PersonID:   personID,
+			Name:       personName(members),
+			ProfileIDs: ids,
+		})
+	}
+
+	setStage(ResolveStageSaving)
+	if _, err := s.personRepo.ReplaceAll(ctx, personClusters); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetPerson gets a person with its profiles and the edges between them
+func (s *IdentityServiceImpl) GetPerson(ctx context.Context, id uint) (*dto.PersonDTO, error) {
+	person, err := s.personRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		return nil, ErrPersonNotFound
+	}
+
+	nodes := make([]identity.Node, 0, len(person.Profiles))
+	for i := range person.Profiles {
+		nodes = append(nodes, identityNode(&person.Profiles[i]))
+	}
+
+	personDTO := dto.NewPersonDTO(person)
+	for _, edge := range identity.Build(nodes, s.weights).Edges {
+		personDTO.Edges = append(personDTO.Edges, &dto.PersonEdgeDTO{
+			FromProfileID: edge.From,
+			ToProfileID:   edge.To,
+			Weight:        edge.Weight,
+			Signals:       edge.Signals,
+		})
+	}
+
+	return personDTO, nil
+}
+
+// ListPersons lists persons, largest first, along with the total count
+func (s *IdentityServiceImpl) ListPersons(ctx context.Context, limit, offset int) ([]*dto.PersonDTO, int64, error) {
+	persons, err := s.personRepo.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.personRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	personDTOs := make([]*dto.PersonDTO, 0, len(persons))
+	for _, person := range persons {
+		personDTOs = append(personDTOs, dto.NewPersonDTO(person))
+	}
+
+	return personDTOs, total, nil
+}
+
+// HashAvatars downloads and hashes the pictures of profiles that have none
+// hashed yet. Pictures that cannot be downloaded or decoded are skipped and
+// tried again on the next call.
+func (s *IdentityServiceImpl) HashAvatars(ctx context.Context, profiles []*model.Profile) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+	)
+	sem := make(chan struct{}, avatarConcurrency)
+	for _, profile := range profiles {
+		if profile.ImageHash != "" || profile.ImageURL == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(profile *model.Profile) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			hash, err := s.hashAvatar(ctx, profile)
+			if err != nil {
+				mu.Lock()
+				failed++
+				mu.Unlock()
+				return
+			}
+			profile.ImageHash = hash
+		}(profile)
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		log.Printf("Warning: Failed to hash %d profile pictures", failed)
+	}
+}
+
+// hashAvatar downloads, hashes and stores the hash of a profile's picture
+func (s *IdentityServiceImpl) hashAvatar(ctx context.Context, profile *model.Profile) (string, error) {
+	body, err := s.images.DownloadImage(ctx, profile.ImageURL)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	hash, err := image.HashImage(body)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.profileRepo.UpdateImageHash(ctx, profile.ID, hash); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// identityNode converts a profile into a node of the identity graph
+func identityNode(profile *model.Profile) identity.Node {
+	location, _ := profile.GetPlatformData("location")
+
+	links := storedLinkExtractor.FromPlatformData(profile.GetPlatformDataMap())
+	links = append(links, storedLinkExtractor.FromText(profile.Bio)...)
+
+	return identity.Node{
+		ProfileID: profile.ID,
+		Platform:  profile.Platform,
+		Username:  profile.Username,
+		RealName:  profile.RealName,
+		Location:  location,
+		ImageHash: profile.ImageHash,
+		Links:     links,
+	}
+}
+
+// existingPersonID returns the person most of the profiles already belong
+// to, preferring the lowest ID on ties, or 0 if none that is not yet claimed
+func existingPersonID(profiles []*model.Profile, claimed map[uint]bool) uint {
+	counts := make(map[uint]int)
+	for _, profile := range profiles {
+		if profile.PersonID != nil && !claimed[*profile.PersonID] {
+			counts[*profile.PersonID]++
+		}
+	}
+
+	var best uint
+	for id, count := range counts {
+		if count > counts[best] || (count == counts[best] && id < best) {
+			best = id
+		}
+	}
+	return best
+}
+
+// personName returns the most common real name of the profiles, or the
+// username of the first profile if none has a real name
+func personName(profiles []*model.Profile) string {
+	counts := make(map[string]int)
+	var name string
+	for _, profile := range profiles {
+		if profile.RealName == "" {
+			continue
+		}
+		counts[profile.RealName]++
+		if counts[profile.RealName] > counts[name] {
+			name = profile.RealName
+		}
+	}
+
+	if name == "" && len(profiles) > 0 {
+		return profiles[0].Username
+	}
+	return name
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+)
+
+// listProfileRepository pages through profiles in memory and records the
+// stored picture hashes
+type listProfileRepository struct {
+	repository.ProfileRepository
+	profiles []*model.Profile
+	mu       sync.Mutex
+	hashes   map[uint]string
+}
+
+func (r *listProfileRepository) UpdateImageHash(ctx context.Context, id uint, hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hashes == nil {
+		r.hashes = make(map[uint]string)
+	}
+	r.hashes[id] = hash
+	return nil
+}
+
+func (r *listProfileRepository) FindAll(ctx context.Context, filter repository.ProfileFilter, limit, offset int) ([]*model.Profile, error) {
+	if offset >= len(r.profiles) {
+		return nil, nil
+	}
+	return r.profiles[offset:min(offset+limit, len(r.profiles))], nil
+}
+
+// memoryPersonRepository records replaced persons, waiting for release
+// first when set
+type memoryPersonRepository struct {
+	repository.PersonRepository
+	release  chan struct{}
+	clusters []repository.PersonCluster
+}
+
+func (r *memoryPersonRepository) ReplaceAll(ctx context.Context, clusters []repository.PersonCluster) ([]*model.Person, error) {
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	r.clusters = clusters
+	return nil, nil
+}
+
+// waitForResolveJob polls a resolution job until it finishes
+func waitForResolveJob(t *testing.T, s IdentityService, id string) *dto.ResolveJobDTO {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := s.GetResolveJob(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetResolveJob() error = %v", err)
+		}
+		if job.Status != ResolveJobRunning {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("resolution job %s did not finish", id)
+	return nil
+}
+
+func TestStartResolve(t *testing.T) {
+	profileRepo := &listProfileRepository{profiles: []*model.Profile{
+		{ID: 1, Platform: "GitHub", Username: "johndoe", RealName: "John Doe"},
+		{ID: 2, Platform: "GitLab", Username: "JohnDoe", RealName: "John Doe"},
+		{ID: 3, Platform: "Reddit", Username: "someoneelse"},
+	}}
+	personRepo := &memoryPersonRepository{}
+	s := NewIdentityService(profileRepo, personRepo)
+	defer s.Stop()
+
+	if _, err := s.StartResolve(context.Background(), dto.ResolveIdentitiesDTO{Threshold: -1}); !errors.Is(err, ErrInvalidThreshold) {
+		t.Fatalf("StartResolve() error = %v, want %v", err, ErrInvalidThreshold)
+	}
+
+	job, err := s.StartResolve(context.Background(), dto.ResolveIdentitiesDTO{})
+	if err != nil {
+		t.Fatalf("StartResolve() error = %v", err)
+	}
+	if job.Status != ResolveJobRunning || job.ID == "" {
+		t.Fatalf("StartResolve() = %+v, want a running job", job)
+	}
+
+	job = waitForResolveJob(t, s, job.ID)
+	if job.Status != ResolveJobCompleted || job.FinishedAt == nil || job.Stage != "" {
+		t.Fatalf("job = %+v, want completed", job)
+	}
+	if job.Result == nil || job.Result.Profiles != 3 || job.Result.Persons != 2 || job.Result.Merged != 1 {
+		t.Errorf("job.Result = %+v, want 3 profiles in 2 persons with 1 merged", job.Result)
+	}
+	if len(personRepo.clusters) != 2 {
+		t.Errorf("stored %d persons, want 2", len(personRepo.clusters))
+	}
+
+	if _, err := s.GetResolveJob(context.Background(), "missing"); !errors.Is(err, ErrResolveJobNotFound) {
+		t.Errorf("GetResolveJob() error = %v, want %v", err, ErrResolveJobNotFound)
+	}
+}
+
+func TestStartResolveRunsOneAtATime(t *testing.T) {
+	profileRepo := &listProfileRepository{profiles: []*model.Profile{
+		{ID: 1, Platform: "GitHub", Username: "johndoe"},
+	}}
+	personRepo := &memoryPersonRepository{release: make(chan struct{})}
+	s := NewIdentityService(profileRepo, personRepo)
+
+	first, err := s.StartResolve(context.Background(), dto.ResolveIdentitiesDTO{})
+	if err != nil {
+		t.Fatalf("StartResolve() error = %v", err)
+	}
+	if _, err := s.StartResolve(context.Background(), dto.ResolveIdentitiesDTO{}); !errors.Is(err, ErrResolveInProgress) {
+		t.Fatalf("second StartResolve() error = %v, want %v", err, ErrResolveInProgress)
+	}
+
+	personRepo.release <- struct{}{}
+	if job := waitForResolveJob(t, s, first.ID); job.Status != ResolveJobCompleted {
+		t.Fatalf("first job status = %q, want %q", job.Status, ResolveJobCompleted)
+	}
+
+	second, err := s.StartResolve(context.Background(), dto.ResolveIdentitiesDTO{})
+	if err != nil {
+		t.Fatalf("StartResolve() after completion error = %v", err)
+	}
+
+	// Stopping cancels the job still waiting to save
+	s.Stop()
+	job, err := s.GetResolveJob(context.Background(), second.ID)
+	if err != nil {
+		t.Fatalf("GetResolveJob() error = %v", err)
+	}
+	if job.Status != ResolveJobCancelled {
+		t.Errorf("second job status = %q, want %q", job.Status, ResolveJobCancelled)
+	}
+	if _, err := s.StartResolve(context.Background(), dto.ResolveIdentitiesDTO{}); !errors.Is(err, ErrResolveStopped) {
+		t.Errorf("StartResolve() after Stop error = %v, want %v", err, ErrResolveStopped)
+	}
+}
+
+func TestHashAvatars(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/avatar.png" {
+			http.NotFound(w, r)
+			return
+		}
+		img := image.NewGray(image.Rect(0, 0, 16, 16))
+		for x := 0; x < 8; x++ {
+			for y := 0; y < 16; y++ {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+		png.Encode(w, img)
+	}))
+	defer server.Close()
+
+	profiles := []*model.Profile{
+		{ID: 1, ImageURL: server.URL + "/avatar.png"},
+		{ID: 2, ImageURL: server.URL + "/missing.png"},
+		{ID: 3, ImageURL: server.URL + "/avatar.png", ImageHash: "00000000000000ff"},
+		{ID: 4},
+	}
+	profileRepo := &listProfileRepository{profiles: profiles}
+	s := NewIdentityService(profileRepo, &memoryPersonRepository{})
+	defer s.Stop()
+
+	s.HashAvatars(context.Background(), profiles)
+
+	if profiles[0].ImageHash == "" || profileRepo.hashes[1] != profiles[0].ImageHash {
+		t.Errorf("Expected the picture of profile 1 to be hashed and stored, got %q (stored %q)", profiles[0].ImageHash, profileRepo.hashes[1])
+	}
+	if profiles[1].ImageHash != "" {
+		t.Errorf("Expected no hash for a missing picture, got %q", profiles[1].ImageHash)
+	}
+	if profiles[2].ImageHash != "00000000000000ff" {
+		t.Errorf("Expected an existing hash to be kept, got %q", profiles[2].ImageHash)
+	}
+	if len(profileRepo.hashes) != 1 {
+		t.Errorf("Expected only one hash to be stored, got %v", profileRepo.hashes)
+	}
+}
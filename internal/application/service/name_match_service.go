@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/accio/internal/application/dto"
+	domainservice "github.com/accio/internal/domain/service"
+	"github.com/accio/internal/intersection"
+	"github.com/accio/internal/matcher"
+)
+
+// NameMatchService defines the interface for comparing the real names of
+// found profiles with the name being searched for
+type NameMatchService interface {
+	// MatchNames fetches the found profiles of a match from the platforms
+	// with API clients and records in NameMatches whether each has the
+	// searched real name. Profiles that cannot be fetched are left out.
+	MatchNames(ctx context.Context, match *intersection.ProfileMatch, name *matcher.NameInfo)
+}
+
+// NameMatchServiceImpl implements the NameMatchService interface
+type NameMatchServiceImpl struct {
+	profileService domainservice.ProfileService
+}
+
+// NewNameMatchService creates a new NameMatchServiceImpl
+func NewNameMatchService(profileService domainservice.ProfileService) NameMatchService {
+	return &NameMatchServiceImpl{
+		profileService: profileService,
+	}
+}
+
+// MatchNames records which found profiles of a match have the searched real name
+func (s *NameMatchServiceImpl) MatchNames(ctx context.Context, match *intersection.ProfileMatch, name *matcher.NameInfo) {
+	supported := make(map[string]bool)
+	for _, platform := range s.profileService.GetSupportedPlatforms() {
+		supported[platform] = true
+	}
+
+	if match.NameMatches == nil {
+		match.NameMatches = make(map[string]bool)
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, result := range match.Results {
+		if !result.Exists || !supported[result.Site] {
+			continue
+		}
+
+		wg.Add(1)
+		go func(platform string) {
+			defer wg.Done()
+
+			// Profiles are fetched through the profile service so they are
+			// stored and later lookups are served from the database
+			profile, err := s.profileService.GetProfileByUsername(ctx, match.Username, platform)
+			if err != nil || profile == nil {
+				return
+			}
+
+			matched := profileHasName(profile, name)
+			mu.Lock()
+			match.NameMatches[platform] = matched
+			mu.Unlock()
+		}(result.Site)
+	}
+	wg.Wait()
+}
+
+// profileHasName reports whether the real name or name parts of a profile
+// match the searched name
+func profileHasName(profile *dto.ProfileDTO, name *matcher.NameInfo) bool {
+	if name.MatchesName(profile.RealName) {
+		return true
+	}
+
+	// Name parts may be curated separately from the display name
+	var first, middle, last string
+	for _, part := range profile.NameParts {
+		switch part.PartType {
+		case "first":
+			first = part.NamePart
+		case "middle":
+			middle = part.NamePart
+		case "last":
+			last = part.NamePart
+		}
+	}
+	if first == "" && last == "" {
+		return false
+	}
+	return name.MatchesName(strings.Join(strings.Fields(first+" "+middle+" "+last), " "))
+}
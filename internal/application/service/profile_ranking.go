@@ -0,0 +1,101 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/matcher"
+)
+
+// Name match scores used to rank search results
+const (
+	scoreExactName    = 100
+	scorePrefixName   = 60
+	scoreContainsName = 40
+	scoreNamePart     = 20
+	scoreAlias        = 10
+	scoreVerified     = 5
+)
+
+// minNamePartLength is the number of runes a name part needs to score, so
+// initials do not match every query containing the letter
+const minNamePartLength = 2
+
+// rankProfilesByName sorts profiles by how well they match the searched name,
+// with a small bonus for verified profiles. Flagged profiles are always
+// ranked last. Ties are broken by follower count.
+func rankProfilesByName(name string, profiles []*model.Profile, flagged map[uint]bool) []*model.Profile {
+	query := strings.ToLower(strings.TrimSpace(name))
+	tokens := make(map[string]bool)
+	for _, token := range strings.Fields(matcher.NormalizeName(name)) {
+		tokens[token] = true
+	}
+
+	scores := make(map[*model.Profile]int, len(profiles))
+	for _, profile := range profiles {
+		scores[profile] = scoreProfileName(query, tokens, profile)
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		if flagged[profiles[i].ID] != flagged[profiles[j].ID] {
+			return !flagged[profiles[i].ID]
+		}
+		if scores[profiles[i]] != scores[profiles[j]] {
+			return scores[profiles[i]] > scores[profiles[j]]
+		}
+		return profiles[i].FollowerCount > profiles[j].FollowerCount
+	})
+
+	return profiles
+}
+
+// scoreProfileName scores a single profile against a lowercase query and the
+// tokens of the normalized query
+func scoreProfileName(query string, tokens map[string]bool, profile *model.Profile) int {
+	score := 0
+	realName := strings.ToLower(profile.RealName)
+
+	switch {
+	case realName == query:
+		score += scoreExactName
+	case strings.HasPrefix(realName, query):
+		score += scorePrefixName
+	case strings.Contains(realName, query):
+		score += scoreContainsName
+	}
+
+	for _, part := range profile.NameParts {
+		if namePartMatches(tokens, part.NamePart) {
+			score += scoreNamePart
+		}
+	}
+
+	for _, alias := range profile.Aliases {
+		if strings.EqualFold(alias.Alias, query) {
+			score += scoreAlias
+		}
+	}
+
+	if profile.Verified {
+		score += scoreVerified
+	}
+
+	return score
+}
+
+// namePartMatches reports whether every word of a name part is a whole token
+// of the query. Parts shorter than minNamePartLength never match.
+func namePartMatches(tokens map[string]bool, namePart string) bool {
+	words := strings.Fields(matcher.NormalizeName(namePart))
+	if len(words) == 0 || utf8.RuneCountInString(strings.Join(words, " ")) < minNamePartLength {
+		return false
+	}
+	for _, word := range words {
+		if !tokens[word] {
+			return false
+		}
+	}
+	return true
+}
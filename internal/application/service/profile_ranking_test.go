@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/accio/internal/domain/model"
+)
+
+func TestRankProfilesByName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		profiles []*model.Profile
+		flagged  map[uint]bool
+		expected []uint
+	}{
+		{
+			name:  "exact before prefix before contains",
+			query: "John Doe",
+			profiles: []*model.Profile{
+				{ID: 1, RealName: "Mr John Doe"},
+				{ID: 2, RealName: "John Doe Jr"},
+				{ID: 3, RealName: "John Doe"},
+			},
+			expected: []uint{3, 2, 1},
+		},
+		{
+			name:  "verification adds to the score",
+			query: "John Doe",
+			profiles: []*model.Profile{
+				{ID: 1, RealName: "John Doe", FollowerCount: 1000},
+				{ID: 2, RealName: "John Doe", Verified: true},
+			},
+			expected: []uint{2, 1},
+		},
+		{
+			name:  "followers break a full tie",
+			query: "John Doe",
+			profiles: []*model.Profile{
+				{ID: 1, RealName: "John Doe", FollowerCount: 10},
+				{ID: 2, RealName: "John Doe", FollowerCount: 500},
+				{ID: 3, RealName: "John Doe", FollowerCount: 50},
+			},
+			expected: []uint{2, 3, 1},
+		},
+		{
+			name:  "equal profiles keep their order",
+			query: "John Doe",
+			profiles: []*model.Profile{
+				{ID: 1, RealName: "John Doe"},
+				{ID: 2, RealName: "John Doe"},
+			},
+			expected: []uint{1, 2},
+		},
+		{
+			name:  "flagged profiles are ranked last",
+			query: "John Doe",
+			profiles: []*model.Profile{
+				{ID: 1, RealName: "John Doe", Verified: true, FollowerCount: 1000},
+				{ID: 2, RealName: "Johnny"},
+				{ID: 3, RealName: "John Doe"},
+			},
+			flagged:  map[uint]bool{1: true},
+			expected: []uint{3, 2, 1},
+		},
+		{
+			name:  "name parts and aliases add to the score",
+			query: "john doe",
+			profiles: []*model.Profile{
+				{ID: 1, RealName: "Johnny D"},
+				{ID: 2, RealName: "J. D.", NameParts: []model.NamePart{{NamePart: "John"}, {NamePart: "Doe"}}},
+				{ID: 3, RealName: "JD", Aliases: []model.Alias{{Alias: "John Doe"}}},
+			},
+			expected: []uint{2, 3, 1},
+		},
+		{
+			name:  "name parts match whole tokens of the query",
+			query: "Joanna Doe",
+			profiles: []*model.Profile{
+				{ID: 1, RealName: "Someone", NameParts: []model.NamePart{{NamePart: "a"}, {NamePart: "j"}, {NamePart: "Jo"}, {NamePart: "Do"}}},
+				{ID: 2, RealName: "Other", NameParts: []model.NamePart{{NamePart: "Joanna"}}},
+			},
+			expected: []uint{2, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ranked := rankProfilesByName(tc.query, tc.profiles, tc.flagged)
+			for i, profile := range ranked {
+				if profile.ID != tc.expected[i] {
+					t.Fatalf("Expected order %v, got profile %d at position %d", tc.expected, profile.ID, i)
+				}
+			}
+		})
+	}
+}
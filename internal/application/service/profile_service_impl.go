@@ -4,26 +4,97 @@ import (
 	"context"
 	"errors"
 	"io"
+	"log"
 	"sync"
+	"time"
 
+	"github.com/accio/internal/application/dto"
 	"github.com/accio/internal/domain/model"
 	"github.com/accio/internal/domain/repository"
 	"github.com/accio/internal/domain/service"
 	"github.com/accio/internal/infrastructure/api"
+	"github.com/accio/internal/intersection"
+	"github.com/accio/internal/metrics"
+)
+
+// ErrProfileSnapshotNotFound is returned when a snapshot does not exist or
+// belongs to another profile
+var ErrProfileSnapshotNotFound = errors.New("profile snapshot not found")
+
+// maxNotFoundEntries is the number of remembered not found usernames above
+// which expired ones are pruned
+const maxNotFoundEntries = 10000
+
+// ProfileServiceConfig configures how long stored profiles are served
+// before they are fetched again from their platform
+type ProfileServiceConfig struct {
+	TTL            time.Duration            // How long a stored profile is served as is
+	PlatformTTLs   map[string]time.Duration // TTL overrides for platforms whose profiles change faster or slower
+	StaleTTL       time.Duration            // How long past its TTL a profile is served while refreshed in the background
+	NotFoundTTL    time.Duration            // How long a username not found on a platform is not looked up again
+	RefreshTimeout time.Duration            // Timeout of each background refresh
+	NoCache        bool                     // Fetch every profile from its platform, as RefreshProfileByUsername does
+}
+
+// DefaultProfileServiceConfig returns the default profile service configuration
+func DefaultProfileServiceConfig() ProfileServiceConfig {
+	return ProfileServiceConfig{
+		TTL: 24 * time.Hour,
+		PlatformTTLs: map[string]time.Duration{
+			"Twitch":  6 * time.Hour,
+			"Twitter": 6 * time.Hour,
+		},
+		StaleTTL:       7 * 24 * time.Hour,
+		NotFoundTTL:    time.Hour,
+		RefreshTimeout: 30 * time.Second,
+	}
+}
+
+// profileFreshness is how a stored profile may be served
+type profileFreshness int
+
+const (
+	profileFresh   profileFreshness = iota // Served as is
+	profileStale                           // Served while refreshed in the background
+	profileExpired                         // Fetched again before being served
 )
 
 // ProfileServiceImpl implements the ProfileService interface
 type ProfileServiceImpl struct {
+	config          ProfileServiceConfig
 	profileRepo     repository.ProfileRepository
+	feedbackRepo    repository.UserFeedbackRepository
 	platformClients map[string]api.PlatformClient
 	clientsMutex    sync.RWMutex
+
+	notFound   map[string]time.Time // Expiry of usernames not found, by platform and username
+	refreshing map[string]bool      // Profiles being refreshed in the background
+	cacheMutex sync.Mutex
 }
 
 // NewProfileService creates a new ProfileServiceImpl
-func NewProfileService(profileRepo repository.ProfileRepository) service.ProfileService {
+func NewProfileService(config ProfileServiceConfig, profileRepo repository.ProfileRepository, feedbackRepo repository.UserFeedbackRepository) service.ProfileService {
+	defaults := DefaultProfileServiceConfig()
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.StaleTTL < 0 {
+		config.StaleTTL = 0
+	}
+	if config.NotFoundTTL < 0 {
+		config.NotFoundTTL = 0
+	}
+	if config.RefreshTimeout <= 0 {
+		config.RefreshTimeout = defaults.RefreshTimeout
+	}
+
 	return &ProfileServiceImpl{
+		config:          config,
 		profileRepo:     profileRepo,
+		feedbackRepo:    feedbackRepo,
 		platformClients: make(map[string]api.PlatformClient),
+		notFound:        make(map[string]time.Time),
+		refreshing:      make(map[string]bool),
 	}
 }
 
@@ -34,57 +105,256 @@ func (s *ProfileServiceImpl) RegisterPlatformClient(client api.PlatformClient) {
 	s.platformClients[client.GetPlatformName()] = client
 }
 
-// GetProfileByUsername gets a profile by username from a specific platform
-func (s *ProfileServiceImpl) GetProfileByUsername(ctx context.Context, username, platform string) (*model.Profile, error) {
+// platformClient returns the client registered for a platform
+func (s *ProfileServiceImpl) platformClient(platform string) (api.PlatformClient, bool) {
+	s.clientsMutex.RLock()
+	defer s.clientsMutex.RUnlock()
+	client, ok := s.platformClients[platform]
+	return client, ok
+}
+
+// GetProfileByUsername gets a profile by username from a specific platform.
+// A stored profile is served as is within its platform's TTL, served while
+// refreshed in the background for StaleTTL after that, and fetched again
+// once older. Profiles of platforms without a client are always served.
+func (s *ProfileServiceImpl) GetProfileByUsername(ctx context.Context, username, platform string) (*dto.ProfileDTO, error) {
+	client, ok := s.platformClient(platform)
+	if ok && s.config.NoCache {
+		return s.RefreshProfileByUsername(ctx, username, platform)
+	}
+
 	// First, try to get from repository
 	profile, err := s.profileRepo.FindByUsername(ctx, username, platform)
 	if err != nil {
 		return nil, err
 	}
 
-	// If found in repository, return it
 	if profile != nil {
-		return profile, nil
+		freshness := profileFresh
+		if ok {
+			freshness = s.freshness(profile, time.Now())
+		}
+
+		switch freshness {
+		case profileFresh:
+			metrics.ProfileCacheLookups.WithLabelValues("username", metrics.CacheHit).Inc()
+			return s.profileDTO(ctx, profile)
+		case profileStale:
+			metrics.ProfileCacheLookups.WithLabelValues("username", metrics.CacheStale).Inc()
+			s.refreshInBackground(client, username, platform)
+			return s.profileDTO(ctx, profile)
+		}
+		metrics.ProfileCacheLookups.WithLabelValues("username", metrics.CacheExpired).Inc()
+
+		// Serve the stored profile if the platform cannot be reached
+		fetched, err := s.fetchCachedProfile(ctx, client, username, platform)
+		if err != nil && !errors.Is(err, api.ErrNotFound) {
+			log.Printf("Error refreshing profile %s on %s, serving the stored profile: %v", username, platform, err)
+			return s.profileDTO(ctx, profile)
+		}
+		if err != nil || fetched == nil {
+			return nil, err
+		}
+		return s.profileDTO(ctx, fetched)
+	}
+
+	if s.isNotFound(platform, username) {
+		metrics.ProfileCacheLookups.WithLabelValues("username", metrics.CacheNegative).Inc()
+		return nil, api.ErrNotFound
 	}
+	metrics.ProfileCacheLookups.WithLabelValues("username", metrics.CacheMiss).Inc()
 
 	// If not found in repository, try to get from platform API
-	s.clientsMutex.RLock()
-	client, ok := s.platformClients[platform]
-	s.clientsMutex.RUnlock()
+	if !ok {
+		return nil, service.ErrUnsupportedPlatform
+	}
 
+	profile, err = s.fetchProfile(ctx, client, username, platform)
+	if err != nil || profile == nil {
+		return nil, err
+	}
+	return s.profileDTO(ctx, profile)
+}
+
+// RefreshProfileByUsername fetches a profile from its platform, ignoring the
+// stored profile and remembered not found usernames, and saves it
+func (s *ProfileServiceImpl) RefreshProfileByUsername(ctx context.Context, username, platform string) (*dto.ProfileDTO, error) {
+	client, ok := s.platformClient(platform)
 	if !ok {
-		return nil, errors.New("unsupported platform")
+		return nil, service.ErrUnsupportedPlatform
+	}
+	metrics.ProfileCacheLookups.WithLabelValues("username", metrics.CacheBypass).Inc()
+
+	profile, err := s.fetchProfile(ctx, client, username, platform)
+	if err != nil || profile == nil {
+		return nil, err
 	}
+	return s.profileDTO(ctx, profile)
+}
 
-	// Get from platform API
-	profile, err = client.GetProfileByUsername(ctx, username)
+// profileDTO converts a profile to a data transfer object with its feedback
+// flag and identity match
+func (s *ProfileServiceImpl) profileDTO(ctx context.Context, profile *model.Profile) (*dto.ProfileDTO, error) {
+	flagged, err := findFlaggedProfiles(ctx, s.feedbackRepo, []*model.Profile{profile})
 	if err != nil {
 		return nil, err
 	}
 
-	// Save to repository
-	if profile != nil {
-		if err := s.profileRepo.Create(ctx, profile); err != nil {
-			// Log error but continue
-			// log.Printf("Error saving profile to repository: %v", err)
-		}
+	profileDTO := dto.NewProfileDTO(profile)
+	profileDTO.Flagged = flagged[profile.ID]
+	if profileDTO.Match, err = s.identityMatch(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profileDTO, nil
+}
+
+// freshness classifies a stored profile by the time since it was last
+// fetched from its platform
+func (s *ProfileServiceImpl) freshness(profile *model.Profile, now time.Time) profileFreshness {
+	ttl, ok := s.config.PlatformTTLs[profile.Platform]
+	if !ok || ttl <= 0 {
+		ttl = s.config.TTL
 	}
 
+	age := now.Sub(profile.LastUpdated)
+	switch {
+	case age < ttl:
+		return profileFresh
+	case age < ttl+s.config.StaleTTL:
+		return profileStale
+	default:
+		return profileExpired
+	}
+}
+
+// fetchCachedProfile fetches a profile from its platform unless its username
+// was recently not found there
+func (s *ProfileServiceImpl) fetchCachedProfile(ctx context.Context, client api.PlatformClient, username, platform string) (*model.Profile, error) {
+	if s.isNotFound(platform, username) {
+		return nil, api.ErrNotFound
+	}
+	return s.fetchProfile(ctx, client, username, platform)
+}
+
+// fetchProfile fetches a profile from its platform and saves it. A username
+// not found is remembered for NotFoundTTL.
+func (s *ProfileServiceImpl) fetchProfile(ctx context.Context, client api.PlatformClient, username, platform string) (*model.Profile, error) {
+	profile, err := client.GetProfileByUsername(ctx, username)
+	if errors.Is(err, api.ErrNotFound) {
+		s.setNotFound(platform, username, true)
+	}
+	if err != nil || profile == nil {
+		return nil, err
+	}
+	s.setNotFound(platform, username, false)
+
+	if err := s.SaveProfile(ctx, profile); err != nil {
+		log.Printf("Error saving profile %s on %s: %v", username, platform, err)
+	}
 	return profile, nil
 }
 
-// SearchProfilesByName searches for profiles by real name
-func (s *ProfileServiceImpl) SearchProfilesByName(ctx context.Context, name string) ([]*model.Profile, error) {
+// refreshInBackground fetches and saves a stale profile without blocking
+// the lookup serving it. A profile is refreshed once at a time.
+func (s *ProfileServiceImpl) refreshInBackground(client api.PlatformClient, username, platform string) {
+	key := profileCacheKey(platform, username)
+	s.cacheMutex.Lock()
+	if s.refreshing[key] {
+		s.cacheMutex.Unlock()
+		return
+	}
+	s.refreshing[key] = true
+	s.cacheMutex.Unlock()
+
+	go func() {
+		defer func() {
+			s.cacheMutex.Lock()
+			delete(s.refreshing, key)
+			s.cacheMutex.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.config.RefreshTimeout)
+		defer cancel()
+		if _, err := s.fetchCachedProfile(ctx, client, username, platform); err != nil {
+			log.Printf("Error refreshing profile %s on %s: %v", username, platform, err)
+		}
+	}()
+}
+
+// isNotFound reports whether a username was recently not found on a platform
+func (s *ProfileServiceImpl) isNotFound(platform, username string) bool {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+	expiry, ok := s.notFound[profileCacheKey(platform, username)]
+	return ok && time.Now().Before(expiry)
+}
+
+// setNotFound remembers or forgets that a username was not found on a platform
+func (s *ProfileServiceImpl) setNotFound(platform, username string, notFound bool) {
+	key := profileCacheKey(platform, username)
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	if !notFound || s.config.NotFoundTTL == 0 {
+		delete(s.notFound, key)
+		return
+	}
+
+	now := time.Now()
+	if len(s.notFound) >= maxNotFoundEntries {
+		for k, expiry := range s.notFound {
+			if !now.Before(expiry) {
+				delete(s.notFound, k)
+			}
+		}
+	}
+	s.notFound[key] = now.Add(s.config.NotFoundTTL)
+}
+
+// profileCacheKey identifies a username on a platform
+func profileCacheKey(platform, username string) string {
+	return platform + "/" + username
+}
+
+// identityMatch scores how likely the stored profiles sharing the profile's
+// username belong to the same person
+func (s *ProfileServiceImpl) identityMatch(ctx context.Context, profile *model.Profile) (*dto.IdentityMatchDTO, error) {
+	same, err := s.profileRepo.FindAllByUsername(ctx, profile.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	match := newStoredMatch(profile, same)
+	intersection.CurrentWeights().Apply(&match)
+
+	identityMatch := &dto.IdentityMatchDTO{
+		Username:   match.Username,
+		Platforms:  make([]string, 0, len(match.Results)),
+		Confidence: match.Confidence,
+		Evidence:   match.Evidence,
+		Links:      match.Links,
+	}
+	for _, result := range match.Results {
+		identityMatch.Platforms = append(identityMatch.Platforms, result.Site)
+	}
+
+	return identityMatch, nil
+}
+
+// SearchProfilesByName searches for profiles by real name, best matches first
+func (s *ProfileServiceImpl) SearchProfilesByName(ctx context.Context, name string) ([]*dto.ProfileDTO, error) {
 	// First, try to get from repository
-	profiles, err := s.profileRepo.FindByRealName(ctx, name)
+	profiles, err := s.findProfilesByName(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 
 	// If found in repository, return them
 	if len(profiles) > 0 {
-		return profiles, nil
+		metrics.ProfileCacheLookups.WithLabelValues("name", metrics.CacheHit).Inc()
+		return s.rankedProfileDTOs(ctx, name, profiles)
 	}
+	metrics.ProfileCacheLookups.WithLabelValues("name", metrics.CacheMiss).Inc()
 
 	// If not found in repository, try to get from platform APIs
 	var allProfiles []*model.Profile
@@ -107,7 +377,7 @@ func (s *ProfileServiceImpl) SearchProfilesByName(ctx context.Context, name stri
 
 			// Save to repository
 			for _, profile := range clientProfiles {
-				if err := s.profileRepo.Create(ctx, profile); err != nil {
+				if err := s.SaveProfile(ctx, profile); err != nil {
 					// Log error but continue
 					// log.Printf("Error saving profile to repository: %v", err)
 				}
@@ -124,25 +394,84 @@ func (s *ProfileServiceImpl) SearchProfilesByName(ctx context.Context, name stri
 	// Wait for all goroutines to finish
 	wg.Wait()
 
-	return allProfiles, nil
+	return s.rankedProfileDTOs(ctx, name, allProfiles)
+}
+
+// rankedProfileDTOs ranks profiles by name match, demoting profiles flagged
+// by user feedback below all others
+func (s *ProfileServiceImpl) rankedProfileDTOs(ctx context.Context, name string, profiles []*model.Profile) ([]*dto.ProfileDTO, error) {
+	flagged, err := findFlaggedProfiles(ctx, s.feedbackRepo, profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	profileDTOs := dto.NewProfileDTOs(rankProfilesByName(name, profiles, flagged))
+	for _, profileDTO := range profileDTOs {
+		profileDTO.Flagged = flagged[profileDTO.ID]
+	}
+
+	return profileDTOs, nil
+}
+
+// findProfilesByName finds stored profiles whose real name, name parts or aliases match
+func (s *ProfileServiceImpl) findProfilesByName(ctx context.Context, name string) ([]*model.Profile, error) {
+	finders := []func(context.Context, string) ([]*model.Profile, error){
+		s.profileRepo.FindByRealName,
+		s.profileRepo.FindByNamePart,
+		s.profileRepo.FindByAlias,
+	}
+
+	var profiles []*model.Profile
+	seen := make(map[uint]bool)
+	for _, find := range finders {
+		found, err := find(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, profile := range found {
+			if seen[profile.ID] {
+				continue
+			}
+			seen[profile.ID] = true
+			profiles = append(profiles, profile)
+		}
+	}
+
+	return profiles, nil
+}
+
+// ListProfiles lists stored profiles matching the filter, along with the total count
+func (s *ProfileServiceImpl) ListProfiles(ctx context.Context, filter repository.ProfileFilter, limit, offset int) ([]*dto.ProfileDTO, int64, error) {
+	total, err := s.profileRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	profiles, err := s.profileRepo.FindAll(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return dto.NewProfileDTOs(profiles), total, nil
 }
 
 // GetProfileImage gets a profile image
 func (s *ProfileServiceImpl) GetProfileImage(ctx context.Context, profile *model.Profile) (io.ReadCloser, error) {
 	// Get platform client
-	s.clientsMutex.RLock()
-	client, ok := s.platformClients[profile.Platform]
-	s.clientsMutex.RUnlock()
-
+	client, ok := s.platformClient(profile.Platform)
 	if !ok {
-		return nil, errors.New("unsupported platform")
+		return nil, service.ErrUnsupportedPlatform
 	}
 
 	// Get image from platform API
 	return client.GetProfileImage(ctx, profile)
 }
 
-// SaveProfile saves a profile to the repository
+// SaveProfile saves a profile fetched from its platform and, in the same
+// transaction, records the fetched state as a snapshot unless it equals the
+// latest one. An existing profile keeps its ID, creation time and person,
+// and its picture hash unless the picture changed.
 func (s *ProfileServiceImpl) SaveProfile(ctx context.Context, profile *model.Profile) error {
 	// Check if profile already exists
 	existingProfile, err := s.profileRepo.FindByUsername(ctx, profile.Username, profile.Platform)
@@ -150,14 +479,111 @@ func (s *ProfileServiceImpl) SaveProfile(ctx context.Context, profile *model.Pro
 		return err
 	}
 
+	profile.ID = 0
+	profile.LastUpdated = time.Now()
 	if existingProfile != nil {
-		// Update existing profile
 		profile.ID = existingProfile.ID
-		return s.profileRepo.Update(ctx, profile)
+		profile.CreatedAt = existingProfile.CreatedAt
+		profile.PersonID = existingProfile.PersonID
+		if profile.ImageURL == existingProfile.ImageURL {
+			profile.ImageHash = existingProfile.ImageHash
+		}
 	}
 
-	// Create new profile
-	return s.profileRepo.Create(ctx, profile)
+	snapshot := model.NewProfileSnapshot(profile)
+	if existingProfile != nil {
+		latest, err := s.profileRepo.FindSnapshots(ctx, existingProfile.ID, 1, 0)
+		if err != nil {
+			return err
+		}
+		if len(latest) > 0 && len(model.DiffProfileSnapshots(latest[0], snapshot)) == 0 {
+			snapshot = nil
+		}
+	}
+
+	return s.profileRepo.SaveWithSnapshot(ctx, profile, snapshot)
+}
+
+// ListSnapshots lists the snapshots of a stored profile, latest first
+func (s *ProfileServiceImpl) ListSnapshots(ctx context.Context, profileID uint, limit, offset int) ([]*dto.ProfileSnapshotDTO, int64, error) {
+	if err := s.checkProfileExists(ctx, profileID); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.profileRepo.CountSnapshots(ctx, profileID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Load one more snapshot than the page to compare the oldest one on it
+	snapshots, err := s.profileRepo.FindSnapshots(ctx, profileID, limit+1, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	snapshotDTOs := make([]*dto.ProfileSnapshotDTO, 0, limit)
+	for i, snapshot := range snapshots {
+		if i == limit {
+			break
+		}
+
+		snapshotDTO := dto.NewProfileSnapshotDTO(snapshot)
+		if i+1 < len(snapshots) {
+			previous := snapshots[i+1]
+			snapshotDTO.PreviousID = &previous.ID
+			snapshotDTO.Changes = dto.NewProfileFieldChangeDTOs(model.DiffProfileSnapshots(previous, snapshot))
+		}
+		snapshotDTOs = append(snapshotDTOs, snapshotDTO)
+	}
+
+	return snapshotDTOs, total, nil
+}
+
+// DiffSnapshots compares two snapshots of a stored profile
+func (s *ProfileServiceImpl) DiffSnapshots(ctx context.Context, profileID, fromID, toID uint) (*dto.ProfileSnapshotDiffDTO, error) {
+	if err := s.checkProfileExists(ctx, profileID); err != nil {
+		return nil, err
+	}
+
+	from, err := s.findSnapshot(ctx, profileID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findSnapshot(ctx, profileID, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ProfileSnapshotDiffDTO{
+		From:    dto.NewProfileSnapshotDTO(from),
+		To:      dto.NewProfileSnapshotDTO(to),
+		Changes: dto.NewProfileFieldChangeDTOs(model.DiffProfileSnapshots(from, to)),
+	}, nil
+}
+
+// checkProfileExists returns ErrProfileNotFound if a profile does not exist
+func (s *ProfileServiceImpl) checkProfileExists(ctx context.Context, profileID uint) error {
+	profile, err := s.profileRepo.FindByID(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		return ErrProfileNotFound
+	}
+	return nil
+}
+
+// findSnapshot finds a snapshot of a profile, returning
+// ErrProfileSnapshotNotFound if it does not exist or belongs to another profile
+func (s *ProfileServiceImpl) findSnapshot(ctx context.Context, profileID, id uint) (*model.ProfileSnapshot, error) {
+	snapshot, err := s.profileRepo.FindSnapshotByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil || snapshot.ProfileID != profileID {
+		return nil, ErrProfileSnapshotNotFound
+	}
+	return snapshot, nil
 }
 
 // GetSupportedPlatforms returns a list of supported platforms
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+	"github.com/accio/internal/infrastructure/api"
+)
+
+// notFoundClient is a platform client on which no profile exists
+type notFoundClient struct {
+	calls int
+}
+
+func (c *notFoundClient) GetProfileByUsername(ctx context.Context, username string) (*model.Profile, error) {
+	c.calls++
+	return nil, api.ErrNotFound
+}
+
+func (c *notFoundClient) SearchProfilesByName(ctx context.Context, name string) ([]*model.Profile, error) {
+	return nil, nil
+}
+
+func (c *notFoundClient) GetProfileImage(ctx context.Context, profile *model.Profile) (io.ReadCloser, error) {
+	return nil, api.ErrNotFound
+}
+
+func (c *notFoundClient) GetPlatformName() string {
+	return "GitHub"
+}
+
+// memoryHistoryRepository keeps profiles and their snapshots in memory
+type memoryHistoryRepository struct {
+	repository.ProfileRepository
+	profiles  []*model.Profile
+	snapshots []*model.ProfileSnapshot
+}
+
+func (r *memoryHistoryRepository) FindByID(ctx context.Context, id uint) (*model.Profile, error) {
+	for _, profile := range r.profiles {
+		if profile.ID == id {
+			return profile, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryHistoryRepository) FindByUsername(ctx context.Context, username, platform string) (*model.Profile, error) {
+	for _, profile := range r.profiles {
+		if profile.Username == username && profile.Platform == platform {
+			copied := *profile
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryHistoryRepository) SaveWithSnapshot(ctx context.Context, profile *model.Profile, snapshot *model.ProfileSnapshot) error {
+	copied := *profile
+	if profile.ID == 0 {
+		profile.ID = uint(len(r.profiles) + 1)
+		copied.ID = profile.ID
+		r.profiles = append(r.profiles, &copied)
+	} else {
+		r.profiles[profile.ID-1] = &copied
+	}
+
+	if snapshot != nil {
+		snapshot.ID = uint(len(r.snapshots) + 1)
+		snapshot.ProfileID = profile.ID
+		r.snapshots = append(r.snapshots, snapshot)
+	}
+	return nil
+}
+
+func (r *memoryHistoryRepository) FindSnapshotByID(ctx context.Context, id uint) (*model.ProfileSnapshot, error) {
+	if id == 0 || int(id) > len(r.snapshots) {
+		return nil, nil
+	}
+	return r.snapshots[id-1], nil
+}
+
+func (r *memoryHistoryRepository) profileSnapshots(profileID uint) []*model.ProfileSnapshot {
+	var snapshots []*model.ProfileSnapshot
+	for i := len(r.snapshots) - 1; i >= 0; i-- {
+		if r.snapshots[i].ProfileID == profileID {
+			snapshots = append(snapshots, r.snapshots[i])
+		}
+	}
+	return snapshots
+}
+
+func (r *memoryHistoryRepository) FindSnapshots(ctx context.Context, profileID uint, limit, offset int) ([]*model.ProfileSnapshot, error) {
+	snapshots := r.profileSnapshots(profileID)
+	if offset >= len(snapshots) {
+		return nil, nil
+	}
+	return snapshots[offset:min(offset+limit, len(snapshots))], nil
+}
+
+func (r *memoryHistoryRepository) CountSnapshots(ctx context.Context, profileID uint) (int64, error) {
+	return int64(len(r.profileSnapshots(profileID))), nil
+}
+
+// saveProfiles saves fetched states of a GitHub profile, failing the test on error
+func saveProfiles(t *testing.T, s *ProfileServiceImpl, profiles ...model.Profile) {
+	t.Helper()
+	for _, profile := range profiles {
+		profile.Username, profile.Platform = "johndoe", "GitHub"
+		if err := s.SaveProfile(context.Background(), &profile); err != nil {
+			t.Fatalf("SaveProfile() error = %v", err)
+		}
+	}
+}
+
+func TestSaveProfile(t *testing.T) {
+	repo := &memoryHistoryRepository{}
+	s := NewProfileService(ProfileServiceConfig{}, repo, nil).(*ProfileServiceImpl)
+
+	personID := uint(7)
+	saveProfiles(t, s, model.Profile{RealName: "John Doe", ImageURL: "https://avatars.example/1", FollowerCount: 10})
+	repo.profiles[0].PersonID = &personID
+	repo.profiles[0].ImageHash = "00ff00ff00ff00ff"
+	created := repo.profiles[0].LastUpdated
+
+	steps := []struct {
+		name      string
+		profile   model.Profile
+		snapshots int
+		imageHash string
+	}{
+		{"unchanged", model.Profile{RealName: "John Doe", ImageURL: "https://avatars.example/1", FollowerCount: 10}, 1, "00ff00ff00ff00ff"},
+		{"new bio", model.Profile{RealName: "John Doe", Bio: "Go developer", ImageURL: "https://avatars.example/1", FollowerCount: 10}, 2, "00ff00ff00ff00ff"},
+		{"new picture", model.Profile{RealName: "John Doe", Bio: "Go developer", ImageURL: "https://avatars.example/2", FollowerCount: 10}, 3, ""},
+	}
+	for _, step := range steps {
+		saveProfiles(t, s, step.profile)
+
+		if len(repo.profiles) != 1 {
+			t.Fatalf("%s: %d profiles stored, want 1", step.name, len(repo.profiles))
+		}
+		stored := repo.profiles[0]
+		if len(repo.snapshots) != step.snapshots {
+			t.Errorf("%s: %d snapshots, want %d", step.name, len(repo.snapshots), step.snapshots)
+		}
+		if stored.PersonID == nil || *stored.PersonID != personID {
+			t.Errorf("%s: person = %v, want %d", step.name, stored.PersonID, personID)
+		}
+		if stored.ImageHash != step.imageHash {
+			t.Errorf("%s: image hash = %q, want %q", step.name, stored.ImageHash, step.imageHash)
+		}
+		if stored.LastUpdated.Before(created) {
+			t.Errorf("%s: last updated %s before the creation at %s", step.name, stored.LastUpdated, created)
+		}
+	}
+
+	for _, snapshot := range repo.snapshots {
+		if snapshot.ProfileID != 1 {
+			t.Errorf("snapshot %d belongs to profile %d, want 1", snapshot.ID, snapshot.ProfileID)
+		}
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	repo := &memoryHistoryRepository{}
+	s := NewProfileService(ProfileServiceConfig{}, repo, nil).(*ProfileServiceImpl)
+	saveProfiles(t, s,
+		model.Profile{Bio: "one", FollowerCount: 1},
+		model.Profile{Bio: "two", FollowerCount: 1},
+		model.Profile{Bio: "two", FollowerCount: 3},
+	)
+
+	snapshots, total, err := s.ListSnapshots(context.Background(), 1, 2, 0)
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if total != 3 || len(snapshots) != 2 {
+		t.Fatalf("ListSnapshots() = %d snapshots of %d, want 2 of 3", len(snapshots), total)
+	}
+
+	// The oldest snapshot on the page is compared to the next page
+	want := []struct {
+		id       uint
+		previous uint
+		change   dto.ProfileFieldChangeDTO
+	}{
+		{3, 2, dto.ProfileFieldChangeDTO{Field: "follower_count", Old: "1", New: "3"}},
+		{2, 1, dto.ProfileFieldChangeDTO{Field: "bio", Old: "one", New: "two"}},
+	}
+	for i, w := range want {
+		snapshot := snapshots[i]
+		if snapshot.ID != w.id || snapshot.PreviousID == nil || *snapshot.PreviousID != w.previous {
+			t.Errorf("snapshot %d = %d after %v, want %d after %d", i, snapshot.ID, snapshot.PreviousID, w.id, w.previous)
+		}
+		if len(snapshot.Changes) != 1 || snapshot.Changes[0] != w.change {
+			t.Errorf("snapshot %d changes = %+v, want %+v", snapshot.ID, snapshot.Changes, w.change)
+		}
+	}
+
+	last, _, err := s.ListSnapshots(context.Background(), 1, 2, 2)
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(last) != 1 || last[0].PreviousID != nil || len(last[0].Changes) != 0 {
+		t.Errorf("first snapshot = %+v, want no previous snapshot", last)
+	}
+
+	if _, _, err := s.ListSnapshots(context.Background(), 2, 2, 0); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("ListSnapshots() of a missing profile error = %v, want %v", err, ErrProfileNotFound)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	repo := &memoryHistoryRepository{}
+	s := NewProfileService(ProfileServiceConfig{}, repo, nil).(*ProfileServiceImpl)
+	saveProfiles(t, s,
+		model.Profile{RealName: "John Doe", Bio: "one"},
+		model.Profile{RealName: "John Doe", Bio: "two"},
+		model.Profile{RealName: "Johnny Doe", Bio: "two"},
+	)
+	other := model.Profile{Username: "janedoe", Platform: "GitHub"}
+	if err := s.SaveProfile(context.Background(), &other); err != nil {
+		t.Fatalf("SaveProfile() error = %v", err)
+	}
+
+	diff, err := s.DiffSnapshots(context.Background(), 1, 1, 3)
+	if err != nil {
+		t.Fatalf("DiffSnapshots() error = %v", err)
+	}
+	want := []dto.ProfileFieldChangeDTO{
+		{Field: "real_name", Old: "John Doe", New: "Johnny Doe"},
+		{Field: "bio", Old: "one", New: "two"},
+	}
+	if diff.From.ID != 1 || diff.To.ID != 3 || len(diff.Changes) != 2 || diff.Changes[0] != want[0] || diff.Changes[1] != want[1] {
+		t.Errorf("DiffSnapshots() = %d -> %d with %+v, want 1 -> 3 with %+v", diff.From.ID, diff.To.ID, diff.Changes, want)
+	}
+
+	testCases := []struct {
+		name     string
+		profile  uint
+		from, to uint
+		err      error
+	}{
+		{"missing profile", 3, 1, 3, ErrProfileNotFound},
+		{"missing snapshot", 1, 1, 9, ErrProfileSnapshotNotFound},
+		{"snapshot of another profile", 1, 1, 4, ErrProfileSnapshotNotFound},
+	}
+	for _, tc := range testCases {
+		if _, err := s.DiffSnapshots(context.Background(), tc.profile, tc.from, tc.to); !errors.Is(err, tc.err) {
+			t.Errorf("%s: DiffSnapshots() error = %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestProfileFreshness(t *testing.T) {
+	s := NewProfileService(ProfileServiceConfig{
+		TTL:          24 * time.Hour,
+		PlatformTTLs: map[string]time.Duration{"Twitter": time.Hour},
+		StaleTTL:     48 * time.Hour,
+	}, nil, nil).(*ProfileServiceImpl)
+
+	now := time.Now()
+	testCases := []struct {
+		platform string
+		age      time.Duration
+		expected profileFreshness
+	}{
+		{"GitHub", time.Hour, profileFresh},
+		{"GitHub", 30 * time.Hour, profileStale},
+		{"GitHub", 80 * time.Hour, profileExpired},
+		{"Twitter", 30 * time.Minute, profileFresh},
+		{"Twitter", 2 * time.Hour, profileStale},
+		{"Twitter", 50 * time.Hour, profileExpired},
+	}
+
+	for _, tc := range testCases {
+		profile := &model.Profile{Platform: tc.platform, LastUpdated: now.Add(-tc.age)}
+		if result := s.freshness(profile, now); result != tc.expected {
+			t.Errorf("freshness(%s, %s): expected %d, got %d", tc.platform, tc.age, tc.expected, result)
+		}
+	}
+}
+
+func TestProfileNotFoundCache(t *testing.T) {
+	s := NewProfileService(ProfileServiceConfig{NotFoundTTL: time.Hour}, nil, nil).(*ProfileServiceImpl)
+	client := &notFoundClient{}
+	s.RegisterPlatformClient(client)
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.fetchCachedProfile(context.Background(), client, "ghost", "GitHub"); !errors.Is(err, api.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	}
+	if client.calls != 1 {
+		t.Errorf("Expected the platform to be asked once, got %d calls", client.calls)
+	}
+
+	// A refresh ignores the remembered result
+	if _, err := s.RefreshProfileByUsername(context.Background(), "ghost", "GitHub"); !errors.Is(err, api.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	if client.calls != 2 {
+		t.Errorf("Expected the refresh to ask the platform, got %d calls", client.calls)
+	}
+
+	s.notFound[profileCacheKey("GitHub", "ghost")] = time.Now().Add(-time.Second)
+	if s.isNotFound("GitHub", "ghost") {
+		t.Error("Expected an expired not found entry to be ignored")
+	}
+}
@@ -0,0 +1,519 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/intersection"
+	"github.com/accio/internal/metrics"
+	"github.com/accio/internal/output"
+	"github.com/accio/internal/scanner"
+	"github.com/accio/internal/sites"
+)
+
+// Scan job statuses
+const (
+	ScanJobQueued    = "queued"
+	ScanJobRunning   = "running"
+	ScanJobCompleted = "completed"
+	ScanJobCancelled = "cancelled"
+)
+
+// Scan event types
+const (
+	ScanEventStatus = "status"
+	ScanEventResult = "result"
+	ScanEventDone   = "done"
+)
+
+// Scan job errors
+var (
+	ErrScanJobNotFound = errors.New("scan job not found")
+	ErrScanJobFinished = errors.New("scan job already finished")
+	ErrScanQueueFull   = errors.New("scan queue is full")
+	ErrScanStopped     = errors.New("scan service is stopped")
+	ErrInvalidUsername = errors.New("invalid username")
+	ErrUnknownSite     = errors.New("unknown site")
+)
+
+// ScanJobService defines the interface for asynchronous username scans
+type ScanJobService interface {
+	// Enqueue validates a scan request and queues it for execution
+	Enqueue(ctx context.Context, request dto.CreateScanJobDTO) (*dto.ScanJobDTO, error)
+
+	// GetJob gets a scan job, including the results collected so far
+	GetJob(ctx context.Context, id string) (*dto.ScanJobDTO, error)
+
+	// GetSummary gets the results collected so far of a scan job, with the
+	// confidence analysis of the profiles found, for the output encoders
+	GetSummary(ctx context.Context, id string) (*dto.ScanJobDTO, *output.Summary, error)
+
+	// CancelJob cancels a queued or running scan job
+	CancelJob(ctx context.Context, id string) (*dto.ScanJobDTO, error)
+
+	// Subscribe streams the events of a scan job, replaying those already
+	// emitted. The channel is closed after the done event; call the returned
+	// function to unsubscribe early.
+	Subscribe(ctx context.Context, id string) (<-chan dto.ScanEventDTO, func(), error)
+
+	// QueueDepth returns the number of jobs waiting for a worker
+	QueueDepth() int
+
+	// Stop cancels all jobs and waits for the workers to exit
+	Stop()
+}
+
+// ScanJobServiceConfig configures the scan job service
+type ScanJobServiceConfig struct {
+	Workers   int             // Number of jobs run in parallel
+	QueueSize int             // Number of jobs that may wait for a worker
+	Retention time.Duration   // How long finished jobs are kept
+	Scanner   scanner.Options // Options for each job's scanner
+}
+
+// DefaultScanJobServiceConfig returns the default scan job service configuration
+func DefaultScanJobServiceConfig() ScanJobServiceConfig {
+	return ScanJobServiceConfig{
+		Workers:   2,
+		QueueSize: 16,
+		Retention: time.Hour,
+	}
+}
+
+// scanJob is the in-memory state of a single scan job
+type scanJob struct {
+	mu          sync.Mutex
+	id          string
+	username    string
+	sites       []sites.Site
+	status      string
+	err         string
+	results     []output.Result
+	createdAt   time.Time
+	startedAt   time.Time
+	finishedAt  time.Time
+	ctx         context.Context
+	cancel      context.CancelFunc
+	subscribers map[chan dto.ScanEventDTO]struct{}
+}
+
+// ScanJobServiceImpl implements the ScanJobService interface with a bounded
+// in-process queue and a fixed pool of workers
+type ScanJobServiceImpl struct {
+	config    ScanJobServiceConfig
+	publisher EventPublisher
+	queue     chan *scanJob
+	jobs      map[string]*scanJob
+	jobsMu    sync.RWMutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	stopped   bool
+}
+
+// NewScanJobService creates a new ScanJobServiceImpl and starts its workers.
+// Found accounts and completed jobs are published to publisher, if not nil.
+func NewScanJobService(config ScanJobServiceConfig, publisher EventPublisher) ScanJobService {
+	defaults := DefaultScanJobServiceConfig()
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.Retention <= 0 {
+		config.Retention = defaults.Retention
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &ScanJobServiceImpl{
+		config:    config,
+		publisher: publisher,
+		queue:     make(chan *scanJob, config.QueueSize),
+		jobs:      make(map[string]*scanJob),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	for i := 0; i < config.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	return s
+}
+
+// Enqueue validates a scan request and queues it for execution
+func (s *ScanJobServiceImpl) Enqueue(ctx context.Context, request dto.CreateScanJobDTO) (*dto.ScanJobDTO, error) {
+	username := strings.TrimSpace(request.Username)
+	if username == "" || strings.ContainsAny(username, "/?#% \t\n") {
+		return nil, ErrInvalidUsername
+	}
+
+	siteList, err := resolveSites(request.Sites)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	jobCtx, cancel := context.WithCancel(s.ctx)
+	job := &scanJob{
+		id:          id,
+		username:    username,
+		sites:       siteList,
+		status:      ScanJobQueued,
+		createdAt:   time.Now(),
+		ctx:         jobCtx,
+		cancel:      cancel,
+		subscribers: make(map[chan dto.ScanEventDTO]struct{}),
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	if s.stopped {
+		cancel()
+		return nil, ErrScanStopped
+	}
+
+	s.pruneLocked()
+
+	select {
+	case s.queue <- job:
+	default:
+		cancel()
+		return nil, ErrScanQueueFull
+	}
+	s.jobs[id] = job
+	metrics.ScanQueueDepth.Set(float64(len(s.queue)))
+
+	return job.toDTO(true), nil
+}
+
+// GetJob gets a scan job, including the results collected so far
+func (s *ScanJobServiceImpl) GetJob(ctx context.Context, id string) (*dto.ScanJobDTO, error) {
+	job, err := s.findJob(id)
+	if err != nil {
+		return nil, err
+	}
+	return job.toDTO(true), nil
+}
+
+// GetSummary gets the results collected so far of a scan job, with the
+// confidence analysis of the profiles found
+func (s *ScanJobServiceImpl) GetSummary(ctx context.Context, id string) (*dto.ScanJobDTO, *output.Summary, error) {
+	job, err := s.findJob(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jobDTO := job.toDTO(true)
+	summary := &output.Summary{Results: jobDTO.Results}
+	analysis := intersection.AnalyzeResults(map[string][]output.Result{jobDTO.Username: jobDTO.Results})
+	for _, match := range analysis.Matches {
+		if match.Username == jobDTO.Username {
+			summary.Analysis = match.Analysis()
+		}
+	}
+	return jobDTO, summary, nil
+}
+
+// CancelJob cancels a queued or running scan job
+func (s *ScanJobServiceImpl) CancelJob(ctx context.Context, id string) (*dto.ScanJobDTO, error) {
+	job, err := s.findJob(id)
+	if err != nil {
+		return nil, err
+	}
+
+	job.mu.Lock()
+	switch job.status {
+	case ScanJobQueued:
+		// The worker skips cancelled jobs when it dequeues them
+		job.finishLocked(ScanJobCancelled, "")
+	case ScanJobRunning:
+		// The worker marks the job cancelled once the scanner returns
+	default:
+		job.mu.Unlock()
+		return nil, ErrScanJobFinished
+	}
+	job.mu.Unlock()
+
+	job.cancel()
+	return job.toDTO(true), nil
+}
+
+// Subscribe streams the events of a scan job, replaying those already emitted
+func (s *ScanJobServiceImpl) Subscribe(ctx context.Context, id string) (<-chan dto.ScanEventDTO, func(), error) {
+	job, err := s.findJob(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+
+	// A subscriber receives at most two status events, one event per site
+	// and a done event, so a buffer of that size never blocks the publisher
+	events := make(chan dto.ScanEventDTO, len(job.sites)+3)
+	events <- dto.ScanEventDTO{Type: ScanEventStatus, Job: job.toDTOLocked(false)}
+	for i := range job.results {
+		result := job.results[i]
+		events <- dto.ScanEventDTO{Type: ScanEventResult, Result: &result}
+	}
+
+	if job.isFinishedLocked() {
+		events <- dto.ScanEventDTO{Type: ScanEventDone, Job: job.toDTOLocked(false)}
+		close(events)
+		return events, func() {}, nil
+	}
+
+	job.subscribers[events] = struct{}{}
+	unsubscribe := func() {
+		job.mu.Lock()
+		defer job.mu.Unlock()
+		if _, ok := job.subscribers[events]; ok {
+			delete(job.subscribers, events)
+			close(events)
+		}
+	}
+
+	return events, unsubscribe, nil
+}
+
+// QueueDepth returns the number of jobs waiting for a worker
+func (s *ScanJobServiceImpl) QueueDepth() int {
+	return len(s.queue)
+}
+
+// Stop cancels all jobs and waits for the workers to exit
+func (s *ScanJobServiceImpl) Stop() {
+	s.jobsMu.Lock()
+	if s.stopped {
+		s.jobsMu.Unlock()
+		return
+	}
+	s.stopped = true
+	s.jobsMu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+
+	// Finish jobs that never reached a worker
+	s.jobsMu.RLock()
+	defer s.jobsMu.RUnlock()
+	for _, job := range s.jobs {
+		job.mu.Lock()
+		if !job.isFinishedLocked() {
+			job.finishLocked(ScanJobCancelled, ErrScanStopped.Error())
+		}
+		job.mu.Unlock()
+	}
+}
+
+// worker runs queued jobs until the service is stopped
+func (s *ScanJobServiceImpl) worker() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case job := <-s.queue:
+			metrics.ScanQueueDepth.Set(float64(len(s.queue)))
+			s.run(job)
+		}
+	}
+}
+
+// run executes a single scan job
+func (s *ScanJobServiceImpl) run(job *scanJob) {
+	job.mu.Lock()
+	if job.status != ScanJobQueued {
+		job.mu.Unlock()
+		return
+	}
+	job.status = ScanJobRunning
+	job.startedAt = time.Now()
+	metrics.ScansStarted.Inc()
+	job.publishLocked(dto.ScanEventDTO{Type: ScanEventStatus, Job: job.toDTOLocked(false)})
+	job.mu.Unlock()
+
+	scanner.NewScanner(s.config.Scanner).
+		WithSites(job.sites).
+		Scan(job.ctx, job.username, func(result output.Result) {
+			job.mu.Lock()
+			job.results = append(job.results, result)
+			job.publishLocked(dto.ScanEventDTO{Type: ScanEventResult, Result: &result})
+			job.mu.Unlock()
+
+			if result.Exists {
+				publishEvent(s.publisher, model.EventAccountFound, dto.AccountFoundEventDTO{
+					Username: job.username,
+					Site:     result.Site,
+					URL:      result.URL,
+					ScanID:   job.id,
+				})
+			}
+		})
+
+	job.mu.Lock()
+	if job.ctx.Err() != nil {
+		job.finishLocked(ScanJobCancelled, "")
+		job.mu.Unlock()
+		job.cancel()
+		return
+	}
+	job.finishLocked(ScanJobCompleted, "")
+	event := dto.ScanCompletedEventDTO{Scan: job.toDTOLocked(false), Found: []output.Result{}}
+	for _, result := range job.results {
+		if result.Exists {
+			event.Found = append(event.Found, result)
+		}
+	}
+	job.mu.Unlock()
+	job.cancel()
+
+	publishEvent(s.publisher, model.EventScanCompleted, event)
+}
+
+// findJob finds a job by ID
+func (s *ScanJobServiceImpl) findJob(id string) (*scanJob, error) {
+	s.jobsMu.RLock()
+	defer s.jobsMu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrScanJobNotFound
+	}
+	return job, nil
+}
+
+// pruneLocked removes finished jobs older than the retention period.
+// The caller must hold jobsMu.
+func (s *ScanJobServiceImpl) pruneLocked() {
+	cutoff := time.Now().Add(-s.config.Retention)
+	for id, job := range s.jobs {
+		job.mu.Lock()
+		expired := job.isFinishedLocked() && job.finishedAt.Before(cutoff)
+		job.mu.Unlock()
+		if expired {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// isFinishedLocked reports whether the job reached a final status
+func (j *scanJob) isFinishedLocked() bool {
+	return j.status == ScanJobCompleted || j.status == ScanJobCancelled
+}
+
+// finishLocked sets a final status and notifies and releases all subscribers
+func (j *scanJob) finishLocked(status, errMsg string) {
+	j.status = status
+	j.err = errMsg
+	j.finishedAt = time.Now()
+	metrics.ScansFinished.WithLabelValues(status).Inc()
+
+	j.publishLocked(dto.ScanEventDTO{Type: ScanEventDone, Job: j.toDTOLocked(false)})
+	for events := range j.subscribers {
+		delete(j.subscribers, events)
+		close(events)
+	}
+}
+
+// publishLocked sends an event to all subscribers without blocking
+func (j *scanJob) publishLocked(event dto.ScanEventDTO) {
+	for events := range j.subscribers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// toDTO converts the job to a data transfer object
+func (j *scanJob) toDTO(includeResults bool) *dto.ScanJobDTO {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.toDTOLocked(includeResults)
+}
+
+// toDTOLocked converts the job to a data transfer object.
+// The caller must hold the job's lock.
+func (j *scanJob) toDTOLocked(includeResults bool) *dto.ScanJobDTO {
+	jobDTO := &dto.ScanJobDTO{
+		ID:        j.id,
+		Username:  j.username,
+		Status:    j.status,
+		Error:     j.err,
+		CreatedAt: j.createdAt,
+		Stats: dto.ScanStatsDTO{
+			Total:   len(j.sites),
+			Checked: len(j.results),
+		},
+	}
+
+	for _, result := range j.results {
+		switch {
+		case result.Error != nil:
+			jobDTO.Stats.Errors++
+		case result.Exists:
+			jobDTO.Stats.Found++
+		default:
+			jobDTO.Stats.NotFound++
+		}
+	}
+
+	if includeResults {
+		jobDTO.Results = append([]output.Result{}, j.results...)
+	}
+	if !j.startedAt.IsZero() {
+		startedAt := j.startedAt
+		jobDTO.StartedAt = &startedAt
+	}
+	if !j.finishedAt.IsZero() {
+		finishedAt := j.finishedAt
+		jobDTO.FinishedAt = &finishedAt
+	}
+
+	return jobDTO
+}
+
+// resolveSites looks up the requested sites, defaulting to all known sites
+func resolveSites(names []string) ([]sites.Site, error) {
+	if len(names) == 0 {
+		return sites.GetSites(), nil
+	}
+
+	siteList := make([]sites.Site, 0, len(names))
+	for _, name := range names {
+		site, ok := sites.GetSiteByName(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSite, name)
+		}
+		siteList = append(siteList, site)
+	}
+
+	return siteList, nil
+}
+
+// newJobID generates a random ID for a scan or resolution job
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
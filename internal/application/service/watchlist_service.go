@@ -0,0 +1,553 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+	"github.com/accio/internal/infrastructure/api"
+	"github.com/accio/internal/metrics"
+	"github.com/accio/internal/output"
+	"github.com/accio/internal/scanner"
+)
+
+// dueEntryBatchSize is the number of due entries loaded at a time
+const dueEntryBatchSize = 10
+
+// Watchlist errors
+var (
+	ErrWatchlistEntryNotFound = errors.New("watchlist entry not found")
+	ErrWatchlistEntryExists   = errors.New("username is already watched")
+	ErrInvalidInterval        = errors.New("invalid interval")
+)
+
+// WatchlistService defines the interface for watching usernames over time
+type WatchlistService interface {
+	// CreateEntry watches a username. The first run is due immediately and
+	// records the baseline snapshot; later runs raise alerts.
+	CreateEntry(ctx context.Context, request dto.CreateWatchlistEntryDTO) (*dto.WatchlistEntryDTO, error)
+
+	// GetEntry gets a watchlist entry
+	GetEntry(ctx context.Context, id uint) (*dto.WatchlistEntryDTO, error)
+
+	// ListEntries lists watchlist entries, along with the total count
+	ListEntries(ctx context.Context, limit, offset int) ([]*dto.WatchlistEntryDTO, int64, error)
+
+	// DeleteEntry stops watching a username and deletes its history
+	DeleteEntry(ctx context.Context, id uint) error
+
+	// RunEntry makes an entry due now and wakes the scheduler
+	RunEntry(ctx context.Context, id uint) (*dto.WatchlistEntryDTO, error)
+
+	// ListSnapshots lists the snapshots of an entry, latest first, along
+	// with the total count
+	ListSnapshots(ctx context.Context, id uint, limit, offset int) ([]*dto.WatchlistSnapshotDTO, int64, error)
+
+	// ListAlerts lists alerts, latest first, of one entry or of every entry
+	// if id is 0, along with the total count
+	ListAlerts(ctx context.Context, id uint, limit, offset int) ([]*dto.WatchlistAlertDTO, int64, error)
+
+	// Start starts the scheduler rerunning due entries in the background
+	Start()
+
+	// Stop stops the scheduler and waits for the current run to finish
+	Stop()
+}
+
+// WatchlistServiceConfig configures the watchlist service
+type WatchlistServiceConfig struct {
+	PollInterval    time.Duration   // How often the scheduler looks for due entries
+	DefaultInterval time.Duration   // Time between runs of entries created without one
+	MinInterval     time.Duration   // Shortest time allowed between runs
+	Scanner         scanner.Options // Options for each run's scanner
+}
+
+// DefaultWatchlistServiceConfig returns the default watchlist service configuration
+func DefaultWatchlistServiceConfig() WatchlistServiceConfig {
+	return WatchlistServiceConfig{
+		PollInterval:    time.Minute,
+		DefaultInterval: 24 * time.Hour,
+		MinInterval:     5 * time.Minute,
+	}
+}
+
+// WatchlistServiceImpl implements the WatchlistService interface with a
+// single scheduler goroutine running due entries one at a time
+type WatchlistServiceImpl struct {
+	config          WatchlistServiceConfig
+	watchlistRepo   repository.WatchlistRepository
+	platformClients map[string]api.PlatformClient
+	publisher       EventPublisher
+	wake            chan struct{}
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	startOnce       sync.Once
+}
+
+// NewWatchlistService creates a new WatchlistServiceImpl. Accounts found on
+// platforms with a client are fetched to compare their name, bio and picture.
+// Runs raising alerts are published to publisher, if not nil.
+func NewWatchlistService(config WatchlistServiceConfig, watchlistRepo repository.WatchlistRepository, platformClients map[string]api.PlatformClient, publisher EventPublisher) WatchlistService {
+	defaults := DefaultWatchlistServiceConfig()
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.DefaultInterval <= 0 {
+		config.DefaultInterval = defaults.DefaultInterval
+	}
+	if config.MinInterval <= 0 {
+		config.MinInterval = defaults.MinInterval
+	}
+
+	return &WatchlistServiceImpl{
+		config:          config,
+		watchlistRepo:   watchlistRepo,
+		platformClients: platformClients,
+		publisher:       publisher,
+		wake:            make(chan struct{}, 1),
+	}
+}
+
+// CreateEntry watches a username
+func (s *WatchlistServiceImpl) CreateEntry(ctx context.Context, request dto.CreateWatchlistEntryDTO) (*dto.WatchlistEntryDTO, error) {
+	username := strings.TrimSpace(request.Username)
+	if username == "" || strings.ContainsAny(username, "/?#% \t\n") {
+		return nil, ErrInvalidUsername
+	}
+
+	var siteNames []string
+	if len(request.Sites) > 0 {
+		siteList, err := resolveSites(request.Sites)
+		if err != nil {
+			return nil, err
+		}
+		for _, site := range siteList {
+			siteNames = append(siteNames, site.Name)
+		}
+	}
+
+	interval := s.config.DefaultInterval
+	if request.Interval != "" {
+		var err error
+		interval, err = time.ParseDuration(request.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInterval, err)
+		}
+		if interval < s.config.MinInterval {
+			return nil, fmt.Errorf("%w: interval must be at least %s", ErrInvalidInterval, s.config.MinInterval)
+		}
+	}
+
+	existing, err := s.watchlistRepo.FindEntryByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrWatchlistEntryExists
+	}
+
+	entry := model.NewWatchlistEntry(username, siteNames, interval)
+	if err := s.watchlistRepo.CreateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.wakeScheduler()
+
+	return dto.NewWatchlistEntryDTO(entry), nil
+}
+
+// GetEntry gets a watchlist entry
+func (s *WatchlistServiceImpl) GetEntry(ctx context.Context, id uint) (*dto.WatchlistEntryDTO, error) {
+	entry, err := s.findEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewWatchlistEntryDTO(entry), nil
+}
+
+// ListEntries lists watchlist entries, along with the total count
+func (s *WatchlistServiceImpl) ListEntries(ctx context.Context, limit, offset int) ([]*dto.WatchlistEntryDTO, int64, error) {
+	total, err := s.watchlistRepo.CountEntries(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries, err := s.watchlistRepo.FindEntries(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entryDTOs := make([]*dto.WatchlistEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		entryDTOs = append(entryDTOs, dto.NewWatchlistEntryDTO(entry))
+	}
+	return entryDTOs, total, nil
+}
+
+// DeleteEntry stops watching a username and deletes its history
+func (s *WatchlistServiceImpl) DeleteEntry(ctx context.Context, id uint) error {
+	if _, err := s.findEntry(ctx, id); err != nil {
+		return err
+	}
+	return s.watchlistRepo.DeleteEntry(ctx, id)
+}
+
+// RunEntry makes an entry due now and wakes the scheduler
+func (s *WatchlistServiceImpl) RunEntry(ctx context.Context, id uint) (*dto.WatchlistEntryDTO, error) {
+	entry, err := s.findEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.NextRunAt = time.Now()
+	if err := s.watchlistRepo.UpdateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.wakeScheduler()
+
+	return dto.NewWatchlistEntryDTO(entry), nil
+}
+
+// ListSnapshots lists the snapshots of an entry, latest first
+func (s *WatchlistServiceImpl) ListSnapshots(ctx context.Context, id uint, limit, offset int) ([]*dto.WatchlistSnapshotDTO, int64, error) {
+	if _, err := s.findEntry(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.watchlistRepo.CountSnapshots(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	snapshots, err := s.watchlistRepo.FindSnapshots(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	snapshotDTOs := make([]*dto.WatchlistSnapshotDTO, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapshotDTOs = append(snapshotDTOs, dto.NewWatchlistSnapshotDTO(snapshot))
+	}
+	return snapshotDTOs, total, nil
+}
+
+// ListAlerts lists alerts, latest first, of one entry or of every entry
+func (s *WatchlistServiceImpl) ListAlerts(ctx context.Context, id uint, limit, offset int) ([]*dto.WatchlistAlertDTO, int64, error) {
+	if id != 0 {
+		if _, err := s.findEntry(ctx, id); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	total, err := s.watchlistRepo.CountAlerts(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	alerts, err := s.watchlistRepo.FindAlerts(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	alertDTOs := make([]*dto.WatchlistAlertDTO, 0, len(alerts))
+	for _, alert := range alerts {
+		alertDTOs = append(alertDTOs, dto.NewWatchlistAlertDTO(alert))
+	}
+	return alertDTOs, total, nil
+}
+
+// Start starts the scheduler rerunning due entries in the background
+func (s *WatchlistServiceImpl) Start() {
+	s.startOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancel = cancel
+		s.wg.Add(1)
+		go s.schedule(ctx)
+	})
+}
+
+// Stop stops the scheduler and waits for the current run to finish
+func (s *WatchlistServiceImpl) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// schedule runs due entries on every tick and wake-up until ctx is done
+func (s *WatchlistServiceImpl) schedule(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// runDue runs every due entry, one at a time. Each entry runs at most once
+// per call, and the call returns once a batch schedules no entry, so entries
+// whose next run cannot be stored wait for the next tick.
+func (s *WatchlistServiceImpl) runDue(ctx context.Context) {
+	attempted := make(map[uint]bool)
+	for ctx.Err() == nil {
+		entries, err := s.watchlistRepo.FindDueEntries(ctx, time.Now(), dueEntryBatchSize)
+		if err != nil {
+			log.Printf("Error finding due watchlist entries: %v", err)
+			return
+		}
+
+		var batch []*model.WatchlistEntry
+		for _, entry := range entries {
+			if !attempted[entry.ID] {
+				attempted[entry.ID] = true
+				batch = append(batch, entry)
+			}
+		}
+		if len(batch) == 0 {
+			return
+		}
+
+		var scheduled int
+		for _, entry := range batch {
+			ok, err := s.run(ctx, entry)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("Error running watchlist entry %d (%s): %v", entry.ID, entry.Username, err)
+			}
+			if ok {
+				scheduled++
+			}
+		}
+
+		if scheduled == 0 {
+			return
+		}
+	}
+}
+
+// run runs an entry and schedules its next run, reporting whether the next
+// run was stored. Entries that fail are retried on their schedule rather
+// than on every poll; entries interrupted by Stop stay due.
+func (s *WatchlistServiceImpl) run(ctx context.Context, entry *model.WatchlistEntry) (bool, error) {
+	startedAt := time.Now()
+	err := s.snapshot(ctx, entry)
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	entry.LastRunAt = &startedAt
+	entry.NextRunAt = startedAt.Add(entry.Interval)
+	if updateErr := s.watchlistRepo.UpdateEntry(ctx, entry); updateErr != nil {
+		if err == nil {
+			err = updateErr
+		}
+		return false, err
+	}
+	return true, err
+}
+
+// snapshot scans an entry and stores the snapshot with the alerts raised
+// against the previous one
+func (s *WatchlistServiceImpl) snapshot(ctx context.Context, entry *model.WatchlistEntry) error {
+	siteList, err := resolveSites(entry.SiteList())
+	if err != nil {
+		return err
+	}
+
+	var results []output.Result
+	var mu sync.Mutex
+	scanner.NewScanner(s.config.Scanner).
+		WithSites(siteList).
+		Scan(ctx, entry.Username, func(result output.Result) {
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, result)
+		})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	snapshot := &model.WatchlistSnapshot{EntryID: entry.ID, Total: len(results), CreatedAt: time.Now()}
+	for _, result := range results {
+		account := model.WatchlistAccount{Site: result.Site, URL: result.URL, Exists: result.Exists}
+		if result.Error != nil {
+			account.Error = result.Error.Error()
+		}
+		if result.Exists {
+			snapshot.Found++
+			s.fetchProfile(ctx, entry.Username, &account)
+		}
+		snapshot.Accounts = append(snapshot.Accounts, account)
+	}
+
+	previous, err := s.watchlistRepo.FindLatestSnapshot(ctx, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	var alerts []*model.WatchlistAlert
+	if previous != nil {
+		alerts = compareSnapshots(entry, previous, snapshot)
+	}
+	if err := s.watchlistRepo.CreateSnapshot(ctx, snapshot, alerts); err != nil {
+		return err
+	}
+
+	metrics.WatchlistRuns.Inc()
+	for _, alert := range alerts {
+		metrics.WatchlistAlerts.WithLabelValues(alert.Kind).Inc()
+		log.Printf("Watchlist alert: %s %s on %s", alert.Username, alert.Kind, alert.Site)
+	}
+
+	// The snapshot is not stored if the entry was deleted during the run
+	if snapshot.ID != 0 && len(alerts) > 0 {
+		s.publishAlerts(entry, snapshot, alerts)
+	}
+	return nil
+}
+
+// publishAlerts publishes the alerts raised by a run as a watchlist.changed
+// event, along with an account.found event for each account that appeared
+func (s *WatchlistServiceImpl) publishAlerts(entry *model.WatchlistEntry, snapshot *model.WatchlistSnapshot, alerts []*model.WatchlistAlert) {
+	event := dto.WatchlistChangedEventDTO{
+		Entry:      dto.NewWatchlistEntryDTO(entry),
+		SnapshotID: snapshot.ID,
+		Alerts:     make([]*dto.WatchlistAlertDTO, 0, len(alerts)),
+	}
+	for _, alert := range alerts {
+		event.Alerts = append(event.Alerts, dto.NewWatchlistAlertDTO(alert))
+	}
+	publishEvent(s.publisher, model.EventWatchlistChanged, event)
+
+	for _, alert := range alerts {
+		if alert.Kind == model.AlertAppeared {
+			publishEvent(s.publisher, model.EventAccountFound, dto.AccountFoundEventDTO{
+				Username:         entry.Username,
+				Site:             alert.Site,
+				URL:              alert.URL,
+				WatchlistEntryID: entry.ID,
+			})
+		}
+	}
+}
+
+// fetchProfile fills the name, bio and picture of an account from the
+// platform API, if the site has a client
+func (s *WatchlistServiceImpl) fetchProfile(ctx context.Context, username string, account *model.WatchlistAccount) {
+	client, ok := s.platformClients[account.Site]
+	if !ok {
+		return
+	}
+
+	profile, err := client.GetProfileByUsername(ctx, username)
+	if err != nil || profile == nil {
+		return
+	}
+
+	account.Fetched = true
+	account.Name = profile.RealName
+	account.Bio = profile.Bio
+	account.ImageURL = profile.ImageURL
+}
+
+// findEntry finds an entry by ID
+func (s *WatchlistServiceImpl) findEntry(ctx context.Context, id uint) (*model.WatchlistEntry, error) {
+	entry, err := s.watchlistRepo.FindEntryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrWatchlistEntryNotFound
+	}
+	return entry, nil
+}
+
+// wakeScheduler makes the scheduler look for due entries without waiting
+// for the next tick
+func (s *WatchlistServiceImpl) wakeScheduler() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// compareSnapshots returns the alerts raised by the changes from the
+// previous snapshot. Accounts appear and disappear as in output.DiffResults,
+// so checks that failed raise no alerts. Names, bios and pictures are
+// compared when both snapshots fetched them from the platform API.
+func compareSnapshots(entry *model.WatchlistEntry, previous, current *model.WatchlistSnapshot) []*model.WatchlistAlert {
+	diff := output.DiffResults(snapshotResults(previous), snapshotResults(current))
+
+	var alerts []*model.WatchlistAlert
+	newAlert := func(kind, site, url, old, new string) {
+		alerts = append(alerts, &model.WatchlistAlert{
+			EntryID:   entry.ID,
+			Username:  entry.Username,
+			Kind:      kind,
+			Site:      site,
+			URL:       url,
+			Old:       old,
+			New:       new,
+			CreatedAt: current.CreatedAt,
+		})
+	}
+
+	for _, change := range diff.Claimed {
+		newAlert(model.AlertAppeared, change.Site, change.URL, "", "")
+	}
+	for _, change := range diff.Disappeared {
+		newAlert(model.AlertDisappeared, change.Site, change.URL, "", "")
+	}
+
+	before := make(map[string]model.WatchlistAccount, len(previous.Accounts))
+	for _, account := range previous.Accounts {
+		before[account.Site] = account
+	}
+	for _, account := range current.Accounts {
+		old, ok := before[account.Site]
+		if !ok || !old.Fetched || !account.Fetched {
+			continue
+		}
+		if old.Name != account.Name {
+			newAlert(model.AlertNameChanged, account.Site, account.URL, old.Name, account.Name)
+		}
+		if old.Bio != account.Bio {
+			newAlert(model.AlertBioChanged, account.Site, account.URL, old.Bio, account.Bio)
+		}
+		if old.ImageURL != account.ImageURL {
+			newAlert(model.AlertAvatarChanged, account.Site, account.URL, old.ImageURL, account.ImageURL)
+		}
+	}
+
+	return alerts
+}
+
+// snapshotResults converts the accounts of a snapshot to results
+func snapshotResults(snapshot *model.WatchlistSnapshot) []output.Result {
+	results := make([]output.Result, 0, len(snapshot.Accounts))
+	for _, account := range snapshot.Accounts {
+		result := output.Result{Site: account.Site, URL: account.URL, Exists: account.Exists}
+		if account.Error != "" {
+			result.Error = errors.New(account.Error)
+		}
+		results = append(results, result)
+	}
+	return results
+}
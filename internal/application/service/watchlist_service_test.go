@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+)
+
+// stuckWatchlistRepository keeps its entries due by failing every update
+type stuckWatchlistRepository struct {
+	repository.WatchlistRepository
+	entries []*model.WatchlistEntry
+	updates int
+}
+
+func (r *stuckWatchlistRepository) FindDueEntries(ctx context.Context, now time.Time, limit int) ([]*model.WatchlistEntry, error) {
+	return r.entries[:min(limit, len(r.entries))], nil
+}
+
+func (r *stuckWatchlistRepository) UpdateEntry(ctx context.Context, entry *model.WatchlistEntry) error {
+	r.updates++
+	return errors.New("database is locked")
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	entry := &model.WatchlistEntry{ID: 1, Username: "alice"}
+	previous := &model.WatchlistSnapshot{Accounts: []model.WatchlistAccount{
+		{Site: "GitHub", URL: "https://github.com/alice", Exists: true, Fetched: true, Name: "Alice", Bio: "old", ImageURL: "a.png"},
+		{Site: "GitLab", URL: "https://gitlab.com/alice", Exists: true},
+		{Site: "Reddit", URL: "https://reddit.com/u/alice"},
+		{Site: "Twitter", URL: "https://twitter.com/alice", Exists: true, Fetched: true, Name: "Alice"},
+	}}
+	current := &model.WatchlistSnapshot{Accounts: []model.WatchlistAccount{
+		{Site: "GitHub", URL: "https://github.com/alice", Exists: true, Fetched: true, Name: "Alice A.", Bio: "new", ImageURL: "b.png"},
+		{Site: "GitLab", URL: "https://gitlab.com/alice"},
+		{Site: "Reddit", URL: "https://reddit.com/u/alice", Exists: true},
+		{Site: "Twitter", URL: "https://twitter.com/alice", Exists: true, Name: "Someone else"},
+	}}
+
+	alerts := compareSnapshots(entry, previous, current)
+
+	want := map[string]string{
+		model.AlertAppeared:      "Reddit",
+		model.AlertDisappeared:   "GitLab",
+		model.AlertNameChanged:   "GitHub",
+		model.AlertBioChanged:    "GitHub",
+		model.AlertAvatarChanged: "GitHub",
+	}
+	if len(alerts) != len(want) {
+		t.Fatalf("got %d alerts, want %d: %+v", len(alerts), len(want), alerts)
+	}
+	for _, alert := range alerts {
+		if site, ok := want[alert.Kind]; !ok || alert.Site != site {
+			t.Errorf("unexpected %s alert for %s", alert.Kind, alert.Site)
+		}
+		if alert.EntryID != entry.ID || alert.Username != entry.Username {
+			t.Errorf("alert not attributed to the entry: %+v", alert)
+		}
+	}
+}
+
+func TestCompareSnapshotsNoChanges(t *testing.T) {
+	entry := &model.WatchlistEntry{ID: 1, Username: "alice"}
+	snapshot := &model.WatchlistSnapshot{Accounts: []model.WatchlistAccount{
+		{Site: "GitHub", URL: "https://github.com/alice", Exists: true, Fetched: true, Name: "Alice"},
+		{Site: "GitLab", URL: "https://gitlab.com/alice", Error: "timeout"},
+	}}
+
+	if alerts := compareSnapshots(entry, snapshot, snapshot); len(alerts) != 0 {
+		t.Errorf("got %d alerts for identical snapshots: %+v", len(alerts), alerts)
+	}
+}
+
+func TestRunDueStopsWhenUpdatesFail(t *testing.T) {
+	repo := &stuckWatchlistRepository{}
+	for id := uint(1); id <= 3; id++ {
+		// The unknown site fails the run before anything is scanned
+		entry := model.NewWatchlistEntry("octocat", []string{"NoSuchSite"}, time.Hour)
+		entry.ID = id
+		repo.entries = append(repo.entries, entry)
+	}
+	s := NewWatchlistService(WatchlistServiceConfig{}, repo, nil, nil).(*WatchlistServiceImpl)
+
+	done := make(chan struct{})
+	go func() {
+		s.runDue(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("runDue() did not return")
+	}
+
+	// The entries stay due, but each runs once until the next tick
+	if repo.updates != 3 {
+		t.Errorf("UpdateEntry() called %d times, want 3", repo.updates)
+	}
+}
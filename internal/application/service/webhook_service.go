@@ -0,0 +1,600 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+	"github.com/accio/internal/metrics"
+)
+
+// Webhook request headers
+const (
+	WebhookEventHeader     = "X-Accio-Event"         // Event type
+	WebhookDeliveryHeader  = "X-Accio-Delivery"      // Event ID, the same for retries and replays
+	WebhookSignatureHeader = "X-Accio-Signature-256" // "sha256=" followed by the hex HMAC-SHA256 of the body
+)
+
+// dueDeliveryBatchSize is the number of due deliveries sent at a time
+const dueDeliveryBatchSize = 10
+
+// Webhook errors
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL       = errors.New("invalid webhook URL")
+	ErrUnknownEvent            = errors.New("unknown event type")
+	ErrForbiddenWebhookTarget  = errors.New("webhook target address is not allowed")
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, which some clouds use
+// for their metadata services
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// EventPublisher publishes events to subscribers outside the process
+type EventPublisher interface {
+	// Publish queues an event for every webhook subscribed to its type
+	Publish(ctx context.Context, event string, data interface{}) error
+}
+
+// WebhookService defines the interface for managing and delivering webhooks
+type WebhookService interface {
+	EventPublisher
+
+	// CreateWebhook creates a webhook, generating its secret if none is given
+	CreateWebhook(ctx context.Context, request dto.CreateWebhookDTO) (*dto.WebhookDTO, error)
+
+	// GetWebhook gets a webhook, without its secret
+	GetWebhook(ctx context.Context, id uint) (*dto.WebhookDTO, error)
+
+	// ListWebhooks lists webhooks, without their secrets, along with the total count
+	ListWebhooks(ctx context.Context, limit, offset int) ([]*dto.WebhookDTO, int64, error)
+
+	// DeleteWebhook deletes a webhook and its delivery log
+	DeleteWebhook(ctx context.Context, id uint) error
+
+	// ListDeliveries lists the deliveries of a webhook, latest first, along
+	// with the total count
+	ListDeliveries(ctx context.Context, id uint, limit, offset int) ([]*dto.WebhookDeliveryDTO, int64, error)
+
+	// ReplayDelivery queues a new delivery of the payload of an earlier one
+	ReplayDelivery(ctx context.Context, id uint) (*dto.WebhookDeliveryDTO, error)
+
+	// Start starts the dispatcher sending due deliveries in the background
+	Start()
+
+	// Stop stops the dispatcher and waits for the current attempts to finish
+	Stop()
+}
+
+// WebhookServiceConfig configures the webhook service
+type WebhookServiceConfig struct {
+	PollInterval time.Duration // How often the dispatcher looks for due deliveries
+	Timeout      time.Duration // Timeout of each attempt
+	MaxAttempts  int           // Attempts before a delivery is marked failed
+	BaseBackoff  time.Duration // Delay before the first retry, doubled for each later one
+	MaxBackoff   time.Duration // Longest delay between two attempts
+
+	// AllowPrivateTargets allows webhooks to loopback, private and
+	// link-local addresses, which are refused by default so webhooks cannot
+	// reach internal services
+	AllowPrivateTargets bool
+}
+
+// DefaultWebhookServiceConfig returns the default webhook service configuration
+func DefaultWebhookServiceConfig() WebhookServiceConfig {
+	return WebhookServiceConfig{
+		PollInterval: 5 * time.Second,
+		Timeout:      10 * time.Second,
+		MaxAttempts:  8,
+		BaseBackoff:  30 * time.Second,
+		MaxBackoff:   time.Hour,
+	}
+}
+
+// WebhookServiceImpl implements the WebhookService interface. Deliveries
+// are queued in the database so retries survive restarts.
+type WebhookServiceImpl struct {
+	config      WebhookServiceConfig
+	webhookRepo repository.WebhookRepository
+	client      *http.Client
+	wake        chan struct{}
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	startOnce   sync.Once
+}
+
+// NewWebhookService creates a new WebhookServiceImpl
+func NewWebhookService(config WebhookServiceConfig, webhookRepo repository.WebhookRepository) WebhookService {
+	defaults := DefaultWebhookServiceConfig()
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaults.BaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaults.MaxBackoff
+	}
+
+	return &WebhookServiceImpl{
+		config:      config,
+		webhookRepo: webhookRepo,
+		client:      newWebhookClient(config),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// newWebhookClient creates the HTTP client posting deliveries. Unless
+// private targets are allowed, it refuses to connect to a forbidden
+// address, checked on the address actually dialed so a host cannot resolve
+// to a public address at creation and a private one later. Redirects are
+// not followed, and proxies are not used since they would dial in our stead.
+func newWebhookClient(config WebhookServiceConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	if !config.AllowPrivateTargets {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isForbiddenWebhookIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenWebhookTarget, host)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: config.Timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: config.Timeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// CreateWebhook creates a webhook
+func (s *WebhookServiceImpl) CreateWebhook(ctx context.Context, request dto.CreateWebhookDTO) (*dto.WebhookDTO, error) {
+	target, err := url.Parse(strings.TrimSpace(request.URL))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("%w: must be an absolute http or https URL", ErrInvalidWebhookURL)
+	}
+	if !s.config.AllowPrivateTargets {
+		if err := checkWebhookHost(ctx, target.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(request.Events) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", ErrUnknownEvent)
+	}
+	var events []string
+	seen := make(map[string]bool)
+	for _, event := range request.Events {
+		if !isEventType(event) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		if secret, err = randomHex(32); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := model.NewWebhook(target.String(), secret, events)
+	if err := s.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	webhookDTO := dto.NewWebhookDTO(webhook)
+	webhookDTO.Secret = webhook.Secret
+	return webhookDTO, nil
+}
+
+// GetWebhook gets a webhook
+func (s *WebhookServiceImpl) GetWebhook(ctx context.Context, id uint) (*dto.WebhookDTO, error) {
+	webhook, err := s.findWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewWebhookDTO(webhook), nil
+}
+
+// ListWebhooks lists webhooks
+func (s *WebhookServiceImpl) ListWebhooks(ctx context.Context, limit, offset int) ([]*dto.WebhookDTO, int64, error) {
+	total, err := s.webhookRepo.CountWebhooks(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	webhooks, err := s.webhookRepo.FindWebhooks(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	webhookDTOs := make([]*dto.WebhookDTO, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookDTOs = append(webhookDTOs, dto.NewWebhookDTO(webhook))
+	}
+	return webhookDTOs, total, nil
+}
+
+// DeleteWebhook deletes a webhook and its delivery log
+func (s *WebhookServiceImpl) DeleteWebhook(ctx context.Context, id uint) error {
+	if _, err := s.findWebhook(ctx, id); err != nil {
+		return err
+	}
+	return s.webhookRepo.DeleteWebhook(ctx, id)
+}
+
+// ListDeliveries lists the deliveries of a webhook, latest first
+func (s *WebhookServiceImpl) ListDeliveries(ctx context.Context, id uint, limit, offset int) ([]*dto.WebhookDeliveryDTO, int64, error) {
+	if _, err := s.findWebhook(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.webhookRepo.CountDeliveries(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, err := s.webhookRepo.FindDeliveries(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deliveryDTOs := make([]*dto.WebhookDeliveryDTO, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryDTOs = append(deliveryDTOs, dto.NewWebhookDeliveryDTO(delivery))
+	}
+	return deliveryDTOs, total, nil
+}
+
+// ReplayDelivery queues a new delivery of the payload of an earlier one.
+// The event ID is kept so receivers can recognize the event.
+func (s *WebhookServiceImpl) ReplayDelivery(ctx context.Context, id uint) (*dto.WebhookDeliveryDTO, error) {
+	delivery, err := s.webhookRepo.FindDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	replay := model.NewWebhookDelivery(delivery.WebhookID, delivery.EventID, delivery.Event, delivery.Payload)
+	if err := s.webhookRepo.CreateDeliveries(ctx, []*model.WebhookDelivery{replay}); err != nil {
+		return nil, err
+	}
+
+	s.wakeDispatcher()
+	return dto.NewWebhookDeliveryDTO(replay), nil
+}
+
+// Publish queues an event for every webhook subscribed to its type
+func (s *WebhookServiceImpl) Publish(ctx context.Context, event string, data interface{}) error {
+	webhooks, err := s.webhookRepo.FindAllWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	var subscribers []*model.Webhook
+	for _, webhook := range webhooks {
+		if webhook.Subscribes(event) {
+			subscribers = append(subscribers, webhook)
+		}
+	}
+	if len(subscribers) == 0 {
+		return nil
+	}
+
+	eventID := uuid.NewString()
+	payload, err := json.Marshal(dto.WebhookEventDTO{
+		ID:        eventID,
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event, err)
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0, len(subscribers))
+	for _, webhook := range subscribers {
+		deliveries = append(deliveries, model.NewWebhookDelivery(webhook.ID, eventID, event, string(payload)))
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	s.wakeDispatcher()
+	return nil
+}
+
+// Start starts the dispatcher sending due deliveries in the background
+func (s *WebhookServiceImpl) Start() {
+	s.startOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancel = cancel
+		s.wg.Add(1)
+		go s.dispatch(ctx)
+	})
+}
+
+// Stop stops the dispatcher and waits for the current attempts to finish
+func (s *WebhookServiceImpl) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// dispatch sends due deliveries on every tick and wake-up until ctx is done
+func (s *WebhookServiceImpl) dispatch(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// deliverDue sends every due delivery, a batch at a time. Each delivery is
+// attempted at most once per call, and a batch in which no outcome could be
+// recorded ends the call, so deliveries that stay due because the database
+// fails are retried on the next tick rather than in a busy loop.
+func (s *WebhookServiceImpl) deliverDue(ctx context.Context) {
+	attempted := make(map[uint]bool)
+	for ctx.Err() == nil {
+		deliveries, err := s.webhookRepo.FindDueDeliveries(ctx, time.Now(), dueDeliveryBatchSize)
+		if err != nil {
+			log.Printf("Error finding due webhook deliveries: %v", err)
+			return
+		}
+
+		var batch []*model.WebhookDelivery
+		for _, delivery := range deliveries {
+			if !attempted[delivery.ID] {
+				attempted[delivery.ID] = true
+				batch = append(batch, delivery)
+			}
+		}
+		if len(batch) == 0 {
+			return
+		}
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			recorded int
+		)
+		for _, delivery := range batch {
+			wg.Add(1)
+			go func(delivery *model.WebhookDelivery) {
+				defer wg.Done()
+				if err := s.deliver(ctx, delivery); err != nil {
+					if ctx.Err() == nil {
+						log.Printf("Error delivering webhook delivery %d: %v", delivery.ID, err)
+					}
+					return
+				}
+				mu.Lock()
+				recorded++
+				mu.Unlock()
+			}(delivery)
+		}
+		wg.Wait()
+
+		if recorded == 0 {
+			return
+		}
+	}
+}
+
+// deliver makes one attempt at a delivery and records its outcome,
+// scheduling a retry with exponential backoff if attempts remain.
+// Attempts interrupted by Stop are not counted.
+func (s *WebhookServiceImpl) deliver(ctx context.Context, delivery *model.WebhookDelivery) error {
+	webhook, err := s.webhookRepo.FindWebhookByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if webhook == nil {
+		// The webhook was deleted after the delivery was queued
+		delivery.Status = model.DeliveryFailed
+		delivery.Error = ErrWebhookNotFound.Error()
+		metrics.WebhookDeliveries.WithLabelValues(delivery.Event, model.DeliveryFailed).Inc()
+		return s.webhookRepo.UpdateDelivery(ctx, delivery)
+	}
+
+	status, err := s.post(ctx, webhook, delivery)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	outcome := model.DeliverySucceeded
+	switch {
+	case err == nil:
+		delivery.Status = model.DeliverySucceeded
+		delivery.Error = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= s.config.MaxAttempts:
+		delivery.Status = model.DeliveryFailed
+		delivery.Error = err.Error()
+		outcome = model.DeliveryFailed
+	default:
+		delivery.Error = err.Error()
+		delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+		outcome = "retried"
+	}
+	metrics.WebhookDeliveries.WithLabelValues(delivery.Event, outcome).Inc()
+
+	return s.webhookRepo.UpdateDelivery(ctx, delivery)
+}
+
+// post sends the payload of a delivery to its webhook, returning the
+// response status and an error unless the status is 2xx
+func (s *WebhookServiceImpl) post(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Accio-Webhook/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.EventID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, []byte(delivery.Payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the next attempt after a number of
+// failed attempts
+func (s *WebhookServiceImpl) backoff(attempts int) time.Duration {
+	delay := s.config.BaseBackoff
+	for i := 1; i < attempts && delay < s.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxBackoff {
+		delay = s.config.MaxBackoff
+	}
+	return delay
+}
+
+// checkWebhookHost returns ErrForbiddenWebhookTarget if the host is or
+// resolves to a forbidden address, and ErrInvalidWebhookURL if it does not
+// resolve
+func checkWebhookHost(ctx context.Context, host string) error {
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return fmt.Errorf("%w: cannot resolve %s", ErrInvalidWebhookURL, host)
+		}
+		ips = ips[:0]
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if isForbiddenWebhookIP(ip) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenWebhookTarget, host, ip)
+		}
+	}
+	return nil
+}
+
+// isForbiddenWebhookIP reports whether webhooks must not be posted to an
+// address: loopback, private, link-local (including cloud metadata
+// services), shared, unspecified or multicast
+func isForbiddenWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsMulticast() ||
+		ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+// findWebhook finds a webhook by ID, returning ErrWebhookNotFound if it does not exist
+func (s *WebhookServiceImpl) findWebhook(ctx context.Context, id uint) (*model.Webhook, error) {
+	webhook, err := s.webhookRepo.FindWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// wakeDispatcher makes the dispatcher look for due deliveries without waiting for the next tick
+func (s *WebhookServiceImpl) wakeDispatcher() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SignWebhookPayload returns the signature header value of a payload:
+// "sha256=" followed by the hex HMAC-SHA256 of the payload keyed by secret
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isEventType reports whether event is a known webhook event type
+func isEventType(event string) bool {
+	for _, eventType := range model.EventTypes {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// publishEvent publishes an event if a publisher is set. Failures are
+// logged rather than failing the work that raised the event.
+func publishEvent(publisher EventPublisher, event string, data interface{}) {
+	if publisher == nil {
+		return
+	}
+	if err := publisher.Publish(context.Background(), event, data); err != nil {
+		log.Printf("Error publishing %s event: %v", event, err)
+	}
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+)
+
+// memoryWebhookRepository keeps webhooks and deliveries in memory, failing
+// delivery updates when failUpdates is set
+type memoryWebhookRepository struct {
+	repository.WebhookRepository
+	mu          sync.Mutex
+	webhooks    map[uint]*model.Webhook
+	deliveries  []*model.WebhookDelivery
+	failUpdates bool
+}
+
+func (r *memoryWebhookRepository) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	webhook.ID = uint(len(r.webhooks) + 1)
+	r.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (r *memoryWebhookRepository) FindWebhookByID(ctx context.Context, id uint) (*model.Webhook, error) {
+	return r.webhooks[id], nil
+}
+
+func (r *memoryWebhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	if r.failUpdates {
+		return errors.New("database is locked")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.deliveries {
+		if stored.ID == delivery.ID {
+			copied := *delivery
+			r.deliveries[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (r *memoryWebhookRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*model.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == model.DeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			copied := *delivery
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+// runDeliverDue runs deliverDue, failing the test if it does not return
+func runDeliverDue(t *testing.T, s *WebhookServiceImpl) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		s.deliverDue(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deliverDue() did not return")
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// HMAC-SHA256 test vector from RFC 4231, test case 2
+	got := SignWebhookPayload("Jefe", []byte("what do ya want for nothing?"))
+	want := "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("SignWebhookPayload() = %s, want %s", got, want)
+	}
+}
+
+func TestWebhookPost(t *testing.T) {
+	payload := `{"id":"abc","event":"scan.completed","data":{}}`
+
+	var request *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		request, body = r, string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s := NewWebhookService(WebhookServiceConfig{AllowPrivateTargets: true}, nil).(*WebhookServiceImpl)
+	webhook := &model.Webhook{URL: server.URL, Secret: "secret"}
+	delivery := model.NewWebhookDelivery(1, "abc", model.EventScanCompleted, payload)
+
+	status, err := s.post(context.Background(), webhook, delivery)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("post() = %d, %v", status, err)
+	}
+	if body != payload {
+		t.Errorf("body = %s, want %s", body, payload)
+	}
+	headers := map[string]string{
+		"Content-Type":         "application/json",
+		WebhookEventHeader:     model.EventScanCompleted,
+		WebhookDeliveryHeader:  "abc",
+		WebhookSignatureHeader: SignWebhookPayload("secret", []byte(payload)),
+	}
+	for name, want := range headers {
+		if got := request.Header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestWebhookPostRejectsNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	s := NewWebhookService(WebhookServiceConfig{AllowPrivateTargets: true}, nil).(*WebhookServiceImpl)
+	webhook := &model.Webhook{URL: server.URL}
+	delivery := model.NewWebhookDelivery(1, "abc", model.EventAccountFound, "{}")
+
+	status, err := s.post(context.Background(), webhook, delivery)
+	if err == nil || status != http.StatusBadGateway {
+		t.Errorf("post() = %d, %v, want %d and an error", status, err, http.StatusBadGateway)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	s := NewWebhookService(WebhookServiceConfig{
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  5 * time.Minute,
+	}, nil).(*WebhookServiceImpl)
+
+	want := []time.Duration{
+		30 * time.Second,
+		time.Minute,
+		2 * time.Minute,
+		4 * time.Minute,
+		5 * time.Minute,
+		5 * time.Minute,
+	}
+	for i, delay := range want {
+		if got := s.backoff(i + 1); got != delay {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, delay)
+		}
+	}
+}
+
+func TestCreateWebhookRejectsPrivateTargets(t *testing.T) {
+	repo := &memoryWebhookRepository{webhooks: make(map[uint]*model.Webhook)}
+	s := NewWebhookService(WebhookServiceConfig{}, repo)
+	events := []string{model.EventScanCompleted}
+
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://100.100.100.200/",
+		"http://0.0.0.0/",
+	} {
+		if _, err := s.CreateWebhook(context.Background(), dto.CreateWebhookDTO{URL: target, Events: events}); !errors.Is(err, ErrForbiddenWebhookTarget) {
+			t.Errorf("CreateWebhook(%s) error = %v, want %v", target, err, ErrForbiddenWebhookTarget)
+		}
+	}
+
+	if _, err := s.CreateWebhook(context.Background(), dto.CreateWebhookDTO{URL: "https://93.184.216.34/hook", Events: events}); err != nil {
+		t.Errorf("CreateWebhook() to a public address error = %v", err)
+	}
+
+	allowed := NewWebhookService(WebhookServiceConfig{AllowPrivateTargets: true}, repo)
+	if _, err := allowed.CreateWebhook(context.Background(), dto.CreateWebhookDTO{URL: "http://127.0.0.1:8080/hook", Events: events}); err != nil {
+		t.Errorf("CreateWebhook() with private targets allowed error = %v", err)
+	}
+}
+
+func TestWebhookPostRefusesPrivateTargets(t *testing.T) {
+	// A webhook created with a public address can resolve to a private one
+	// later, so the address dialed is checked too
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	s := NewWebhookService(WebhookServiceConfig{}, nil).(*WebhookServiceImpl)
+	webhook := &model.Webhook{URL: server.URL}
+	delivery := model.NewWebhookDelivery(1, "abc", model.EventAccountFound, "{}")
+
+	if _, err := s.post(context.Background(), webhook, delivery); !errors.Is(err, ErrForbiddenWebhookTarget) {
+		t.Errorf("post() error = %v, want %v", err, ErrForbiddenWebhookTarget)
+	}
+	if requests != 0 {
+		t.Errorf("server received %d requests, want 0", requests)
+	}
+}
+
+func TestWebhookPostDoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	})
+	mux.HandleFunc("/internal", func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := NewWebhookService(WebhookServiceConfig{AllowPrivateTargets: true}, nil).(*WebhookServiceImpl)
+	webhook := &model.Webhook{URL: server.URL + "/hook"}
+	delivery := model.NewWebhookDelivery(1, "abc", model.EventAccountFound, "{}")
+
+	status, err := s.post(context.Background(), webhook, delivery)
+	if err == nil || status != http.StatusFound {
+		t.Errorf("post() = %d, %v, want %d and an error", status, err, http.StatusFound)
+	}
+	if redirected {
+		t.Error("post() followed the redirect")
+	}
+}
+
+func TestDeliverDueFailsDeliveriesOfDeletedWebhooks(t *testing.T) {
+	delivery := model.NewWebhookDelivery(1, "abc", model.EventAccountFound, "{}")
+	delivery.ID = 1
+	repo := &memoryWebhookRepository{
+		webhooks:   make(map[uint]*model.Webhook),
+		deliveries: []*model.WebhookDelivery{delivery},
+	}
+	s := NewWebhookService(WebhookServiceConfig{}, repo).(*WebhookServiceImpl)
+
+	runDeliverDue(t, s)
+	if got := repo.deliveries[0]; got.Status != model.DeliveryFailed || got.Error != ErrWebhookNotFound.Error() {
+		t.Errorf("delivery = %s (%q), want %s", got.Status, got.Error, model.DeliveryFailed)
+	}
+}
+
+func TestDeliverDueStopsWhenUpdatesFail(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	repo := &memoryWebhookRepository{
+		webhooks:    map[uint]*model.Webhook{1: {ID: 1, URL: server.URL}},
+		failUpdates: true,
+	}
+	for id := uint(1); id <= 3; id++ {
+		delivery := model.NewWebhookDelivery(1, "abc", model.EventAccountFound, "{}")
+		delivery.ID = id
+		repo.deliveries = append(repo.deliveries, delivery)
+	}
+	s := NewWebhookService(WebhookServiceConfig{AllowPrivateTargets: true}, repo).(*WebhookServiceImpl)
+
+	// The deliveries stay due, but each is attempted once until the next tick
+	runDeliverDue(t, s)
+	if requests != 3 {
+		t.Errorf("server received %d requests, want 3", requests)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/accio/internal/metrics"
 )
 
 // Checker handles the process of checking usernames across different sites
@@ -50,18 +52,24 @@ func NewChecker(timeout int, verbose bool) *Checker {
 
 // CheckUsername checks if a username exists on a given site
 func (c *Checker) CheckUsername(username, site, url string) (bool, error) {
+	return c.CheckUsernameContext(context.Background(), username, site, url)
+}
+
+// CheckUsernameContext checks if a username exists on a given site,
+// aborting when the context is cancelled
+func (c *Checker) CheckUsernameContext(ctx context.Context, username, site, url string) (bool, error) {
 	if c.Verbose {
 		fmt.Printf("Checking %s on %s\n", username, site)
 	}
 
 	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), c.Client.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, c.Client.Timeout)
 	defer cancel()
 
 	// Create a new request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		c.incrementErrors()
+		c.incrementErrors(site)
 		return false, err
 	}
 
@@ -71,7 +79,7 @@ func (c *Checker) CheckUsername(username, site, url string) (bool, error) {
 	// Make the request
 	resp, err := c.Client.Do(req)
 	if err != nil {
-		c.incrementErrors()
+		c.incrementErrors(site)
 		return false, err
 	}
 	defer resp.Body.Close()
@@ -92,9 +100,9 @@ func (c *Checker) CheckUsername(username, site, url string) (bool, error) {
 
 	// Update stats
 	if exists {
-		c.incrementFound()
+		c.incrementFound(site)
 	} else {
-		c.incrementNotFound()
+		c.incrementNotFound(site)
 	}
 
 	return exists, nil
@@ -102,10 +110,16 @@ func (c *Checker) CheckUsername(username, site, url string) (bool, error) {
 
 // CheckUsernameWithRetry checks a username with retry logic
 func (c *Checker) CheckUsernameWithRetry(username, site, url string, maxRetries int) (bool, error) {
+	return c.CheckUsernameWithRetryContext(context.Background(), username, site, url, maxRetries)
+}
+
+// CheckUsernameWithRetryContext checks a username with retry logic,
+// giving up early when the context is cancelled
+func (c *Checker) CheckUsernameWithRetryContext(ctx context.Context, username, site, url string, maxRetries int) (bool, error) {
 	var lastErr error
 
 	for retry := 0; retry < maxRetries; retry++ {
-		exists, err := c.CheckUsername(username, site, url)
+		exists, err := c.CheckUsernameContext(ctx, username, site, url)
 		if err == nil {
 			return exists, nil
 		}
@@ -113,7 +127,11 @@ func (c *Checker) CheckUsernameWithRetry(username, site, url string, maxRetries
 		lastErr = err
 
 		// Wait before retrying (with exponential backoff)
-		time.Sleep(time.Duration(retry+1) * 500 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(time.Duration(retry+1) * 500 * time.Millisecond):
+		}
 	}
 
 	return false, fmt.Errorf("max retries exceeded: %w", lastErr)
@@ -130,7 +148,9 @@ func (c *Checker) GetStats() CheckStats {
 }
 
 // incrementFound increments the found counter
-func (c *Checker) incrementFound() {
+func (c *Checker) incrementFound(site string) {
+	metrics.SiteChecks.WithLabelValues(site, metrics.OutcomeFound).Inc()
+
 	c.Mutex.Lock()
 	defer c.Mutex.Unlock()
 	c.Stats.Found++
@@ -138,7 +158,9 @@ func (c *Checker) incrementFound() {
 }
 
 // incrementNotFound increments the not found counter
-func (c *Checker) incrementNotFound() {
+func (c *Checker) incrementNotFound(site string) {
+	metrics.SiteChecks.WithLabelValues(site, metrics.OutcomeNotFound).Inc()
+
 	c.Mutex.Lock()
 	defer c.Mutex.Unlock()
 	c.Stats.NotFound++
@@ -146,7 +168,9 @@ func (c *Checker) incrementNotFound() {
 }
 
 // incrementErrors increments the errors counter
-func (c *Checker) incrementErrors() {
+func (c *Checker) incrementErrors(site string) {
+	metrics.SiteChecks.WithLabelValues(site, metrics.OutcomeError).Inc()
+
 	c.Mutex.Lock()
 	defer c.Mutex.Unlock()
 	c.Stats.Errors++
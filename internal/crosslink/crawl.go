@@ -0,0 +1,268 @@
+package crosslink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/accio/internal/checker"
+	"github.com/accio/internal/output"
+	"github.com/accio/internal/scanner"
+)
+
+// Default crawl settings
+const (
+	DefaultMaxSeeds = 10
+	DefaultTimeout  = 10 // seconds
+)
+
+// maxPageSize is the most of a profile page read for links
+const maxPageSize = 1 << 20
+
+// pageConcurrency is the number of profile pages fetched in parallel
+const pageConcurrency = 8
+
+// Options configures a Crawler
+type Options struct {
+	Depth    int            // Rounds of linked usernames to scan; 0 scans the username only
+	MaxSeeds int            // Most new usernames scanned per round
+	Timeout  int            // Timeout in seconds for fetching a profile page
+	Profiles ProfileFetcher // Optional source of the bios and platform data of found profiles
+}
+
+// ProfileFetcher fetches found profiles from platform APIs, whose bios and
+// platform data often link to the same person's profiles on other sites
+type ProfileFetcher interface {
+	// FetchProfile returns the bio and platform data of the profile of a
+	// username on a site. Sites without an API return an empty bio and no
+	// data.
+	FetchProfile(ctx context.Context, site, username string) (bio string, data map[string]string, err error)
+}
+
+// Crawler scans a username and follows the links on its found profiles to
+// other usernames, which are scanned in turn up to a depth
+type Crawler struct {
+	scanner   *scanner.Scanner
+	extractor *Extractor
+	client    *http.Client
+	options   Options
+	stats     checker.CheckStats
+}
+
+// NewCrawler creates a Crawler scanning with s and following links to the
+// sites s checks
+func NewCrawler(s *scanner.Scanner, options Options) *Crawler {
+	if options.MaxSeeds <= 0 {
+		options.MaxSeeds = DefaultMaxSeeds
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+
+	return &Crawler{
+		scanner:   s,
+		extractor: NewExtractor(s.Sites()),
+		client:    &http.Client{Timeout: time.Duration(options.Timeout) * time.Second},
+		options:   options,
+	}
+}
+
+// Crawl scans the username, then the usernames its found profiles link to
+// from their pages, bios or platform data, and so on up to the crawler's
+// depth. It returns the results by username
+// and the links found. onResult is called with each result as it arrives,
+// possibly from multiple goroutines concurrently.
+func (c *Crawler) Crawl(ctx context.Context, username string, onResult func(username string, result output.Result)) (map[string][]output.Result, []output.Link) {
+	c.stats = checker.CheckStats{}
+	allResults := make(map[string][]output.Result)
+	scanned := map[string]bool{strings.ToLower(username): true}
+	frontier := []string{username}
+
+	var links []output.Link
+	for depth := 0; len(frontier) > 0 && ctx.Err() == nil; depth++ {
+		var next []string
+		for _, seed := range frontier {
+			results := c.scan(ctx, seed, onResult)
+			allResults[seed] = results
+			if depth >= c.options.Depth || ctx.Err() != nil {
+				continue
+			}
+
+			found := c.profileLinks(ctx, seed, results)
+			links = append(links, found...)
+			for _, link := range found {
+				key := strings.ToLower(link.ToUsername)
+				if !scanned[key] && len(next) < c.options.MaxSeeds {
+					scanned[key] = true
+					next = append(next, link.ToUsername)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return allResults, links
+}
+
+// scan checks one username on every site
+func (c *Crawler) scan(ctx context.Context, username string, onResult func(string, output.Result)) []output.Result {
+	var (
+		mu      sync.Mutex
+		results []output.Result
+	)
+	stats := c.scanner.Scan(ctx, username, func(result output.Result) {
+		mu.Lock()
+		results = append(results, result)
+		mu.Unlock()
+		onResult(username, result)
+	})
+	c.addStats(stats)
+	return results
+}
+
+// addStats adds the statistics of a scan to those of the crawl
+func (c *Crawler) addStats(stats checker.CheckStats) {
+	if c.stats.StartTime.IsZero() {
+		c.stats.StartTime = stats.StartTime
+	}
+	c.stats.EndTime = stats.EndTime
+	c.stats.Total += stats.Total
+	c.stats.Found += stats.Found
+	c.stats.NotFound += stats.NotFound
+	c.stats.Errors += stats.Errors
+}
+
+// Stats returns the statistics of every scan of the last crawl
+func (c *Crawler) Stats() checker.CheckStats {
+	return c.stats
+}
+
+// profileLinks returns the links of the found profiles of a username to
+// profiles on other sites, read from their pages and, with a profile fetcher,
+// from their bios and platform data
+func (c *Crawler) profileLinks(ctx context.Context, username string, results []output.Result) []output.Link {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		links []output.Link
+	)
+	sem := make(chan struct{}, pageConcurrency)
+	for _, result := range results {
+		if !result.Exists {
+			continue
+		}
+
+		wg.Add(1)
+		go func(result output.Result) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			found := c.pageLinks(ctx, username, result)
+			if c.options.Profiles != nil {
+				found = append(found, c.fetchedLinks(ctx, username, result)...)
+			}
+
+			mu.Lock()
+			links = append(links, found...)
+			mu.Unlock()
+		}(result)
+	}
+	wg.Wait()
+
+	SortLinks(links)
+	return links
+}
+
+// pageLinks fetches the page of a found profile and returns its links to
+// profiles on other sites
+func (c *Crawler) pageLinks(ctx context.Context, username string, result output.Result) []output.Link {
+	page, err := c.fetch(ctx, result.URL)
+	if err != nil {
+		return nil
+	}
+
+	// Links to the profile's own site are mostly navigation
+	var handles []Handle
+	for _, handle := range c.extractor.FromHTML(page) {
+		if handle.Site != result.Site {
+			handles = append(handles, handle)
+		}
+	}
+	return Links(result.Site, username, handles, SourcePage)
+}
+
+// fetchedLinks fetches a found profile from its platform API and returns the
+// links in its platform data and bio
+func (c *Crawler) fetchedLinks(ctx context.Context, username string, result output.Result) []output.Link {
+	bio, data, err := c.options.Profiles.FetchProfile(ctx, result.Site, username)
+	if err != nil {
+		return nil
+	}
+
+	links := Links(result.Site, username, c.extractor.FromPlatformData(data), SourcePlatformData)
+	return append(links, Links(result.Site, username, c.extractor.FromText(bio), SourceBio)...)
+}
+
+// fetch returns the start of the page at url
+func (c *Crawler) fetch(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Accio/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// Links returns the links from the profile of username on site to the
+// profiles of the handles, leaving out links to the profile itself
+func Links(site, username string, handles []Handle, source string) []output.Link {
+	var links []output.Link
+	for _, handle := range handles {
+		if strings.EqualFold(handle.Site, site) && strings.EqualFold(handle.Username, username) {
+			continue
+		}
+		links = append(links, output.Link{
+			Type:         output.LinksTo,
+			FromSite:     site,
+			FromUsername: username,
+			ToSite:       handle.Site,
+			ToUsername:   handle.Username,
+			ToURL:        handle.URL,
+			Source:       source,
+		})
+	}
+	return links
+}
+
+// SortLinks sorts links by their linking and linked profiles
+func SortLinks(links []output.Link) {
+	sort.SliceStable(links, func(i, j int) bool {
+		a, b := links[i], links[j]
+		if a.FromSite != b.FromSite {
+			return a.FromSite < b.FromSite
+		}
+		if a.FromUsername != b.FromUsername {
+			return a.FromUsername < b.FromUsername
+		}
+		if a.ToSite != b.ToSite {
+			return a.ToSite < b.ToSite
+		}
+		return a.ToUsername < b.ToUsername
+	})
+}
@@ -0,0 +1,195 @@
+package crosslink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/accio/internal/output"
+	"github.com/accio/internal/scanner"
+	"github.com/accio/internal/sites"
+)
+
+func TestFromText(t *testing.T) {
+	e := NewExtractor(sites.GetSites())
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"profile URL", "Code at https://github.com/jdoe.", []string{"GitHub/jdoe"}},
+		{"no scheme", "see twitter.com/jdoe_ and www.instagram.com/j.doe", []string{"Twitter/jdoe_", "Instagram/j.doe"}},
+		{"host alias", "https://x.com/jdoe/status/1", []string{"Twitter/jdoe"}},
+		{"username in host", "Art: https://jdoe.deviantart.com", []string{"Deviantart/jdoe"}},
+		{"labelled handle", "Twitch: @jdoe_live | Reddit - jdoe", []string{"Twitch/jdoe_live", "Reddit/jdoe"}},
+		{"longer host", "https://gist.github.com/jdoe", nil},
+		{"reserved path", "https://twitter.com/intent/tweet", nil},
+		{"duplicates", "github.com/jdoe https://github.com/JDoe", []string{"GitHub/jdoe"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, handle := range e.FromText(tt.text) {
+				got = append(got, handle.Site+"/"+handle.Username)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromText(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromPlatformData(t *testing.T) {
+	e := NewExtractor(sites.GetSites())
+
+	handles := e.FromPlatformData(map[string]string{
+		"twitter_username": "@jdoe",
+		"blog":             "https://medium.com/@johndoe",
+		"company":          "Acme",
+	})
+
+	want := []Handle{
+		{Site: "Twitter", Username: "jdoe", URL: "https://twitter.com/jdoe"},
+		{Site: "Medium", Username: "johndoe", URL: "https://medium.com/@johndoe"},
+	}
+	if !reflect.DeepEqual(handles, want) {
+		t.Errorf("FromPlatformData() = %+v, want %+v", handles, want)
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a/jdoe":
+			fmt.Fprintf(w, `<a href="%s/a/about">About</a> <a href="%s/b/johnd">Me elsewhere</a>`, server.URL, server.URL)
+		case "/b/johnd":
+			fmt.Fprintf(w, `<a href="%s/a/jdoe">GitHub</a>`, server.URL)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	s := scanner.NewScanner(scanner.Options{Timeout: 5, Concurrency: 2, Retries: 1}).
+		WithSites([]sites.Site{
+			{Name: "SiteA", URLFormat: server.URL + "/a/{}"},
+			{Name: "SiteB", URLFormat: server.URL + "/b/{}"},
+		})
+
+	allResults, links := NewCrawler(s, Options{Depth: 1}).
+		Crawl(context.Background(), "jdoe", func(string, output.Result) {})
+
+	if len(allResults) != 2 || len(allResults["jdoe"]) != 2 || len(allResults["johnd"]) != 2 {
+		t.Fatalf("Expected both usernames scanned on both sites, got %v", allResults)
+	}
+
+	// Only the seed's pages are read at depth 1
+	want := []output.Link{{
+		Type:         output.LinksTo,
+		FromSite:     "SiteA",
+		FromUsername: "jdoe",
+		ToSite:       "SiteB",
+		ToUsername:   "johnd",
+		ToURL:        server.URL + "/b/johnd",
+		Source:       SourcePage,
+	}}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("Crawl() links = %+v, want %+v", links, want)
+	}
+}
+
+// stubProfiles serves the bios and platform data of profiles by site and username
+type stubProfiles map[string]struct {
+	bio  string
+	data map[string]string
+}
+
+func (p stubProfiles) FetchProfile(ctx context.Context, site, username string) (string, map[string]string, error) {
+	profile := p[site+"/"+username]
+	return profile.bio, profile.data, nil
+}
+
+func TestCrawlFetchedProfiles(t *testing.T) {
+	// No page links anywhere: only the platform APIs connect the profiles
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a/jdoe", "/b/johnd", "/b/jd":
+			fmt.Fprint(w, `<p>Nothing to see</p>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	s := scanner.NewScanner(scanner.Options{Timeout: 5, Concurrency: 2, Retries: 1}).
+		WithSites([]sites.Site{
+			{Name: "SiteA", URLFormat: server.URL + "/a/{}"},
+			{Name: "SiteB", URLFormat: server.URL + "/b/{}"},
+		})
+	profiles := stubProfiles{
+		"SiteA/jdoe": {
+			bio:  "Also at " + server.URL + "/b/jd",
+			data: map[string]string{"siteb_username": "johnd"},
+		},
+	}
+
+	allResults, links := NewCrawler(s, Options{Depth: 1, Profiles: profiles}).
+		Crawl(context.Background(), "jdoe", func(string, output.Result) {})
+
+	if len(allResults) != 3 || len(allResults["johnd"]) != 2 || len(allResults["jd"]) != 2 {
+		t.Fatalf("Expected the linked usernames scanned on both sites, got %v", allResults)
+	}
+
+	want := []output.Link{
+		{
+			Type:         output.LinksTo,
+			FromSite:     "SiteA",
+			FromUsername: "jdoe",
+			ToSite:       "SiteB",
+			ToUsername:   "jd",
+			ToURL:        server.URL + "/b/jd",
+			Source:       SourceBio,
+		},
+		{
+			Type:         output.LinksTo,
+			FromSite:     "SiteA",
+			FromUsername: "jdoe",
+			ToSite:       "SiteB",
+			ToUsername:   "johnd",
+			ToURL:        server.URL + "/b/johnd",
+			Source:       SourcePlatformData,
+		},
+	}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("Crawl() links = %+v, want %+v", links, want)
+	}
+}
+
+func TestCrawlDepthZero(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `https://twitter.com/someone`)
+	}))
+	defer server.Close()
+
+	s := scanner.NewScanner(scanner.Options{Timeout: 5, Concurrency: 1, Retries: 1}).
+		WithSites([]sites.Site{{Name: "SiteA", URLFormat: server.URL + "/a/{}"}})
+
+	allResults, links := NewCrawler(s, Options{}).
+		Crawl(context.Background(), "jdoe", func(string, output.Result) {})
+
+	if len(allResults) != 1 || len(links) != 0 {
+		t.Errorf("Expected only the username scanned, got %v and %v", allResults, links)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+}
@@ -0,0 +1,204 @@
+package crosslink
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/accio/internal/sites"
+	httputil "github.com/accio/pkg/http"
+)
+
+// Sources of a link
+const (
+	SourcePlatformData = "platform_data"
+	SourceBio          = "bio"
+	SourcePage         = "page"
+)
+
+// hostAliases lists other hosts serving the profiles of a site
+var hostAliases = map[string][]string{
+	"twitter.com": {"x.com"},
+}
+
+// reservedNames are path segments of site pages that are not profiles
+var reservedNames = map[string]bool{
+	"about": true, "explore": true, "features": true, "hashtag": true, "help": true,
+	"home": true, "i": true, "intent": true, "login": true, "logout": true,
+	"pricing": true, "privacy": true, "search": true, "settings": true, "share": true,
+	"signup": true, "terms": true, "www": true,
+}
+
+// Handle is a reference to a username on a catalog site
+type Handle struct {
+	Site     string // Name of the site
+	Username string // Username on the site
+	URL      string // Profile URL
+}
+
+// sitePattern matches profile URLs of one site
+type sitePattern struct {
+	site    sites.Site
+	pattern *regexp.Regexp
+}
+
+// Extractor finds references to profiles on catalog sites in text, HTML and
+// platform data
+type Extractor struct {
+	patterns []sitePattern
+	sites    map[string]sites.Site
+	labelled *regexp.Regexp
+}
+
+// NewExtractor creates an Extractor for the given sites
+func NewExtractor(siteList []sites.Site) *Extractor {
+	e := &Extractor{
+		sites: make(map[string]sites.Site),
+	}
+
+	var names []string
+	for _, site := range siteList {
+		e.sites[strings.ToLower(site.Name)] = site
+		names = append(names, regexp.QuoteMeta(site.Name))
+
+		for _, pattern := range urlPatterns(site.URLFormat) {
+			e.patterns = append(e.patterns, sitePattern{site: site, pattern: pattern})
+		}
+	}
+
+	// Bios often label handles with the site, as in "Twitter: @jdoe"
+	if len(names) > 0 {
+		e.labelled = regexp.MustCompile(`(?i)\b(` + strings.Join(names, "|") + `)\s*[:|\-–—]\s*@?([A-Za-z0-9_][A-Za-z0-9_.\-]*)`)
+	}
+
+	return e
+}
+
+// urlPatterns builds the patterns matching profile URLs of a URL format, with
+// or without the scheme and "www."
+func urlPatterns(urlFormat string) []*regexp.Regexp {
+	format := strings.TrimPrefix(strings.TrimPrefix(urlFormat, "https://"), "http://")
+	format = strings.TrimPrefix(format, "www.")
+
+	prefix, suffix, ok := strings.Cut(format, "{}")
+	if !ok {
+		return nil
+	}
+
+	// Usernames in paths may contain dots, usernames in host names cannot
+	username := `([A-Za-z0-9_][A-Za-z0-9_.\-]*)`
+	if !strings.Contains(prefix, "/") {
+		username = `([A-Za-z0-9_][A-Za-z0-9_\-]*)`
+	}
+
+	prefixes := []string{prefix}
+	host, path, _ := strings.Cut(prefix, "/")
+	for _, alias := range hostAliases[host] {
+		prefixes = append(prefixes, alias+"/"+path)
+	}
+
+	var patterns []*regexp.Regexp
+	for _, prefix := range prefixes {
+		patterns = append(patterns, regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?`+regexp.QuoteMeta(prefix)+username+regexp.QuoteMeta(suffix)))
+	}
+	return patterns
+}
+
+// FromText finds the profile URLs and labelled handles in text, such as a bio
+func (e *Extractor) FromText(text string) []Handle {
+	return e.find(text, true)
+}
+
+// FromHTML finds the profile URLs in an HTML page. Labelled handles are not
+// looked for, as page titles such as "GitHub: Let's build" resemble them.
+func (e *Extractor) FromHTML(page string) []Handle {
+	return e.find(html.UnescapeString(page), false)
+}
+
+// find finds the profile URLs, and optionally the labelled handles, in text
+func (e *Extractor) find(text string, labelled bool) []Handle {
+	var handles []Handle
+	seen := make(map[string]bool)
+	add := func(site sites.Site, username string) {
+		username = strings.TrimRight(username, ".-")
+		if username == "" || reservedNames[strings.ToLower(username)] {
+			return
+		}
+		key := strings.ToLower(site.Name + "/" + username)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		handles = append(handles, Handle{
+			Site:     site.Name,
+			Username: username,
+			URL:      httputil.FormatURL(site.URLFormat, username),
+		})
+	}
+
+	for _, p := range e.patterns {
+		for _, m := range p.pattern.FindAllStringSubmatchIndex(text, -1) {
+			// The match must not continue a longer host or word
+			if m[0] > 0 && isHostChar(text[m[0]-1]) {
+				continue
+			}
+			add(p.site, text[m[2]:m[3]])
+		}
+	}
+
+	if labelled && e.labelled != nil {
+		for _, m := range e.labelled.FindAllStringSubmatch(text, -1) {
+			if site, ok := e.sites[strings.ToLower(m[1])]; ok {
+				add(site, m[2])
+			}
+		}
+	}
+
+	return handles
+}
+
+// FromPlatformData finds the handles in platform data. Keys such as
+// "twitter_username" name the site of their value; other values are
+// searched for profile URLs.
+func (e *Extractor) FromPlatformData(data map[string]string) []Handle {
+	// Sort the keys so the result doesn't depend on map order
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var text []string
+	var handles []Handle
+	for _, key := range keys {
+		value := strings.TrimSpace(data[key])
+		if value == "" {
+			continue
+		}
+
+		name, ok := strings.CutSuffix(strings.ToLower(key), "_username")
+		if !ok {
+			name, ok = strings.CutSuffix(strings.ToLower(key), "_handle")
+		}
+		if site, known := e.sites[name]; ok && known {
+			username := strings.TrimPrefix(value, "@")
+			handles = append(handles, Handle{
+				Site:     site.Name,
+				Username: username,
+				URL:      httputil.FormatURL(site.URLFormat, username),
+			})
+			continue
+		}
+
+		text = append(text, value)
+	}
+
+	return append(handles, e.FromText(strings.Join(text, "\n"))...)
+}
+
+// isHostChar reports whether c can be part of a host name or URL path
+func isHostChar(c byte) bool {
+	return c == '.' || c == '-' || c == '/' || c == '_' || c == '@' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// API key scopes
+const (
+	ScopeRead = "read" // Read stored profiles, search history and scan jobs
+	ScopeScan = "scan" // Also start and cancel scans and live searches
+)
+
+// APIKey represents a hashed API key used to authenticate HTTP clients
+type APIKey struct {
+	ID         uint `gorm:"primaryKey"`
+	Name       string
+	Prefix     string `gorm:"uniqueIndex"`
+	KeyHash    string
+	Scopes     string // Comma-separated list of scopes
+	RateLimit  int    // Requests per minute, 0 for unlimited
+	DailyQuota int    // Requests per day, 0 for unlimited
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// NewAPIKey creates a new API key entity
+func NewAPIKey(name, prefix, keyHash string, scopes []string, rateLimit, dailyQuota int) *APIKey {
+	return &APIKey{
+		Name:       name,
+		Prefix:     prefix,
+		KeyHash:    keyHash,
+		Scopes:     strings.Join(scopes, ","),
+		RateLimit:  rateLimit,
+		DailyQuota: dailyQuota,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// ScopeList returns the scopes granted to the key
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// HasScope reports whether the key grants a scope. The scan scope implies
+// the read scope.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope || (s == ScopeScan && scope == ScopeRead) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRevoked reports whether the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// APIKeyUsage counts the requests made with an API key on a given day
+type APIKeyUsage struct {
+	ID       uint   `gorm:"primaryKey"`
+	APIKeyID uint   `gorm:"uniqueIndex:idx_api_key_usage_day"`
+	Day      string `gorm:"uniqueIndex:idx_api_key_usage_day"` // YYYY-MM-DD in UTC
+	Count    int
+}
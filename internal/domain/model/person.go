@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Person is an identity resolved from stored profiles that likely belong to
+// the same individual
+type Person struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Relationships
+	Profiles []Profile `gorm:"foreignKey:PersonID"`
+}
@@ -15,6 +15,8 @@ type Profile struct {
 	Verified      bool
 	FollowerCount int64
 	Bio           string
+	ImageHash     string // Average hash of the profile picture, see image.AverageHash
+	PersonID      *uint  `gorm:"index"`
 	LastUpdated   time.Time
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
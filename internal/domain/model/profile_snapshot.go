@@ -0,0 +1,87 @@
+package model
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ProfileSnapshot is the state of a profile as fetched from its platform at
+// one point in time
+type ProfileSnapshot struct {
+	ID            uint `gorm:"primaryKey"`
+	ProfileID     uint `gorm:"index"`
+	RealName      string
+	Bio           string
+	ImageURL      string
+	Verified      bool
+	FollowerCount int64
+	PlatformData  string    // JSON object of the platform data
+	CapturedAt    time.Time `gorm:"index"`
+}
+
+// ProfileChange is a field whose value differs between two snapshots
+type ProfileChange struct {
+	Field string // real_name, bio, image_url, verified, follower_count or platform_data.<key>
+	Old   string
+	New   string
+}
+
+// NewProfileSnapshot captures the current state of a stored profile
+func NewProfileSnapshot(profile *Profile) *ProfileSnapshot {
+	platformData, _ := json.Marshal(profile.GetPlatformDataMap())
+	return &ProfileSnapshot{
+		ProfileID:     profile.ID,
+		RealName:      profile.RealName,
+		Bio:           profile.Bio,
+		ImageURL:      profile.ImageURL,
+		Verified:      profile.Verified,
+		FollowerCount: profile.FollowerCount,
+		PlatformData:  string(platformData),
+		CapturedAt:    profile.LastUpdated,
+	}
+}
+
+// PlatformDataMap returns the platform data of the snapshot as a map
+func (s *ProfileSnapshot) PlatformDataMap() map[string]string {
+	result := make(map[string]string)
+	if s.PlatformData != "" {
+		json.Unmarshal([]byte(s.PlatformData), &result)
+	}
+	return result
+}
+
+// DiffProfileSnapshots lists the fields that changed from old to new, with
+// platform data keys sorted after the profile fields
+func DiffProfileSnapshots(old, new *ProfileSnapshot) []ProfileChange {
+	var changes []ProfileChange
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, ProfileChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	add("real_name", old.RealName, new.RealName)
+	add("bio", old.Bio, new.Bio)
+	add("image_url", old.ImageURL, new.ImageURL)
+	add("verified", strconv.FormatBool(old.Verified), strconv.FormatBool(new.Verified))
+	add("follower_count", strconv.FormatInt(old.FollowerCount, 10), strconv.FormatInt(new.FollowerCount, 10))
+
+	oldData, newData := old.PlatformDataMap(), new.PlatformDataMap()
+	keys := make([]string, 0, len(oldData)+len(newData))
+	for key := range oldData {
+		keys = append(keys, key)
+	}
+	for key := range newData {
+		if _, ok := oldData[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add("platform_data."+key, oldData[key], newData[key])
+	}
+
+	return changes
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffProfileSnapshots(t *testing.T) {
+	old := &ProfileSnapshot{
+		RealName:      "John Doe",
+		Bio:           "Developer",
+		FollowerCount: 10,
+		PlatformData:  `{"location":"Berlin","company":"Acme"}`,
+	}
+	new := &ProfileSnapshot{
+		RealName:      "John Doe",
+		Bio:           "Engineer",
+		Verified:      true,
+		FollowerCount: 12,
+		PlatformData:  `{"location":"Berlin","website":"example.com"}`,
+	}
+
+	want := []ProfileChange{
+		{Field: "bio", Old: "Developer", New: "Engineer"},
+		{Field: "verified", Old: "false", New: "true"},
+		{Field: "follower_count", Old: "10", New: "12"},
+		{Field: "platform_data.company", Old: "Acme", New: ""},
+		{Field: "platform_data.website", Old: "", New: "example.com"},
+	}
+	if got := DiffProfileSnapshots(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffProfileSnapshots() = %+v, want %+v", got, want)
+	}
+
+	if got := DiffProfileSnapshots(new, new); len(got) != 0 {
+		t.Errorf("DiffProfileSnapshots() of identical snapshots = %+v, want none", got)
+	}
+}
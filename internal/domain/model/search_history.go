@@ -8,6 +8,9 @@ type SearchHistory struct {
 	Query       string `gorm:"index"`
 	ResultCount int
 	CreatedAt   time.Time
+
+	// SearchCount is only populated by aggregate queries such as FindPopular
+	SearchCount int64 `gorm:"->;-:migration"`
 }
 
 // NewSearchHistory creates a new search history entity
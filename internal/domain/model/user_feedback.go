@@ -2,21 +2,40 @@ package model
 
 import "time"
 
+// Feedback types
+const (
+	FeedbackCorrect   = "correct"   // The profile belongs to the searched person
+	FeedbackIncorrect = "incorrect" // The profile was matched to the wrong person
+	FeedbackMissing   = "missing"   // A profile of the searched person is missing
+)
+
 // UserFeedback represents user feedback for a profile
 type UserFeedback struct {
 	ID           uint   `gorm:"primaryKey"`
 	ProfileID    uint   `gorm:"index"`
+	APIKeyID     uint   `gorm:"index"` // Key that submitted the feedback, 0 when authentication is disabled
 	FeedbackType string // 'correct', 'incorrect', 'missing'
 	Comment      string
 	CreatedAt    time.Time
 }
 
 // NewUserFeedback creates a new user feedback entity
-func NewUserFeedback(profileID uint, feedbackType, comment string) *UserFeedback {
+func NewUserFeedback(profileID, apiKeyID uint, feedbackType, comment string) *UserFeedback {
 	return &UserFeedback{
 		ProfileID:    profileID,
+		APIKeyID:     apiKeyID,
 		FeedbackType: feedbackType,
 		Comment:      comment,
 		CreatedAt:    time.Now(),
 	}
 }
+
+// IsValidFeedbackType reports whether a feedback type is known
+func IsValidFeedbackType(feedbackType string) bool {
+	switch feedbackType {
+	case FeedbackCorrect, FeedbackIncorrect, FeedbackMissing:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Watchlist alert kinds
+const (
+	AlertAppeared      = "appeared"       // An account was found that was not before
+	AlertDisappeared   = "disappeared"    // An account found before is gone
+	AlertNameChanged   = "name_changed"   // The name on an account changed
+	AlertBioChanged    = "bio_changed"    // The bio of an account changed
+	AlertAvatarChanged = "avatar_changed" // The profile picture of an account changed
+)
+
+// WatchlistEntry is a username rescanned on its own schedule
+type WatchlistEntry struct {
+	ID        uint          `gorm:"primaryKey"`
+	Username  string        `gorm:"uniqueIndex"`
+	Sites     string        // Comma-separated sites to scan, empty for every site
+	Interval  time.Duration // Time between two runs
+	NextRunAt time.Time     `gorm:"index"`
+	LastRunAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewWatchlistEntry creates a new watchlist entry, due immediately
+func NewWatchlistEntry(username string, sites []string, interval time.Duration) *WatchlistEntry {
+	now := time.Now()
+	return &WatchlistEntry{
+		Username:  username,
+		Sites:     strings.Join(sites, ","),
+		Interval:  interval,
+		NextRunAt: now,
+		CreatedAt: now,
+	}
+}
+
+// SiteList returns the sites scanned for the entry, or nil for every site
+func (e *WatchlistEntry) SiteList() []string {
+	if e.Sites == "" {
+		return nil
+	}
+	return strings.Split(e.Sites, ",")
+}
+
+// WatchlistSnapshot is the state of a watched username after one run
+type WatchlistSnapshot struct {
+	ID        uint `gorm:"primaryKey"`
+	EntryID   uint `gorm:"index"`
+	Found     int
+	Total     int
+	CreatedAt time.Time
+
+	// Relationships
+	Accounts []WatchlistAccount `gorm:"foreignKey:SnapshotID"`
+}
+
+// WatchlistAccount is a site checked in a snapshot, along with the profile
+// fetched from the platform API if the site has one
+type WatchlistAccount struct {
+	ID         uint `gorm:"primaryKey"`
+	SnapshotID uint `gorm:"index"`
+	Site       string
+	URL        string
+	Exists     bool
+	Error      string
+	Fetched    bool // Whether Name, Bio and ImageURL were fetched from the platform API
+	Name       string
+	Bio        string
+	ImageURL   string
+}
+
+// WatchlistAlert is a change found between two snapshots of an entry
+type WatchlistAlert struct {
+	ID         uint `gorm:"primaryKey"`
+	EntryID    uint `gorm:"index"`
+	SnapshotID uint
+	Username   string
+	Kind       string
+	Site       string
+	URL        string
+	Old        string
+	New        string
+	CreatedAt  time.Time `gorm:"index"`
+}
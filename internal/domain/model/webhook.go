@@ -0,0 +1,93 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook event types
+const (
+	EventScanCompleted    = "scan.completed"    // A scan job completed
+	EventAccountFound     = "account.found"     // A scan job or watchlist run found an account
+	EventWatchlistChanged = "watchlist.changed" // A watchlist run raised alerts
+)
+
+// EventTypes lists every webhook event type
+var EventTypes = []string{EventScanCompleted, EventAccountFound, EventWatchlistChanged}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"   // Waiting for its first or next attempt
+	DeliverySucceeded = "succeeded" // Accepted with a 2xx response
+	DeliveryFailed    = "failed"    // Given up after the last attempt
+)
+
+// Webhook is a URL receiving signed events of the types it subscribes to
+type Webhook struct {
+	ID        uint `gorm:"primaryKey"`
+	URL       string
+	Secret    string // Key of the HMAC-SHA256 signature of each payload
+	Events    string // Comma-separated event types
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewWebhook creates a new webhook entity
+func NewWebhook(url, secret string, events []string) *Webhook {
+	return &Webhook{
+		URL:       url,
+		Secret:    secret,
+		Events:    strings.Join(events, ","),
+		CreatedAt: time.Now(),
+	}
+}
+
+// EventList returns the event types the webhook subscribes to
+func (w *Webhook) EventList() []string {
+	if w.Events == "" {
+		return nil
+	}
+	return strings.Split(w.Events, ",")
+}
+
+// Subscribes reports whether the webhook receives an event type
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is an event sent, or waiting to be sent, to a webhook.
+// Pending deliveries form the retry queue; the rest are the delivery log.
+type WebhookDelivery struct {
+	ID             uint   `gorm:"primaryKey"`
+	WebhookID      uint   `gorm:"index"`
+	EventID        string // Shared by the retries and replays of one event
+	Event          string
+	Payload        string
+	Status         string `gorm:"index"`
+	Attempts       int
+	NextAttemptAt  time.Time `gorm:"index"`
+	ResponseStatus int       // Status code of the last attempt, 0 if no response
+	Error          string    // Error of the last attempt
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewWebhookDelivery creates a new pending delivery, due immediately
+func NewWebhookDelivery(webhookID uint, eventID, event, payload string) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		Event:         event,
+		Payload:       payload,
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
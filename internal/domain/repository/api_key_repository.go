@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/accio/internal/domain/model"
+)
+
+// APIKeyRepository defines the interface for API key data access
+type APIKeyRepository interface {
+	// Create creates a new API key
+	Create(ctx context.Context, apiKey *model.APIKey) error
+
+	// FindByID finds an API key by ID
+	FindByID(ctx context.Context, id uint) (*model.APIKey, error)
+
+	// FindByPrefix finds an API key by its public prefix
+	FindByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+
+	// FindAll finds all API keys
+	FindAll(ctx context.Context) ([]*model.APIKey, error)
+
+	// Revoke marks an API key as revoked
+	Revoke(ctx context.Context, id uint) error
+
+	// IncrementUsage increments the usage counter of a key for a day and
+	// returns the new count
+	IncrementUsage(ctx context.Context, id uint, day string) (int, error)
+
+	// TouchLastUsed updates the last used time of a key
+	TouchLastUsed(ctx context.Context, id uint) error
+}
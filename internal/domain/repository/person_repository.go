@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/accio/internal/domain/model"
+)
+
+// PersonCluster is a person and the profiles resolved to it
+type PersonCluster struct {
+	PersonID   uint // Existing person to keep, or 0 to create one
+	Name       string
+	ProfileIDs []uint
+}
+
+// PersonRepository defines the interface for resolved person data access
+type PersonRepository interface {
+	// FindByID finds a person by ID along with its profiles
+	FindByID(ctx context.Context, id uint) (*model.Person, error)
+
+	// FindAll finds a page of persons along with their profiles, largest first
+	FindAll(ctx context.Context, limit, offset int) ([]*model.Person, error)
+
+	// Count counts all persons
+	Count(ctx context.Context) (int64, error)
+
+	// ReplaceAll replaces every person with the given clusters in one
+	// transaction. Persons not kept by a cluster are deleted, and profiles
+	// not in any cluster are detached from their person.
+	ReplaceAll(ctx context.Context, clusters []PersonCluster) ([]*model.Person, error)
+}
@@ -6,12 +6,20 @@ import (
 	"github.com/accio/internal/domain/model"
 )
 
+// ProfileFilter narrows the profiles returned by FindAll and Count.
+// Zero values mean "no filter".
+type ProfileFilter struct {
+	Platform string
+	Verified *bool
+}
+
 // ProfileRepository defines the interface for profile data access
 type ProfileRepository interface {
 	// Create creates a new profile
 	Create(ctx context.Context, profile *model.Profile) error
 
-	// Update updates an existing profile
+	// Update updates an existing profile, replacing its name parts, aliases
+	// and platform data
 	Update(ctx context.Context, profile *model.Profile) error
 
 	// FindByID finds a profile by ID
@@ -20,6 +28,9 @@ type ProfileRepository interface {
 	// FindByUsername finds a profile by username and platform
 	FindByUsername(ctx context.Context, username, platform string) (*model.Profile, error)
 
+	// FindAllByUsername finds the profiles with a username, ignoring case, on every platform
+	FindAllByUsername(ctx context.Context, username string) ([]*model.Profile, error)
+
 	// FindByRealName finds profiles by real name
 	FindByRealName(ctx context.Context, name string) ([]*model.Profile, error)
 
@@ -29,12 +40,29 @@ type ProfileRepository interface {
 	// FindByAlias finds profiles by alias
 	FindByAlias(ctx context.Context, alias string) ([]*model.Profile, error)
 
-	// FindAll finds all profiles with optional limit and offset
-	FindAll(ctx context.Context, limit, offset int) ([]*model.Profile, error)
+	// FindAll finds all profiles matching the filter with optional limit and offset
+	FindAll(ctx context.Context, filter ProfileFilter, limit, offset int) ([]*model.Profile, error)
+
+	// Count counts all profiles matching the filter
+	Count(ctx context.Context, filter ProfileFilter) (int64, error)
 
-	// Count counts all profiles
-	Count(ctx context.Context) (int64, error)
+	// UpdateImageHash stores the average hash of a profile's picture
+	UpdateImageHash(ctx context.Context, id uint, hash string) error
 
 	// Delete deletes a profile
 	Delete(ctx context.Context, id uint) error
+
+	// SaveWithSnapshot creates the profile, or updates it if it has an ID,
+	// and records the snapshot of its state unless it is nil, in one
+	// transaction. The snapshot is attached to the saved profile.
+	SaveWithSnapshot(ctx context.Context, profile *model.Profile, snapshot *model.ProfileSnapshot) error
+
+	// FindSnapshotByID finds a profile snapshot by ID
+	FindSnapshotByID(ctx context.Context, id uint) (*model.ProfileSnapshot, error)
+
+	// FindSnapshots finds a page of the snapshots of a profile, latest first
+	FindSnapshots(ctx context.Context, profileID uint, limit, offset int) ([]*model.ProfileSnapshot, error)
+
+	// CountSnapshots counts the snapshots of a profile
+	CountSnapshots(ctx context.Context, profileID uint) (int64, error)
 }
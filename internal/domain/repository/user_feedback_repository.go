@@ -6,17 +6,38 @@ import (
 	"github.com/accio/internal/domain/model"
 )
 
+// FeedbackCount is the number of feedback entries of one type for a profile
+type FeedbackCount struct {
+	ProfileID    uint
+	FeedbackType string
+	Count        int64
+}
+
 // UserFeedbackRepository defines the interface for user feedback data access
 type UserFeedbackRepository interface {
 	// Create creates a new user feedback entry
 	Create(ctx context.Context, userFeedback *model.UserFeedback) error
 
+	// Update updates a user feedback entry
+	Update(ctx context.Context, userFeedback *model.UserFeedback) error
+
+	// FindByProfileAndAPIKey finds the feedback an API key gave on a profile,
+	// or nil if it gave none
+	FindByProfileAndAPIKey(ctx context.Context, profileID, apiKeyID uint) (*model.UserFeedback, error)
+
 	// FindByProfileID finds user feedback entries by profile ID
 	FindByProfileID(ctx context.Context, profileID uint) ([]*model.UserFeedback, error)
 
 	// FindByFeedbackType finds user feedback entries by feedback type
 	FindByFeedbackType(ctx context.Context, feedbackType string) ([]*model.UserFeedback, error)
 
-	// Count counts all user feedback entries
-	Count(ctx context.Context) (int64, error)
+	// FindAll finds a page of user feedback entries, newest first. An empty
+	// feedback type matches every type.
+	FindAll(ctx context.Context, feedbackType string, limit, offset int) ([]*model.UserFeedback, error)
+
+	// Count counts user feedback entries of a type, or all entries if the type is empty
+	Count(ctx context.Context, feedbackType string) (int64, error)
+
+	// CountByProfileIDs counts the feedback entries of each type for the given profiles
+	CountByProfileIDs(ctx context.Context, profileIDs []uint) ([]FeedbackCount, error)
 }
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/accio/internal/domain/model"
+)
+
+// WatchlistRepository defines the interface for watchlist data access
+type WatchlistRepository interface {
+	// CreateEntry creates a new watchlist entry
+	CreateEntry(ctx context.Context, entry *model.WatchlistEntry) error
+
+	// UpdateEntry updates an existing watchlist entry, doing nothing if it was deleted
+	UpdateEntry(ctx context.Context, entry *model.WatchlistEntry) error
+
+	// FindEntryByID finds a watchlist entry by ID
+	FindEntryByID(ctx context.Context, id uint) (*model.WatchlistEntry, error)
+
+	// FindEntryByUsername finds the watchlist entry of a username
+	FindEntryByUsername(ctx context.Context, username string) (*model.WatchlistEntry, error)
+
+	// FindEntries finds a page of watchlist entries in the order they were created
+	FindEntries(ctx context.Context, limit, offset int) ([]*model.WatchlistEntry, error)
+
+	// CountEntries counts all watchlist entries
+	CountEntries(ctx context.Context) (int64, error)
+
+	// FindDueEntries finds up to limit entries whose next run is at or before
+	// now, longest overdue first
+	FindDueEntries(ctx context.Context, now time.Time, limit int) ([]*model.WatchlistEntry, error)
+
+	// DeleteEntry deletes a watchlist entry along with its snapshots and alerts
+	DeleteEntry(ctx context.Context, id uint) error
+
+	// CreateSnapshot creates a snapshot with its accounts and the alerts it
+	// raised in one transaction. It does nothing if the entry was deleted.
+	CreateSnapshot(ctx context.Context, snapshot *model.WatchlistSnapshot, alerts []*model.WatchlistAlert) error
+
+	// FindLatestSnapshot finds the latest snapshot of an entry along with its accounts
+	FindLatestSnapshot(ctx context.Context, entryID uint) (*model.WatchlistSnapshot, error)
+
+	// FindSnapshots finds a page of the snapshots of an entry along with
+	// their accounts, latest first
+	FindSnapshots(ctx context.Context, entryID uint, limit, offset int) ([]*model.WatchlistSnapshot, error)
+
+	// CountSnapshots counts the snapshots of an entry
+	CountSnapshots(ctx context.Context, entryID uint) (int64, error)
+
+	// FindAlerts finds a page of alerts, latest first, of one entry or of
+	// every entry if entryID is 0
+	FindAlerts(ctx context.Context, entryID uint, limit, offset int) ([]*model.WatchlistAlert, error)
+
+	// CountAlerts counts the alerts of one entry or of every entry if entryID is 0
+	CountAlerts(ctx context.Context, entryID uint) (int64, error)
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/accio/internal/domain/model"
+)
+
+// WebhookRepository defines the interface for webhook and delivery data access
+type WebhookRepository interface {
+	// CreateWebhook creates a new webhook
+	CreateWebhook(ctx context.Context, webhook *model.Webhook) error
+
+	// FindWebhookByID finds a webhook by ID
+	FindWebhookByID(ctx context.Context, id uint) (*model.Webhook, error)
+
+	// FindWebhooks finds a page of webhooks in the order they were created
+	FindWebhooks(ctx context.Context, limit, offset int) ([]*model.Webhook, error)
+
+	// FindAllWebhooks finds every webhook
+	FindAllWebhooks(ctx context.Context) ([]*model.Webhook, error)
+
+	// CountWebhooks counts all webhooks
+	CountWebhooks(ctx context.Context) (int64, error)
+
+	// DeleteWebhook deletes a webhook along with its deliveries
+	DeleteWebhook(ctx context.Context, id uint) error
+
+	// CreateDeliveries creates deliveries in one transaction
+	CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error
+
+	// UpdateDelivery updates an existing delivery
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+
+	// FindDeliveryByID finds a delivery by ID
+	FindDeliveryByID(ctx context.Context, id uint) (*model.WebhookDelivery, error)
+
+	// FindDeliveries finds a page of the deliveries of a webhook, latest first
+	FindDeliveries(ctx context.Context, webhookID uint, limit, offset int) ([]*model.WebhookDelivery, error)
+
+	// CountDeliveries counts the deliveries of a webhook
+	CountDeliveries(ctx context.Context, webhookID uint) (int64, error)
+
+	// FindDueDeliveries finds up to limit pending deliveries whose next
+	// attempt is at or before now, oldest first
+	FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error)
+}
@@ -2,26 +2,48 @@ package service
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/accio/internal/application/dto"
 	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
 )
 
+// ErrUnsupportedPlatform is returned when no platform client is registered for a platform
+var ErrUnsupportedPlatform = errors.New("unsupported platform")
+
 // ProfileService defines the interface for profile-related operations
 type ProfileService interface {
-	// GetProfileByUsername gets a profile by username from a specific platform
+	// GetProfileByUsername gets a profile by username from a specific platform,
+	// serving the stored profile while it is fresh
 	GetProfileByUsername(ctx context.Context, username, platform string) (*dto.ProfileDTO, error)
 
-	// SearchProfilesByName searches for profiles by real name
+	// RefreshProfileByUsername fetches a profile from its platform regardless
+	// of the stored profile and saves it
+	RefreshProfileByUsername(ctx context.Context, username, platform string) (*dto.ProfileDTO, error)
+
+	// SearchProfilesByName searches for profiles by real name, best matches first
 	SearchProfilesByName(ctx context.Context, name string) ([]*dto.ProfileDTO, error)
 
+	// ListProfiles lists stored profiles matching the filter, along with the total count
+	ListProfiles(ctx context.Context, filter repository.ProfileFilter, limit, offset int) ([]*dto.ProfileDTO, int64, error)
+
 	// GetProfileImage gets a profile image
 	GetProfileImage(ctx context.Context, profile *model.Profile) (io.ReadCloser, error)
 
-	// SaveProfile saves a profile to the repository
+	// SaveProfile saves a profile fetched from its platform, updating the
+	// stored profile with the same username and platform, and records the
+	// fetched state as a snapshot
 	SaveProfile(ctx context.Context, profile *model.Profile) error
 
+	// ListSnapshots lists the snapshots of a stored profile, latest first,
+	// each with the changes since the one before it, along with the total count
+	ListSnapshots(ctx context.Context, profileID uint, limit, offset int) ([]*dto.ProfileSnapshotDTO, int64, error)
+
+	// DiffSnapshots compares two snapshots of a stored profile
+	DiffSnapshots(ctx context.Context, profileID, fromID, toID uint) (*dto.ProfileSnapshotDiffDTO, error)
+
 	// GetSupportedPlatforms returns a list of supported platforms
 	GetSupportedPlatforms() []string
 }
@@ -0,0 +1,308 @@
+package identity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/accio/internal/crosslink"
+	"github.com/accio/internal/image"
+	"github.com/accio/internal/matcher"
+)
+
+// Signals of an edge between two profiles
+const (
+	SignalSameUsername = "same_username"
+	SignalSimilarName  = "similar_name"
+	SignalLink         = "link"
+	SignalSharedLink   = "shared_link"
+	SignalAvatar       = "avatar_hash"
+	SignalLocation     = "same_location"
+)
+
+// DefaultThreshold is the edge weight at which two profiles are considered
+// the same person
+const DefaultThreshold = 0.7
+
+// maxAvatarDistance is the most bits two avatar hashes may differ by to be
+// considered the same picture
+const maxAvatarDistance = 5
+
+// avatarBands is the number of slices avatar hashes are split into for
+// blocking. Hashes differing in at most maxAvatarDistance bits have at least
+// one slice in common.
+const avatarBands = maxAvatarDistance + 1
+
+// maxBlockSize is the most profiles sharing a blocking key that are compared
+// with each other. Larger blocks come from keys such as common first names,
+// default profile pictures or big cities that say little about a person, and
+// would make the comparisons quadratic again.
+const maxBlockSize = 200
+
+// Weights are the weights of the signals of an edge, summed into its weight
+type Weights struct {
+	SameUsername float64 // Same username, ignoring case, on different platforms
+	SimilarName  float64 // Real names that match with matcher.MatchesName
+	Link         float64 // One profile links to the other
+	SharedLink   float64 // Both profiles link to the same third profile
+	Avatar       float64 // Profile pictures with nearly the same average hash
+	Location     float64 // Same location in the platform data
+}
+
+// DefaultWeights returns weights under which an explicit link alone reaches
+// DefaultThreshold, as do most pairs of the other signals, while none of the
+// other signals does alone
+func DefaultWeights() Weights {
+	return Weights{
+		SameUsername: 0.4,
+		SimilarName:  0.4,
+		Link:         1.0,
+		SharedLink:   0.3,
+		Avatar:       0.6,
+		Location:     0.2,
+	}
+}
+
+// Node is a profile in the identity graph
+type Node struct {
+	ProfileID uint
+	Platform  string
+	Username  string
+	RealName  string
+	Location  string
+	ImageHash string             // Formatted with image.FormatHash, or empty
+	Links     []crosslink.Handle // Profiles this profile links to
+}
+
+// Edge connects two profiles that may belong to the same person
+type Edge struct {
+	From    uint     // Profile ID of one end
+	To      uint     // Profile ID of the other end
+	Weight  float64  // Sum of the weights of the signals
+	Signals []string // Signals connecting the profiles
+}
+
+// Graph is a graph of profiles with weighted edges
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Build builds the identity graph of the nodes. Only the pairs of nodes that
+// share a blocking key, such as a username, a name token or a slice of the
+// avatar hash, are compared.
+func Build(nodes []Node, weights Weights) *Graph {
+	g := &Graph{Nodes: nodes}
+
+	// Index profiles by platform and username to resolve links
+	keys := make([]string, len(nodes))
+	targets := make([]map[string]bool, len(nodes))
+	for i, node := range nodes {
+		keys[i] = handleKey(node.Platform, node.Username)
+		targets[i] = make(map[string]bool)
+		for _, link := range node.Links {
+			targets[i][handleKey(link.Site, link.Username)] = true
+		}
+	}
+
+	names := make([]*matcher.NameInfo, len(nodes))
+	locations := make([]string, len(nodes))
+	hashes := make([]*uint64, len(nodes))
+	for i, node := range nodes {
+		if strings.TrimSpace(node.RealName) != "" {
+			names[i] = matcher.ParseFullName(node.RealName)
+		}
+		locations[i] = matcher.NormalizeName(node.Location)
+		if hash, err := image.ParseHash(node.ImageHash); err == nil && node.ImageHash != "" {
+			hashes[i] = &hash
+		}
+	}
+
+	// Every signal needs a key in common, so pairs in no common block could
+	// not be joined by an edge anyway
+	blocks := make(map[string][]int)
+	for i, node := range nodes {
+		for _, key := range blockingKeys(node, keys[i], targets[i], hashes[i], locations[i]) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	for _, pair := range candidatePairs(blocks) {
+		i, j := pair[0], pair[1]
+		a, b := nodes[i], nodes[j]
+		var edge Edge
+		add := func(signal string, weight float64) {
+			edge.Signals = append(edge.Signals, signal)
+			edge.Weight += weight
+		}
+
+		if a.Platform != b.Platform && strings.EqualFold(a.Username, b.Username) {
+			add(SignalSameUsername, weights.SameUsername)
+		}
+		if names[i] != nil && names[j] != nil && (names[i].MatchesName(b.RealName) || names[j].MatchesName(a.RealName)) {
+			add(SignalSimilarName, weights.SimilarName)
+		}
+		if targets[i][keys[j]] || targets[j][keys[i]] {
+			add(SignalLink, weights.Link)
+		} else if sharesTarget(targets[i], targets[j]) {
+			add(SignalSharedLink, weights.SharedLink)
+		}
+		if hashes[i] != nil && hashes[j] != nil && image.HashDistance(*hashes[i], *hashes[j]) <= maxAvatarDistance {
+			add(SignalAvatar, weights.Avatar)
+		}
+		if locations[i] != "" && locations[i] == locations[j] {
+			add(SignalLocation, weights.Location)
+		}
+
+		if len(edge.Signals) > 0 {
+			edge.From, edge.To = a.ProfileID, b.ProfileID
+			g.Edges = append(g.Edges, edge)
+		}
+	}
+
+	return g
+}
+
+// Clusters groups the nodes into the connected components of the edges
+// weighing at least threshold. Profiles without such edges form their own
+// cluster. Clusters are ordered by size, largest first, and then by their
+// lowest profile ID; the nodes of a cluster are ordered by profile ID.
+func (g *Graph) Clusters(threshold float64) [][]Node {
+	index := make(map[uint]int, len(g.Nodes))
+	parent := make([]int, len(g.Nodes))
+	for i, node := range g.Nodes {
+		index[node.ProfileID] = i
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for _, edge := range g.Edges {
+		if edge.Weight < threshold {
+			continue
+		}
+		from, to := find(index[edge.From]), find(index[edge.To])
+		if from != to {
+			parent[from] = to
+		}
+	}
+
+	components := make(map[int][]Node)
+	for i, node := range g.Nodes {
+		root := find(i)
+		components[root] = append(components[root], node)
+	}
+
+	clusters := make([][]Node, 0, len(components))
+	for _, cluster := range components {
+		sort.Slice(cluster, func(i, j int) bool {
+			return cluster[i].ProfileID < cluster[j].ProfileID
+		})
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i]) != len(clusters[j]) {
+			return len(clusters[i]) > len(clusters[j])
+		}
+		return clusters[i][0].ProfileID < clusters[j][0].ProfileID
+	})
+
+	return clusters
+}
+
+// EdgesWithin returns the edges between nodes of the cluster weighing at
+// least threshold
+func (g *Graph) EdgesWithin(cluster []Node, threshold float64) []Edge {
+	members := make(map[uint]bool, len(cluster))
+	for _, node := range cluster {
+		members[node.ProfileID] = true
+	}
+
+	var edges []Edge
+	for _, edge := range g.Edges {
+		if edge.Weight >= threshold && members[edge.From] && members[edge.To] {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// blockingKeys returns the keys of the blocks a node belongs to: its
+// username, the tokens of its real name, the slices of its avatar hash, its
+// own and linked handles and its location
+func blockingKeys(node Node, key string, targets map[string]bool, hash *uint64, location string) []string {
+	keys := []string{"username:" + strings.ToLower(node.Username), "handle:" + key}
+	for target := range targets {
+		keys = append(keys, "handle:"+target)
+	}
+	for _, token := range strings.Fields(matcher.NormalizeName(node.RealName)) {
+		// Initials match too many names to narrow anything down
+		if len([]rune(token)) > 1 {
+			keys = append(keys, "name:"+token)
+		}
+	}
+	if hash != nil {
+		for band := 0; band < avatarBands; band++ {
+			from, to := band*64/avatarBands, (band+1)*64/avatarBands
+			slice := (*hash >> from) & (1<<(to-from) - 1)
+			keys = append(keys, fmt.Sprintf("avatar:%d:%x", band, slice))
+		}
+	}
+	if location != "" {
+		keys = append(keys, "location:"+location)
+	}
+	return keys
+}
+
+// candidatePairs returns the pairs of node indexes sharing a block of at most
+// maxBlockSize nodes, each pair once and in order
+func candidatePairs(blocks map[string][]int) [][2]int {
+	seen := make(map[[2]int]bool)
+	var pairs [][2]int
+	for _, block := range blocks {
+		if len(block) < 2 || len(block) > maxBlockSize {
+			continue
+		}
+		for x := range block {
+			for y := x + 1; y < len(block); y++ {
+				pair := [2]int{min(block[x], block[y]), max(block[x], block[y])}
+				if pair[0] != pair[1] && !seen[pair] {
+					seen[pair] = true
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	return pairs
+}
+
+// handleKey identifies a username on a platform
+func handleKey(platform, username string) string {
+	return strings.ToLower(platform) + "/" + strings.ToLower(username)
+}
+
+// sharesTarget reports whether two sets of link targets intersect
+func sharesTarget(a, b map[string]bool) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	for key := range a {
+		if b[key] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package identity
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/accio/internal/crosslink"
+	"github.com/accio/internal/image"
+)
+
+func TestBuild(t *testing.T) {
+	nodes := []Node{
+		{ProfileID: 1, Platform: "GitHub", Username: "jdoe", RealName: "John Doe", ImageHash: "ff00ff00ff00ff00", Links: []crosslink.Handle{{Site: "Twitter", Username: "john.doe"}}},
+		{ProfileID: 2, Platform: "Twitter", Username: "john.doe", ImageHash: "ff00ff00ff00ff01"},
+		{ProfileID: 3, Platform: "Twitch", Username: "JDoe", RealName: "J. Doe", Location: "Berlin"},
+		{ProfileID: 4, Platform: "Reddit", Username: "someone", Location: "berlin"},
+	}
+
+	g := Build(nodes, DefaultWeights())
+
+	signals := make(map[[2]uint][]string)
+	for _, edge := range g.Edges {
+		signals[[2]uint{edge.From, edge.To}] = edge.Signals
+	}
+
+	want := map[[2]uint][]string{
+		{1, 2}: {SignalLink, SignalAvatar},
+		{1, 3}: {SignalSameUsername, SignalSimilarName},
+		{3, 4}: {SignalLocation},
+	}
+	if !reflect.DeepEqual(signals, want) {
+		t.Errorf("Build() edges = %v, want %v", signals, want)
+	}
+}
+
+func TestBuildBlocking(t *testing.T) {
+	// One differing bit in each of the first five slices of the hash still
+	// leaves a slice in common
+	nearby := image.FormatHash(1<<0 | 1<<11 | 1<<22 | 1<<33 | 1<<43)
+	nodes := []Node{
+		{ProfileID: 1, Platform: "GitHub", Username: "alpha", ImageHash: "0000000000000000"},
+		{ProfileID: 2, Platform: "Twitter", Username: "beta", ImageHash: nearby},
+		{ProfileID: 3, Platform: "Twitch", Username: "gamma"},
+	}
+
+	g := Build(nodes, DefaultWeights())
+	if len(g.Edges) != 1 || g.Edges[0].From != 1 || g.Edges[0].To != 2 || !reflect.DeepEqual(g.Edges[0].Signals, []string{SignalAvatar}) {
+		t.Errorf("Expected one avatar edge between profiles 1 and 2, got %+v", g.Edges)
+	}
+
+	// Profiles only sharing a name too common to block on are not compared
+	var common []Node
+	for i := 0; i <= maxBlockSize; i++ {
+		common = append(common, Node{ProfileID: uint(i + 1), Platform: "GitHub", Username: fmt.Sprintf("user%d", i), RealName: "Alex"})
+	}
+	if edges := Build(common, DefaultWeights()).Edges; len(edges) != 0 {
+		t.Errorf("Expected no edges within an oversized block, got %d", len(edges))
+	}
+}
+
+func TestClusters(t *testing.T) {
+	g := &Graph{
+		Nodes: []Node{{ProfileID: 1}, {ProfileID: 2}, {ProfileID: 3}, {ProfileID: 4}, {ProfileID: 5}},
+		Edges: []Edge{
+			{From: 1, To: 2, Weight: 1.0},
+			{From: 2, To: 5, Weight: 0.8},
+			{From: 3, To: 4, Weight: 0.5}, // Below the threshold
+		},
+	}
+
+	var got [][]uint
+	for _, cluster := range g.Clusters(DefaultThreshold) {
+		var ids []uint
+		for _, node := range cluster {
+			ids = append(ids, node.ProfileID)
+		}
+		got = append(got, ids)
+	}
+
+	want := [][]uint{{1, 2, 5}, {3}, {4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Clusters() = %v, want %v", got, want)
+	}
+
+	if edges := g.EdgesWithin(g.Clusters(DefaultThreshold)[0], DefaultThreshold); len(edges) != 2 {
+		t.Errorf("Expected 2 edges within the largest cluster, got %d", len(edges))
+	}
+}
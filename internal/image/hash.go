@@ -0,0 +1,80 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"math/bits"
+	"strconv"
+)
+
+// hashSize is the width and height of the grid an image is reduced to for
+// hashing, giving a 64-bit hash
+const hashSize = 8
+
+// AverageHash computes the perceptual average hash of an image: the image is
+// reduced to an 8x8 grayscale grid and each bit records whether a cell is
+// brighter than the mean. Resized or recompressed copies of a picture have
+// the same or nearly the same hash.
+func AverageHash(img image.Image) uint64 {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return 0
+	}
+
+	var cells [hashSize * hashSize]float64
+	var counts [hashSize * hashSize]int
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := (y - bounds.Min.Y) * hashSize / height
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			col := (x - bounds.Min.X) * hashSize / width
+			r, g, b, _ := img.At(x, y).RGBA()
+			cells[row*hashSize+col] += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			counts[row*hashSize+col]++
+		}
+	}
+
+	var mean float64
+	for i := range cells {
+		if counts[i] > 0 {
+			cells[i] /= float64(counts[i])
+		}
+		mean += cells[i]
+	}
+	mean /= float64(len(cells))
+
+	var hash uint64
+	for i, cell := range cells {
+		if cell > mean {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// HashImage decodes an image and returns its average hash formatted with
+// FormatHash
+func HashImage(r io.Reader) (string, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	return FormatHash(AverageHash(img)), nil
+}
+
+// FormatHash formats a hash as 16 hexadecimal digits
+func FormatHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// ParseHash parses a hash formatted with FormatHash
+func ParseHash(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
+
+// HashDistance returns the number of differing bits between two hashes.
+// Copies of a picture are usually within 5 bits.
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -28,10 +28,7 @@ type ImageProcessor struct {
 
 // NewImageProcessor creates a new image processor
 func NewImageProcessor() *ImageProcessor {
-	cacheDir := os.Getenv("PROFILE_IMAGE_CACHE_DIR")
-	if cacheDir == "" {
-		cacheDir = CacheDir
-	}
+	cacheDir := CacheDirFromEnv()
 
 	// Create cache directory if it doesn't exist
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
@@ -43,6 +40,30 @@ func NewImageProcessor() *ImageProcessor {
 	}
 }
 
+// CacheDirFromEnv returns the cache directory set in PROFILE_IMAGE_CACHE_DIR,
+// or CacheDir if it is not set
+func CacheDirFromEnv() string {
+	if cacheDir := os.Getenv("PROFILE_IMAGE_CACHE_DIR"); cacheDir != "" {
+		return cacheDir
+	}
+	return CacheDir
+}
+
+// CheckCacheDir verifies the cache directory is writable
+func (p *ImageProcessor) CheckCacheDir() error {
+	file, err := os.CreateTemp(p.CacheDir, ".healthcheck-*")
+	if err != nil {
+		return fmt.Errorf("cache directory %s is not writable: %w", p.CacheDir, err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString("ok"); err != nil {
+		file.Close()
+		return fmt.Errorf("cache directory %s is not writable: %w", p.CacheDir, err)
+	}
+	return file.Close()
+}
+
 // ProcessImage processes an image from a reader
 func (p *ImageProcessor) ProcessImage(imageData io.Reader, size int) (image.Image, error) {
 	// Decode image
@@ -112,6 +133,21 @@ func (p *ImageProcessor) IsCachedImageAvailable(username, platform string) bool
 	return err == nil
 }
 
+// CachedThumbnail returns a cached image scaled down to fit size x size,
+// encoded as PNG
+func (p *ImageProcessor) CachedThumbnail(username, platform string, size int) ([]byte, error) {
+	img, err := imaging.Open(p.GetCachedImagePath(username, platform))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, imaging.Fit(img, size, size, imaging.Lanczos)); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
 // DisplayImage displays an image in the terminal
 func (p *ImageProcessor) DisplayImage(img image.Image) (string, error) {
 	// Check if terminal supports images
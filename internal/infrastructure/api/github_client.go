@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/accio/internal/domain/model"
+)
+
+// GitHubClient is a client for the GitHub API
+type GitHubClient struct {
+	*BaseClient
+	Token string
+}
+
+// GitHubUser represents a GitHub user from the API
+type GitHubUser struct {
+	Login           string    `json:"login"`
+	ID              int       `json:"id"`
+	AvatarURL       string    `json:"avatar_url"`
+	HTMLURL         string    `json:"html_url"`
+	Type            string    `json:"type"`
+	SiteAdmin       bool      `json:"site_admin"`
+	Name            string    `json:"name"`
+	Company         string    `json:"company"`
+	Blog            string    `json:"blog"`
+	Location        string    `json:"location"`
+	Email           string    `json:"email"`
+	Bio             string    `json:"bio"`
+	TwitterUsername string    `json:"twitter_username"`
+	PublicRepos     int       `json:"public_repos"`
+	PublicGists     int       `json:"public_gists"`
+	Followers       int       `json:"followers"`
+	Following       int       `json:"following"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// GitHubSearchResponse represents a GitHub search response
+type GitHubSearchResponse struct {
+	TotalCount        int          `json:"total_count"`
+	IncompleteResults bool         `json:"incomplete_results"`
+	Items             []GitHubUser `json:"items"`
+}
+
+// NewGitHubClient creates a new GitHub API client. GITHUB_TOKEN is optional
+// but raises the API rate limit.
+func NewGitHubClient() (PlatformClient, error) {
+	return &GitHubClient{
+		BaseClient: NewBaseClient(),
+		Token:      os.Getenv("GITHUB_TOKEN"),
+	}, nil
+}
+
+// GetPlatformName returns the name of the platform
+func (c *GitHubClient) GetPlatformName() string {
+	return "GitHub"
+}
+
+// CheckCredentials validates the token, if any, without calling the API
+func (c *GitHubClient) CheckCredentials() error {
+	if strings.ContainsAny(c.Token, " \t\r\n") {
+		return fmt.Errorf("%w: GITHUB_TOKEN contains whitespace", ErrUnauthorized)
+	}
+	return nil
+}
+
+// GetProfileByUsername gets a GitHub profile by username
+func (c *GitHubClient) GetProfileByUsername(ctx context.Context, username string) (*model.Profile, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/users/%s", url.PathEscape(username))
+
+	var user GitHubUser
+	if err := c.get(ctx, apiURL, &user); err != nil {
+		return nil, err
+	}
+
+	return c.githubUserToProfile(&user), nil
+}
+
+// SearchProfilesByName searches for GitHub profiles by real name
+func (c *GitHubClient) SearchProfilesByName(ctx context.Context, name string) ([]*model.Profile, error) {
+	params := url.Values{}
+	params.Add("q", name+" in:name")
+	params.Add("per_page", "10")
+
+	var response GitHubSearchResponse
+	if err := c.get(ctx, "https://api.github.com/search/users?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	// Search results only hold the login, so fetch each full profile
+	var profiles []*model.Profile
+	for _, user := range response.Items {
+		profile, err := c.GetProfileByUsername(ctx, user.Login)
+		if err != nil {
+			// Skip this user if there's an error
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+// GetProfileImage gets a GitHub profile image
+func (c *GitHubClient) GetProfileImage(ctx context.Context, profile *model.Profile) (io.ReadCloser, error) {
+	if profile.ImageURL == "" {
+		return nil, fmt.Errorf("profile has no image URL")
+	}
+
+	return c.DownloadImage(ctx, profile.ImageURL)
+}
+
+// get makes an authenticated GET request and decodes the JSON response
+func (c *GitHubClient) get(ctx context.Context, apiURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", c.UserAgent)
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// GitHub reports an exhausted rate limit as 403
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	} else if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	} else if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status code %d", ErrAPIError, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// githubUserToProfile converts a GitHub user to a profile
+func (c *GitHubClient) githubUserToProfile(user *GitHubUser) *model.Profile {
+	profile := model.NewProfile(
+		user.Name,
+		user.Login,
+		"GitHub",
+		user.HTMLURL,
+		user.AvatarURL,
+		user.Bio,
+		user.SiteAdmin,
+		int64(user.Followers),
+	)
+
+	// Add platform data
+	profile.AddPlatformData("user_id", fmt.Sprintf("%d", user.ID))
+	profile.AddPlatformData("public_repos", fmt.Sprintf("%d", user.PublicRepos))
+	profile.AddPlatformData("public_gists", fmt.Sprintf("%d", user.PublicGists))
+	profile.AddPlatformData("following", fmt.Sprintf("%d", user.Following))
+	profile.AddPlatformData("created_at", user.CreatedAt.Format("2006-01-02"))
+	profile.AddPlatformData("updated_at", user.UpdatedAt.Format("2006-01-02"))
+
+	if user.Email != "" {
+		profile.AddPlatformData("email", user.Email)
+	}
+	if user.Company != "" {
+		profile.AddPlatformData("company", user.Company)
+	}
+	if user.Location != "" {
+		profile.AddPlatformData("location", user.Location)
+	}
+	if user.Blog != "" {
+		profile.AddPlatformData("blog", user.Blog)
+	}
+	if user.TwitterUsername != "" {
+		profile.AddPlatformData("twitter_username", user.TwitterUsername)
+	}
+
+	// Parse name parts
+	addNameParts(profile, user.Name)
+
+	return profile
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/metrics"
+)
+
+// InstrumentedClient records metrics for every call to a platform client
+type InstrumentedClient struct {
+	client PlatformClient
+}
+
+// NewInstrumentedClient wraps a platform client with metrics
+func NewInstrumentedClient(client PlatformClient) PlatformClient {
+	return &InstrumentedClient{
+		client: client,
+	}
+}
+
+// GetProfileByUsername gets a profile by username
+func (c *InstrumentedClient) GetProfileByUsername(ctx context.Context, username string) (*model.Profile, error) {
+	profile, err := c.client.GetProfileByUsername(ctx, username)
+	c.record("get_profile", err)
+	return profile, err
+}
+
+// SearchProfilesByName searches for profiles by real name
+func (c *InstrumentedClient) SearchProfilesByName(ctx context.Context, name string) ([]*model.Profile, error) {
+	profiles, err := c.client.SearchProfilesByName(ctx, name)
+	c.record("search", err)
+	return profiles, err
+}
+
+// GetProfileImage gets a profile image
+func (c *InstrumentedClient) GetProfileImage(ctx context.Context, profile *model.Profile) (io.ReadCloser, error) {
+	image, err := c.client.GetProfileImage(ctx, profile)
+	c.record("get_image", err)
+	return image, err
+}
+
+// GetPlatformName returns the name of the platform
+func (c *InstrumentedClient) GetPlatformName() string {
+	return c.client.GetPlatformName()
+}
+
+// CheckCredentials validates the wrapped client's credentials, if it can
+func (c *InstrumentedClient) CheckCredentials() error {
+	if checker, ok := c.client.(CredentialChecker); ok {
+		return checker.CheckCredentials()
+	}
+	return nil
+}
+
+// record counts a call and, if it failed, its error class
+func (c *InstrumentedClient) record(operation string, err error) {
+	platform := c.client.GetPlatformName()
+	metrics.PlatformRequests.WithLabelValues(platform, operation).Inc()
+	if err != nil {
+		metrics.PlatformErrors.WithLabelValues(platform, operation, errorClass(err)).Inc()
+	}
+}
+
+// errorClass returns the metric label for a platform client error
+func errorClass(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, ErrInvalidParams):
+		return "invalid_params"
+	case errors.Is(err, ErrAPIError):
+		return "api_error"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorClass(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected string
+	}{
+		{ErrNotFound, "not_found"},
+		{fmt.Errorf("%w: retry after 60s", ErrRateLimited), "rate_limited"},
+		{ErrUnauthorized, "unauthorized"},
+		{ErrInvalidParams, "invalid_params"},
+		{fmt.Errorf("%w: status 500", ErrAPIError), "api_error"},
+		{context.DeadlineExceeded, "timeout"},
+		{errors.New("connection reset"), "other"},
+	}
+
+	for _, tc := range testCases {
+		if result := errorClass(tc.err); result != tc.expected {
+			t.Errorf("errorClass(%v): expected %s, got %s", tc.err, tc.expected, result)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/accio/internal/domain/model"
@@ -34,6 +35,13 @@ type PlatformClient interface {
 	GetPlatformName() string
 }
 
+// CredentialChecker is implemented by platform clients that can validate
+// their configured credentials locally, without calling the platform API
+type CredentialChecker interface {
+	// CheckCredentials returns an error if the credentials are unusable
+	CheckCredentials() error
+}
+
 // BaseClient provides common functionality for all API clients
 type BaseClient struct {
 	HTTPClient *http.Client
@@ -71,3 +79,17 @@ func (c *BaseClient) DownloadImage(ctx context.Context, imageURL string) (io.Rea
 
 	return resp.Body, nil
 }
+
+// addNameParts splits a display name into first, middle and last name parts
+func addNameParts(profile *model.Profile, name string) {
+	nameParts := strings.Fields(name)
+	if len(nameParts) > 0 {
+		profile.AddNamePart(nameParts[0], "first")
+	}
+	if len(nameParts) > 1 {
+		profile.AddNamePart(nameParts[len(nameParts)-1], "last")
+	}
+	if len(nameParts) > 2 {
+		profile.AddNamePart(strings.Join(nameParts[1:len(nameParts)-1], " "), "middle")
+	}
+}
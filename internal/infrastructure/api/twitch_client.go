@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/accio/internal/domain/model"
+)
+
+// TwitchClient is a client for the Twitch API
+type TwitchClient struct {
+	*BaseClient
+	ClientID     string
+	ClientSecret string
+
+	tokenMutex  sync.Mutex
+	accessToken string
+	tokenExpiry time.Time
+}
+
+// TwitchUser represents a Twitch user from the API
+type TwitchUser struct {
+	ID              string    `json:"id"`
+	Login           string    `json:"login"`
+	DisplayName     string    `json:"display_name"`
+	Type            string    `json:"type"`
+	BroadcasterType string    `json:"broadcaster_type"`
+	Description     string    `json:"description"`
+	ProfileImageURL string    `json:"profile_image_url"`
+	OfflineImageURL string    `json:"offline_image_url"`
+	ViewCount       int       `json:"view_count"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// TwitchUsersResponse represents a Twitch users response
+type TwitchUsersResponse struct {
+	Data []TwitchUser `json:"data"`
+}
+
+// TwitchChannel represents a channel in a Twitch channel search response
+type TwitchChannel struct {
+	BroadcasterLogin string `json:"broadcaster_login"`
+	DisplayName      string `json:"display_name"`
+}
+
+// TwitchTokenResponse represents a Twitch OAuth token response
+type TwitchTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+}
+
+// NewTwitchClient creates a new Twitch API client. The access token is
+// requested on first use.
+func NewTwitchClient() (PlatformClient, error) {
+	clientID := os.Getenv("TWITCH_CLIENT_ID")
+	clientSecret := os.Getenv("TWITCH_CLIENT_SECRET")
+	if clientID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("TWITCH_CLIENT_ID and TWITCH_CLIENT_SECRET environment variables must be set")
+	}
+
+	return &TwitchClient{
+		BaseClient:   NewBaseClient(),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	}, nil
+}
+
+// GetPlatformName returns the name of the platform
+func (c *TwitchClient) GetPlatformName() string {
+	return "Twitch"
+}
+
+// CheckCredentials validates the client credentials without calling the API
+func (c *TwitchClient) CheckCredentials() error {
+	if strings.ContainsAny(c.ClientID+c.ClientSecret, " \t\r\n") {
+		return fmt.Errorf("%w: TWITCH_CLIENT_ID or TWITCH_CLIENT_SECRET contains whitespace", ErrUnauthorized)
+	}
+	return nil
+}
+
+// GetProfileByUsername gets a Twitch profile by username
+func (c *TwitchClient) GetProfileByUsername(ctx context.Context, username string) (*model.Profile, error) {
+	params := url.Values{}
+	params.Add("login", strings.ToLower(username))
+
+	var response TwitchUsersResponse
+	if err := c.get(ctx, "https://api.twitch.tv/helix/users?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Data) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return c.twitchUserToProfile(&response.Data[0]), nil
+}
+
+// SearchProfilesByName searches for Twitch profiles by display name
+func (c *TwitchClient) SearchProfilesByName(ctx context.Context, name string) ([]*model.Profile, error) {
+	params := url.Values{}
+	params.Add("query", name)
+	params.Add("first", "10")
+
+	var channels struct {
+		Data []TwitchChannel `json:"data"`
+	}
+	if err := c.get(ctx, "https://api.twitch.tv/helix/search/channels?"+params.Encode(), &channels); err != nil {
+		return nil, err
+	}
+	if len(channels.Data) == 0 {
+		return nil, nil
+	}
+
+	// Channel search results lack profile details, so look the users up
+	params = url.Values{}
+	for _, channel := range channels.Data {
+		params.Add("login", channel.BroadcasterLogin)
+	}
+
+	var users TwitchUsersResponse
+	if err := c.get(ctx, "https://api.twitch.tv/helix/users?"+params.Encode(), &users); err != nil {
+		return nil, err
+	}
+
+	var profiles []*model.Profile
+	for _, user := range users.Data {
+		profiles = append(profiles, c.twitchUserToProfile(&user))
+	}
+
+	return profiles, nil
+}
+
+// GetProfileImage gets a Twitch profile image
+func (c *TwitchClient) GetProfileImage(ctx context.Context, profile *model.Profile) (io.ReadCloser, error) {
+	if profile.ImageURL == "" {
+		return nil, fmt.Errorf("profile has no image URL")
+	}
+
+	return c.DownloadImage(ctx, profile.ImageURL)
+}
+
+// get makes an authenticated GET request and decodes the JSON response,
+// refreshing the access token once if it was rejected
+func (c *TwitchClient) get(ctx context.Context, apiURL string, v any) error {
+	for attempt := 0; ; attempt++ {
+		token, err := c.token(ctx, attempt > 0)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Client-ID", c.ClientID)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("User-Agent", c.UserAgent)
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to make request: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			// The token may have been revoked or expired early
+			resp.Body.Close()
+			continue
+		case resp.StatusCode == http.StatusUnauthorized:
+			resp.Body.Close()
+			return ErrUnauthorized
+		case resp.StatusCode == http.StatusTooManyRequests:
+			resp.Body.Close()
+			return ErrRateLimited
+		case resp.StatusCode != http.StatusOK:
+			resp.Body.Close()
+			return fmt.Errorf("%w: status code %d", ErrAPIError, resp.StatusCode)
+		}
+
+		err = json.NewDecoder(resp.Body).Decode(v)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		return nil
+	}
+}
+
+// token returns a valid app access token, requesting a new one if there is
+// none, it has expired or refresh is set
+func (c *TwitchClient) token(ctx context.Context, refresh bool) (string, error) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	if !refresh && c.accessToken != "" && time.Now().Before(c.tokenExpiry) {
+		return c.accessToken, nil
+	}
+
+	params := url.Values{}
+	params.Add("client_id", c.ClientID)
+	params.Add("client_secret", c.ClientSecret)
+	params.Add("grant_type", "client_credentials")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://id.twitch.tv/oauth2/token", strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", fmt.Errorf("%w: token request rejected with status code %d", ErrUnauthorized, resp.StatusCode)
+	} else if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: status code %d", ErrAPIError, resp.StatusCode)
+	}
+
+	var tokenResp TwitchTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Renew a minute early so requests in flight don't race the expiry
+	c.accessToken = tokenResp.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn)*time.Second - time.Minute)
+
+	return c.accessToken, nil
+}
+
+// twitchUserToProfile converts a Twitch user to a profile
+func (c *TwitchClient) twitchUserToProfile(user *TwitchUser) *model.Profile {
+	// Twitch has no separate real name, the display name is the closest
+	profile := model.NewProfile(
+		user.DisplayName,
+		user.Login,
+		"Twitch",
+		fmt.Sprintf("https://www.twitch.tv/%s", user.Login),
+		user.ProfileImageURL,
+		user.Description,
+		user.BroadcasterType == "partner" || user.Type == "admin" || user.Type == "staff",
+		0, // The users endpoint does not report followers
+	)
+
+	// Add platform data
+	profile.AddPlatformData("user_id", user.ID)
+	profile.AddPlatformData("broadcaster_type", user.BroadcasterType)
+	profile.AddPlatformData("user_type", user.Type)
+	profile.AddPlatformData("created_at", user.CreatedAt.Format("2006-01-02"))
+
+	// Parse name parts
+	addNameParts(profile, user.DisplayName)
+
+	return profile
+}
@@ -64,6 +64,14 @@ func (c *TwitterClient) GetPlatformName() string {
 	return "Twitter"
 }
 
+// CheckCredentials validates the bearer token without calling the API
+func (c *TwitterClient) CheckCredentials() error {
+	if strings.ContainsAny(c.BearerToken, " \t\r\n") {
+		return fmt.Errorf("%w: TWITTER_BEARER_TOKEN contains whitespace", ErrUnauthorized)
+	}
+	return nil
+}
+
 // GetProfileByUsername gets a Twitter profile by username
 func (c *TwitterClient) GetProfileByUsername(ctx context.Context, username string) (*model.Profile, error) {
 	// Clean username (remove @ if present)
@@ -206,16 +214,7 @@ func (c *TwitterClient) twitterUserToProfile(user *TwitterUser) *model.Profile {
 	profile.AddPlatformData("created_at", createdAt.Format("2006-01-02"))
 
 	// Parse name parts
-	nameParts := strings.Fields(user.Name)
-	if len(nameParts) > 0 {
-		profile.AddNamePart(nameParts[0], "first")
-	}
-	if len(nameParts) > 1 {
-		profile.AddNamePart(nameParts[len(nameParts)-1], "last")
-	}
-	if len(nameParts) > 2 {
-		profile.AddNamePart(strings.Join(nameParts[1:len(nameParts)-1], " "), "middle")
-	}
+	addNameParts(profile, user.Name)
 
 	return profile
 }
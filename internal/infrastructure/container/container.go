@@ -1,8 +1,14 @@
 package container
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	appservice "github.com/accio/internal/application/service"
 	"github.com/accio/internal/domain/repository"
@@ -21,10 +27,22 @@ type Container struct {
 	ProfileRepository       repository.ProfileRepository
 	SearchHistoryRepository repository.SearchHistoryRepository
 	UserFeedbackRepository  repository.UserFeedbackRepository
+	PersonRepository        repository.PersonRepository
+	APIKeyRepository        repository.APIKeyRepository
+	WatchlistRepository     repository.WatchlistRepository
+	WebhookRepository       repository.WebhookRepository
 
 	// Services
 	ProfileService       domainservice.ProfileService
 	SearchHistoryService appservice.SearchHistoryService
+	FeedbackService      appservice.FeedbackService
+	NameMatchService     appservice.NameMatchService
+	IdentityService      appservice.IdentityService
+	ScanJobService       appservice.ScanJobService
+	APIKeyService        appservice.APIKeyService
+	HealthService        appservice.HealthService
+	WatchlistService     appservice.WatchlistService
+	WebhookService       appservice.WebhookService
 
 	// Platform clients
 	PlatformClients map[string]api.PlatformClient
@@ -36,8 +54,21 @@ type Container struct {
 	Seeder *persistence.Seeder
 }
 
+// Options overrides the configuration a container reads from the environment
+type Options struct {
+	// NoCache fetches profiles from the platform APIs even if the stored
+	// profiles are fresh, like PROFILE_NO_CACHE=true
+	NoCache bool
+}
+
 // NewContainer creates a new dependency injection container
 func NewContainer() (*Container, error) {
+	return NewContainerWithOptions(Options{})
+}
+
+// NewContainerWithOptions creates a new dependency injection container with
+// options overriding the environment
+func NewContainerWithOptions(options Options) (*Container, error) {
 	container := &Container{
 		PlatformClients: make(map[string]api.PlatformClient),
 	}
@@ -53,44 +84,219 @@ func NewContainer() (*Container, error) {
 	container.ProfileRepository = persistence.NewGormProfileRepository(db.DB)
 	container.SearchHistoryRepository = persistence.NewGormSearchHistoryRepository(db.DB)
 	container.UserFeedbackRepository = persistence.NewGormUserFeedbackRepository(db.DB)
+	container.PersonRepository = persistence.NewGormPersonRepository(db.DB)
+	container.APIKeyRepository = persistence.NewGormAPIKeyRepository(db.DB)
+	container.WatchlistRepository = persistence.NewGormWatchlistRepository(db.DB)
+	container.WebhookRepository = persistence.NewGormWebhookRepository(db.DB)
 
 	// Initialize services
-	container.ProfileService = appservice.NewProfileService(container.ProfileRepository)
+	container.ProfileService = appservice.NewProfileService(profileServiceConfig(options), container.ProfileRepository, container.UserFeedbackRepository)
+	container.FeedbackService = appservice.NewFeedbackService(container.UserFeedbackRepository, container.ProfileRepository)
+	container.NameMatchService = appservice.NewNameMatchService(container.ProfileService)
+	container.IdentityService = appservice.NewIdentityService(container.ProfileRepository, container.PersonRepository)
 	container.SearchHistoryService = appservice.NewSearchHistoryService(container.SearchHistoryRepository)
+	container.WebhookService = appservice.NewWebhookService(webhookServiceConfig(), container.WebhookRepository)
+	container.ScanJobService = appservice.NewScanJobService(scanJobServiceConfig(), container.WebhookService)
+	container.APIKeyService = appservice.NewAPIKeyService(container.APIKeyRepository)
 
 	// Initialize platform clients
 	if err := container.initializePlatformClients(); err != nil {
 		log.Printf("Warning: Failed to initialize some platform clients: %v", err)
 	}
 
+	// The watchlist fetches profiles from the platform clients; its
+	// scheduler only runs once started by the web server
+	container.WatchlistService = appservice.NewWatchlistService(watchlistServiceConfig(), container.WatchlistRepository, container.PlatformClients, container.WebhookService)
+
 	// Initialize image processor
 	container.ImageProcessor = image.NewImageProcessor()
 
 	// Initialize seeder
 	container.Seeder = persistence.NewSeeder(db.DB)
 
+	// Initialize health checks
+	container.HealthService = appservice.NewHealthService(container.healthChecks())
+
 	return container, nil
 }
 
-// initializePlatformClients initializes platform clients
+// initializePlatformClients initializes the clients of every platform whose
+// credentials are configured
 func (c *Container) initializePlatformClients() error {
-	// Initialize Twitter client
-	twitterClient, err := api.NewTwitterClient()
-	if err == nil {
-		c.PlatformClients["Twitter"] = twitterClient
-		profileService, ok := c.ProfileService.(*appservice.ProfileServiceImpl)
-		if ok {
-			profileService.RegisterPlatformClient(twitterClient)
+	constructors := []func() (api.PlatformClient, error){
+		api.NewGitHubClient,
+		api.NewTwitterClient,
+		api.NewTwitchClient,
+	}
+
+	profileService, _ := c.ProfileService.(*appservice.ProfileServiceImpl)
+	for _, newClient := range constructors {
+		client, err := newClient()
+		if err != nil {
+			continue
+		}
+
+		client = api.NewInstrumentedClient(client)
+		c.PlatformClients[client.GetPlatformName()] = client
+		if profileService != nil {
+			profileService.RegisterPlatformClient(client)
 		}
 	}
 
-	// Initialize other platform clients here...
+	return nil
+}
 
+// platformCredentials lists the environment variables holding the
+// credentials of each platform client
+var platformCredentials = map[string][]string{
+	"GitHub":  {"GITHUB_TOKEN"},
+	"Twitter": {"TWITTER_BEARER_TOKEN"},
+	"Twitch":  {"TWITCH_CLIENT_ID", "TWITCH_CLIENT_SECRET"},
+}
+
+// healthChecks builds the readiness checks. Platform credentials are
+// validated locally so probes never spend API quota.
+func (c *Container) healthChecks() []appservice.HealthCheck {
+	checks := []appservice.HealthCheck{
+		{Name: "database", Critical: true, Check: c.Database.Ping},
+		{Name: "migrations", Critical: true, Check: c.Database.CheckMigrations},
+		{Name: "image_cache", Critical: false, Check: func(ctx context.Context) error {
+			return c.ImageProcessor.CheckCacheDir()
+		}},
+	}
+
+	platforms := make([]string, 0, len(platformCredentials))
+	for platform := range platformCredentials {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	for _, platform := range platforms {
+		checks = append(checks, appservice.HealthCheck{
+			Name:     "platform:" + platform,
+			Critical: false,
+			Check: func(ctx context.Context) error {
+				return c.checkPlatformCredentials(platform)
+			},
+		})
+	}
+
+	return checks
+}
+
+// checkPlatformCredentials verifies a platform client is configured with usable credentials
+func (c *Container) checkPlatformCredentials(platform string) error {
+	client, ok := c.PlatformClients[platform]
+	if !ok {
+		return fmt.Errorf("client not configured, set %s", strings.Join(platformCredentials[platform], ", "))
+	}
+
+	if checker, ok := client.(api.CredentialChecker); ok {
+		return checker.CheckCredentials()
+	}
 	return nil
 }
 
+// profileServiceConfig builds the profile service configuration from the
+// environment and the container options. PROFILE_TTL_SECONDS_<PLATFORM>
+// overrides the TTL of one platform.
+func profileServiceConfig(options Options) appservice.ProfileServiceConfig {
+	config := appservice.DefaultProfileServiceConfig()
+	config.TTL = time.Duration(envInt("PROFILE_TTL_SECONDS", int(config.TTL/time.Second))) * time.Second
+	config.StaleTTL = time.Duration(envInt("PROFILE_STALE_SECONDS", int(config.StaleTTL/time.Second))) * time.Second
+	config.NotFoundTTL = time.Duration(envInt("PROFILE_NOT_FOUND_TTL_SECONDS", int(config.NotFoundTTL/time.Second))) * time.Second
+	config.NoCache = options.NoCache || envBool("PROFILE_NO_CACHE", config.NoCache)
+	for platform := range platformCredentials {
+		name := "PROFILE_TTL_SECONDS_" + strings.ToUpper(platform)
+		if os.Getenv(name) == "" {
+			continue
+		}
+
+		ttl, ok := config.PlatformTTLs[platform]
+		if !ok {
+			ttl = config.TTL
+		}
+		config.PlatformTTLs[platform] = time.Duration(envInt(name, int(ttl/time.Second))) * time.Second
+	}
+	return config
+}
+
+// scanJobServiceConfig builds the scan job service configuration from the environment
+func scanJobServiceConfig() appservice.ScanJobServiceConfig {
+	config := appservice.DefaultScanJobServiceConfig()
+	config.Workers = envInt("SCAN_WORKERS", config.Workers)
+	config.QueueSize = envInt("SCAN_QUEUE_SIZE", config.QueueSize)
+	config.Scanner.Timeout = envInt("SCAN_TIMEOUT", config.Scanner.Timeout)
+	config.Scanner.Concurrency = envInt("MAX_CONCURRENT_REQUESTS", config.Scanner.Concurrency)
+	return config
+}
+
+// watchlistServiceConfig builds the watchlist service configuration from the environment
+func watchlistServiceConfig() appservice.WatchlistServiceConfig {
+	config := appservice.DefaultWatchlistServiceConfig()
+	config.PollInterval = time.Duration(envInt("WATCHLIST_POLL_SECONDS", int(config.PollInterval/time.Second))) * time.Second
+	config.Scanner.Timeout = envInt("SCAN_TIMEOUT", config.Scanner.Timeout)
+	config.Scanner.Concurrency = envInt("MAX_CONCURRENT_REQUESTS", config.Scanner.Concurrency)
+	return config
+}
+
+// webhookServiceConfig builds the webhook service configuration from the environment
+func webhookServiceConfig() appservice.WebhookServiceConfig {
+	config := appservice.DefaultWebhookServiceConfig()
+	config.Timeout = time.Duration(envInt("WEBHOOK_TIMEOUT", int(config.Timeout/time.Second))) * time.Second
+	config.MaxAttempts = envInt("WEBHOOK_MAX_ATTEMPTS", config.MaxAttempts)
+	config.AllowPrivateTargets = envBool("WEBHOOK_ALLOW_PRIVATE_TARGETS", config.AllowPrivateTargets)
+	return config
+}
+
+// envInt reads an integer environment variable, falling back to a default
+func envInt(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: Invalid value %q for %s, using %d", value, name, fallback)
+		return fallback
+	}
+	return n
+}
+
+// envBool reads a boolean environment variable, falling back to a default
+func envBool(name string, fallback bool) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: Invalid value %q for %s, using %t", value, name, fallback)
+		return fallback
+	}
+	return b
+}
+
 // Close closes the container and releases resources
 func (c *Container) Close() error {
+	if c.WatchlistService != nil {
+		c.WatchlistService.Stop()
+	}
+
+	if c.ScanJobService != nil {
+		c.ScanJobService.Stop()
+	}
+
+	if c.WebhookService != nil {
+		c.WebhookService.Stop()
+	}
+
+	if c.IdentityService != nil {
+		c.IdentityService.Stop()
+	}
+
 	if c.Database != nil {
 		return c.Database.Close()
 	}
@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -20,7 +21,7 @@ type Database struct {
 func NewDatabase() (*Database, error) {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
-		fmt.Printf("Warning: .env file not found, using environment variables\n")
+		fmt.Fprintf(os.Stderr, "Warning: .env file not found, using environment variables\n")
 	}
 
 	dbURL := os.Getenv("TURSO_DATABASE_URL")
@@ -48,16 +49,69 @@ func NewDatabase() (*Database, error) {
 	return &Database{DB: db}, nil
 }
 
-// autoMigrate automatically migrates the schema
-func autoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+// models returns every model managed by the schema migration
+func models() []any {
+	return []any{
 		&model.Profile{},
 		&model.NamePart{},
 		&model.Alias{},
 		&model.PlatformData{},
+		&model.ProfileSnapshot{},
 		&model.SearchHistory{},
 		&model.UserFeedback{},
-	)
+		&model.Person{},
+		&model.APIKey{},
+		&model.APIKeyUsage{},
+		&model.WatchlistEntry{},
+		&model.WatchlistSnapshot{},
+		&model.WatchlistAccount{},
+		&model.WatchlistAlert{},
+		&model.Webhook{},
+		&model.WebhookDelivery{},
+	}
+}
+
+// autoMigrate automatically migrates the schema
+func autoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(models()...)
+}
+
+// Ping verifies the database connection is alive
+func (d *Database) Ping(ctx context.Context) error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// CheckMigrations verifies every table and column of the schema exists
+func (d *Database) CheckMigrations(ctx context.Context) error {
+	db := d.DB.WithContext(ctx)
+	migrator := db.Migrator()
+
+	for _, m := range models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return fmt.Errorf("failed to parse model: %w", err)
+		}
+
+		table := stmt.Schema.Table
+		if !migrator.HasTable(table) {
+			return fmt.Errorf("table %s is missing", table)
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			if !migrator.HasColumn(m, field.DBName) {
+				return fmt.Errorf("column %s.%s is missing", table, field.DBName)
+			}
+		}
+	}
+
+	return nil
 }
 
 // Close closes the database connection
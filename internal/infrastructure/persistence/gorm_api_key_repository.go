@@ -0,0 +1,114 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormAPIKeyRepository is a GORM implementation of APIKeyRepository
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewGormAPIKeyRepository creates a new GormAPIKeyRepository
+func NewGormAPIKeyRepository(db *gorm.DB) repository.APIKeyRepository {
+	return &GormAPIKeyRepository{
+		db: db,
+	}
+}
+
+// Create creates a new API key
+func (r *GormAPIKeyRepository) Create(ctx context.Context, apiKey *model.APIKey) error {
+	return r.db.WithContext(ctx).Create(apiKey).Error
+}
+
+// FindByID finds an API key by ID
+func (r *GormAPIKeyRepository) FindByID(ctx context.Context, id uint) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	err := r.db.WithContext(ctx).First(&apiKey, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+// FindByPrefix finds an API key by its public prefix
+func (r *GormAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	err := r.db.WithContext(ctx).
+		Where("prefix = ?", prefix).
+		First(&apiKey).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+// FindAll finds all API keys
+func (r *GormAPIKeyRepository) FindAll(ctx context.Context) ([]*model.APIKey, error) {
+	var apiKeys []*model.APIKey
+	err := r.db.WithContext(ctx).
+		Order("id ASC").
+		Find(&apiKeys).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+// Revoke marks an API key as revoked
+func (r *GormAPIKeyRepository) Revoke(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&model.APIKey{}).
+		Where("id = ?", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// IncrementUsage increments the usage counter of a key for a day and returns the new count
+func (r *GormAPIKeyRepository) IncrementUsage(ctx context.Context, id uint, day string) (int, error) {
+	usage := model.APIKeyUsage{APIKeyID: id, Day: day, Count: 1}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "api_key_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]any{"count": gorm.Expr("count + 1")}),
+		}).Create(&usage).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("api_key_id = ? AND day = ?", id, day).First(&usage).Error
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return usage.Count, nil
+}
+
+// TouchLastUsed updates the last used time of a key
+func (r *GormAPIKeyRepository) TouchLastUsed(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&model.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+}
@@ -0,0 +1,136 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// profileIDBatchSize is the number of profile IDs bound in one statement,
+// well below SQLite's limit on bound variables
+const profileIDBatchSize = 500
+
+// GormPersonRepository is a GORM implementation of PersonRepository
+type GormPersonRepository struct {
+	db *gorm.DB
+}
+
+// NewGormPersonRepository creates a new GormPersonRepository
+func NewGormPersonRepository(db *gorm.DB) repository.PersonRepository {
+	return &GormPersonRepository{
+		db: db,
+	}
+}
+
+// FindByID finds a person by ID along with its profiles
+func (r *GormPersonRepository) FindByID(ctx context.Context, id uint) (*model.Person, error) {
+	var person model.Person
+	err := r.db.WithContext(ctx).
+		Preload("Profiles", func(db *gorm.DB) *gorm.DB {
+			return db.Order("platform ASC, id ASC")
+		}).
+		Preload("Profiles.PlatformData").
+		First(&person, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &person, nil
+}
+
+// FindAll finds a page of persons along with their profiles, largest first
+func (r *GormPersonRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.Person, error) {
+	var persons []*model.Person
+	query := r.db.WithContext(ctx).
+		Preload("Profiles", func(db *gorm.DB) *gorm.DB {
+			return db.Order("platform ASC, id ASC")
+		}).
+		Order("(SELECT COUNT(*) FROM profiles WHERE profiles.person_id = people.id) DESC, id ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&persons).Error; err != nil {
+		return nil, err
+	}
+
+	return persons, nil
+}
+
+// Count counts all persons
+func (r *GormPersonRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Person{}).Count(&count).Error
+	return count, err
+}
+
+// ReplaceAll replaces every person with the given clusters in one transaction
+func (r *GormPersonRepository) ReplaceAll(ctx context.Context, clusters []repository.PersonCluster) ([]*model.Person, error) {
+	persons := make([]*model.Person, 0, len(clusters))
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Profile{}).
+			Where("person_id IS NOT NULL").
+			Update("person_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		var keep []uint
+		for _, cluster := range clusters {
+			if cluster.PersonID != 0 {
+				keep = append(keep, cluster.PersonID)
+			}
+		}
+		stale := tx.Where("1 = 1")
+		if len(keep) > 0 {
+			stale = tx.Where("id NOT IN ?", keep)
+		}
+		if err := stale.Delete(&model.Person{}).Error; err != nil {
+			return err
+		}
+
+		for _, cluster := range clusters {
+			person := &model.Person{ID: cluster.PersonID, Name: cluster.Name}
+			if person.ID == 0 {
+				err = tx.Create(person).Error
+			} else {
+				err = tx.Model(person).Update("name", person.Name).Error
+			}
+			if err != nil {
+				return err
+			}
+
+			for start := 0; start < len(cluster.ProfileIDs); start += profileIDBatchSize {
+				end := min(start+profileIDBatchSize, len(cluster.ProfileIDs))
+				err := tx.Model(&model.Profile{}).
+					Where("id IN ?", cluster.ProfileIDs[start:end]).
+					Update("person_id", person.ID).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			persons = append(persons, person)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return persons, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/accio/internal/domain/model"
 	"github.com/accio/internal/domain/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormProfileRepository is a GORM implementation of ProfileRepository
@@ -26,9 +27,75 @@ func (r *GormProfileRepository) Create(ctx context.Context, profile *model.Profi
 	return r.db.WithContext(ctx).Create(profile).Error
 }
 
-// Update updates an existing profile
+// Update updates an existing profile, replacing its name parts, aliases and
+// platform data
 func (r *GormProfileRepository) Update(ctx context.Context, profile *model.Profile) error {
-	return r.db.WithContext(ctx).Save(profile).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateProfile(tx, profile)
+	})
+}
+
+// SaveWithSnapshot creates or updates a profile and records the snapshot in
+// one transaction
+func (r *GormProfileRepository) SaveWithSnapshot(ctx context.Context, profile *model.Profile, snapshot *model.ProfileSnapshot) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if profile.ID == 0 {
+			err = tx.Create(profile).Error
+		} else {
+			err = updateProfile(tx, profile)
+		}
+		if err != nil || snapshot == nil {
+			return err
+		}
+
+		snapshot.ProfileID = profile.ID
+		return tx.Create(snapshot).Error
+	})
+}
+
+// updateProfile updates a profile within a transaction, replacing its name
+// parts, aliases and platform data
+func updateProfile(tx *gorm.DB, profile *model.Profile) error {
+	if err := tx.Model(profile).Select("*").Omit(clause.Associations).Updates(profile).Error; err != nil {
+		return err
+	}
+
+	children := []interface{}{&model.NamePart{}, &model.Alias{}, &model.PlatformData{}}
+	for _, child := range children {
+		if err := tx.Where("profile_id = ?", profile.ID).Delete(child).Error; err != nil {
+			return err
+		}
+	}
+
+	for i := range profile.NameParts {
+		profile.NameParts[i].ID = 0
+		profile.NameParts[i].ProfileID = profile.ID
+	}
+	for i := range profile.Aliases {
+		profile.Aliases[i].ID = 0
+		profile.Aliases[i].ProfileID = profile.ID
+	}
+	for i := range profile.PlatformData {
+		profile.PlatformData[i].ID = 0
+		profile.PlatformData[i].ProfileID = profile.ID
+	}
+	if len(profile.NameParts) > 0 {
+		if err := tx.Create(&profile.NameParts).Error; err != nil {
+			return err
+		}
+	}
+	if len(profile.Aliases) > 0 {
+		if err := tx.Create(&profile.Aliases).Error; err != nil {
+			return err
+		}
+	}
+	if len(profile.PlatformData) > 0 {
+		if err := tx.Create(&profile.PlatformData).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // FindByID finds a profile by ID
@@ -70,6 +137,22 @@ func (r *GormProfileRepository) FindByUsername(ctx context.Context, username, pl
 	return &profile, nil
 }
 
+// FindAllByUsername finds the profiles with a username, ignoring case, on every platform
+func (r *GormProfileRepository) FindAllByUsername(ctx context.Context, username string) ([]*model.Profile, error) {
+	var profiles []*model.Profile
+	err := r.db.WithContext(ctx).
+		Preload("PlatformData").
+		Where("LOWER(username) = LOWER(?)", username).
+		Order("platform").
+		Find(&profiles).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
 // FindByRealName finds profiles by real name
 func (r *GormProfileRepository) FindByRealName(ctx context.Context, name string) ([]*model.Profile, error) {
 	var profiles []*model.Profile
@@ -123,13 +206,14 @@ func (r *GormProfileRepository) FindByAlias(ctx context.Context, alias string) (
 	return profiles, nil
 }
 
-// FindAll finds all profiles with optional limit and offset
-func (r *GormProfileRepository) FindAll(ctx context.Context, limit, offset int) ([]*model.Profile, error) {
+// FindAll finds all profiles matching the filter with optional limit and offset
+func (r *GormProfileRepository) FindAll(ctx context.Context, filter repository.ProfileFilter, limit, offset int) ([]*model.Profile, error) {
 	var profiles []*model.Profile
-	query := r.db.WithContext(ctx).
+	query := applyProfileFilter(r.db.WithContext(ctx), filter).
 		Preload("NameParts").
 		Preload("Aliases").
-		Preload("PlatformData")
+		Preload("PlatformData").
+		Order("id ASC")
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -147,14 +231,72 @@ func (r *GormProfileRepository) FindAll(ctx context.Context, limit, offset int)
 	return profiles, nil
 }
 
-// Count counts all profiles
-func (r *GormProfileRepository) Count(ctx context.Context) (int64, error) {
+// Count counts all profiles matching the filter
+func (r *GormProfileRepository) Count(ctx context.Context, filter repository.ProfileFilter) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&model.Profile{}).Count(&count).Error
+	err := applyProfileFilter(r.db.WithContext(ctx).Model(&model.Profile{}), filter).Count(&count).Error
 	return count, err
 }
 
+// applyProfileFilter adds the filter conditions to a profile query
+func applyProfileFilter(query *gorm.DB, filter repository.ProfileFilter) *gorm.DB {
+	if filter.Platform != "" {
+		query = query.Where("platform = ?", filter.Platform)
+	}
+
+	if filter.Verified != nil {
+		query = query.Where("verified = ?", *filter.Verified)
+	}
+
+	return query
+}
+
+// UpdateImageHash stores the average hash of a profile's picture
+func (r *GormProfileRepository) UpdateImageHash(ctx context.Context, id uint, hash string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.Profile{}).
+		Where("id = ?", id).
+		Update("image_hash", hash).Error
+}
+
 // Delete deletes a profile
 func (r *GormProfileRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&model.Profile{}, id).Error
 }
+
+// FindSnapshotByID finds a profile snapshot by ID
+func (r *GormProfileRepository) FindSnapshotByID(ctx context.Context, id uint) (*model.ProfileSnapshot, error) {
+	var snapshot model.ProfileSnapshot
+	if err := r.db.WithContext(ctx).First(&snapshot, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// FindSnapshots finds a page of the snapshots of a profile, latest first
+func (r *GormProfileRepository) FindSnapshots(ctx context.Context, profileID uint, limit, offset int) ([]*model.ProfileSnapshot, error) {
+	var snapshots []*model.ProfileSnapshot
+	err := r.db.WithContext(ctx).
+		Where("profile_id = ?", profileID).
+		Order("captured_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&snapshots).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// CountSnapshots counts the snapshots of a profile
+func (r *GormProfileRepository) CountSnapshots(ctx context.Context, profileID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.ProfileSnapshot{}).Where("profile_id = ?", profileID).Count(&count).Error
+	return count, err
+}
@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"context"
+	"time"
 
 	"github.com/accio/internal/domain/model"
 	"github.com/accio/internal/domain/repository"
@@ -42,19 +43,52 @@ func (r *GormSearchHistoryRepository) FindByQuery(ctx context.Context, query str
 
 // FindPopular finds the most popular searches
 func (r *GormSearchHistoryRepository) FindPopular(ctx context.Context, limit int) ([]*model.SearchHistory, error) {
-	var searchHistories []*model.SearchHistory
+	// Aggregate first; SQLite returns MAX(created_at) as text, so timestamps
+	// are loaded from the latest entry of each query afterwards
+	var rows []struct {
+		ID          uint
+		Query       string
+		SearchCount int64
+		ResultCount int
+	}
 	err := r.db.WithContext(ctx).
 		Model(&model.SearchHistory{}).
-		Select("query, COUNT(*) as count, MAX(created_at) as created_at, MAX(id) as id, SUM(result_count) as result_count").
+		Select("MAX(id) as id, query, COUNT(*) as search_count, SUM(result_count) as result_count").
 		Group("query").
-		Order("count DESC").
+		Order("search_count DESC, id DESC").
 		Limit(limit).
-		Find(&searchHistories).Error
+		Scan(&rows).Error
 
 	if err != nil {
 		return nil, err
 	}
 
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+
+	var latest []*model.SearchHistory
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&latest).Error; err != nil {
+		return nil, err
+	}
+
+	createdAt := make(map[uint]time.Time, len(latest))
+	for _, entry := range latest {
+		createdAt[entry.ID] = entry.CreatedAt
+	}
+
+	searchHistories := make([]*model.SearchHistory, 0, len(rows))
+	for _, row := range rows {
+		searchHistories = append(searchHistories, &model.SearchHistory{
+			ID:          row.ID,
+			Query:       row.Query,
+			ResultCount: row.ResultCount,
+			CreatedAt:   createdAt[row.ID],
+			SearchCount: row.SearchCount,
+		})
+	}
+
 	return searchHistories, nil
 }
 
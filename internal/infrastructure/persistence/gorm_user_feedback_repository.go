@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"context"
+	"errors"
 
 	"github.com/accio/internal/domain/model"
 	"github.com/accio/internal/domain/repository"
@@ -25,6 +26,28 @@ func (r *GormUserFeedbackRepository) Create(ctx context.Context, userFeedback *m
 	return r.db.WithContext(ctx).Create(userFeedback).Error
 }
 
+// Update updates a user feedback entry
+func (r *GormUserFeedbackRepository) Update(ctx context.Context, userFeedback *model.UserFeedback) error {
+	return r.db.WithContext(ctx).Model(userFeedback).Select("*").Updates(userFeedback).Error
+}
+
+// FindByProfileAndAPIKey finds the feedback an API key gave on a profile
+func (r *GormUserFeedbackRepository) FindByProfileAndAPIKey(ctx context.Context, profileID, apiKeyID uint) (*model.UserFeedback, error) {
+	var userFeedback model.UserFeedback
+	err := r.db.WithContext(ctx).
+		Where("profile_id = ? AND api_key_id = ?", profileID, apiKeyID).
+		First(&userFeedback).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &userFeedback, nil
+}
+
 // FindByProfileID finds user feedback entries by profile ID
 func (r *GormUserFeedbackRepository) FindByProfileID(ctx context.Context, profileID uint) ([]*model.UserFeedback, error) {
 	var userFeedbacks []*model.UserFeedback
@@ -55,9 +78,54 @@ func (r *GormUserFeedbackRepository) FindByFeedbackType(ctx context.Context, fee
 	return userFeedbacks, nil
 }
 
-// Count counts all user feedback entries
-func (r *GormUserFeedbackRepository) Count(ctx context.Context) (int64, error) {
+// FindAll finds a page of user feedback entries, newest first
+func (r *GormUserFeedbackRepository) FindAll(ctx context.Context, feedbackType string, limit, offset int) ([]*model.UserFeedback, error) {
+	var userFeedbacks []*model.UserFeedback
+	err := applyFeedbackType(r.db.WithContext(ctx), feedbackType).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&userFeedbacks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return userFeedbacks, nil
+}
+
+// Count counts user feedback entries of a type, or all entries if the type is empty
+func (r *GormUserFeedbackRepository) Count(ctx context.Context, feedbackType string) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&model.UserFeedback{}).Count(&count).Error
+	err := applyFeedbackType(r.db.WithContext(ctx).Model(&model.UserFeedback{}), feedbackType).Count(&count).Error
 	return count, err
 }
+
+// CountByProfileIDs counts the feedback entries of each type for the given profiles
+func (r *GormUserFeedbackRepository) CountByProfileIDs(ctx context.Context, profileIDs []uint) ([]repository.FeedbackCount, error) {
+	if len(profileIDs) == 0 {
+		return nil, nil
+	}
+
+	var counts []repository.FeedbackCount
+	err := r.db.WithContext(ctx).
+		Model(&model.UserFeedback{}).
+		Select("profile_id, feedback_type, COUNT(*) AS count").
+		Where("profile_id IN ?", profileIDs).
+		Group("profile_id, feedback_type").
+		Scan(&counts).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// applyFeedbackType restricts a query to a feedback type, if one is given
+func applyFeedbackType(db *gorm.DB, feedbackType string) *gorm.DB {
+	if feedbackType != "" {
+		db = db.Where("feedback_type = ?", feedbackType)
+	}
+	return db
+}
@@ -0,0 +1,219 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// GormWatchlistRepository is a GORM implementation of WatchlistRepository
+type GormWatchlistRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWatchlistRepository creates a new GormWatchlistRepository
+func NewGormWatchlistRepository(db *gorm.DB) repository.WatchlistRepository {
+	return &GormWatchlistRepository{
+		db: db,
+	}
+}
+
+// CreateEntry creates a new watchlist entry
+func (r *GormWatchlistRepository) CreateEntry(ctx context.Context, entry *model.WatchlistEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// UpdateEntry updates an existing watchlist entry. Unlike Save it does not
+// recreate an entry deleted in the meantime.
+func (r *GormWatchlistRepository) UpdateEntry(ctx context.Context, entry *model.WatchlistEntry) error {
+	return r.db.WithContext(ctx).Model(entry).Select("*").Updates(entry).Error
+}
+
+// FindEntryByID finds a watchlist entry by ID
+func (r *GormWatchlistRepository) FindEntryByID(ctx context.Context, id uint) (*model.WatchlistEntry, error) {
+	var entry model.WatchlistEntry
+	if err := r.db.WithContext(ctx).First(&entry, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// FindEntryByUsername finds the watchlist entry of a username
+func (r *GormWatchlistRepository) FindEntryByUsername(ctx context.Context, username string) (*model.WatchlistEntry, error) {
+	var entry model.WatchlistEntry
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// FindEntries finds a page of watchlist entries in the order they were created
+func (r *GormWatchlistRepository) FindEntries(ctx context.Context, limit, offset int) ([]*model.WatchlistEntry, error) {
+	var entries []*model.WatchlistEntry
+	err := r.db.WithContext(ctx).
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CountEntries counts all watchlist entries
+func (r *GormWatchlistRepository) CountEntries(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.WatchlistEntry{}).Count(&count).Error
+	return count, err
+}
+
+// FindDueEntries finds up to limit entries due at now, longest overdue first
+func (r *GormWatchlistRepository) FindDueEntries(ctx context.Context, now time.Time, limit int) ([]*model.WatchlistEntry, error) {
+	var entries []*model.WatchlistEntry
+	err := r.db.WithContext(ctx).
+		Where("next_run_at <= ?", now).
+		Order("next_run_at ASC, id ASC").
+		Limit(limit).
+		Find(&entries).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// DeleteEntry deletes a watchlist entry along with its snapshots and alerts
+func (r *GormWatchlistRepository) DeleteEntry(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		snapshots := tx.Model(&model.WatchlistSnapshot{}).Select("id").Where("entry_id = ?", id)
+		if err := tx.Where("snapshot_id IN (?)", snapshots).Delete(&model.WatchlistAccount{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entry_id = ?", id).Delete(&model.WatchlistSnapshot{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entry_id = ?", id).Delete(&model.WatchlistAlert{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.WatchlistEntry{}, id).Error
+	})
+}
+
+// CreateSnapshot creates a snapshot with its accounts and alerts in one
+// transaction, skipping it if the entry was deleted
+func (r *GormWatchlistRepository) CreateSnapshot(ctx context.Context, snapshot *model.WatchlistSnapshot, alerts []*model.WatchlistAlert) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.WatchlistEntry{}).Where("id = ?", snapshot.EntryID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+		if len(alerts) == 0 {
+			return nil
+		}
+
+		for _, alert := range alerts {
+			alert.SnapshotID = snapshot.ID
+		}
+		return tx.Create(alerts).Error
+	})
+}
+
+// FindLatestSnapshot finds the latest snapshot of an entry along with its accounts
+func (r *GormWatchlistRepository) FindLatestSnapshot(ctx context.Context, entryID uint) (*model.WatchlistSnapshot, error) {
+	var snapshot model.WatchlistSnapshot
+	err := r.db.WithContext(ctx).
+		Preload("Accounts").
+		Where("entry_id = ?", entryID).
+		Order("id DESC").
+		First(&snapshot).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// FindSnapshots finds a page of the snapshots of an entry, latest first
+func (r *GormWatchlistRepository) FindSnapshots(ctx context.Context, entryID uint, limit, offset int) ([]*model.WatchlistSnapshot, error) {
+	var snapshots []*model.WatchlistSnapshot
+	err := r.db.WithContext(ctx).
+		Preload("Accounts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("site ASC")
+		}).
+		Where("entry_id = ?", entryID).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&snapshots).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// CountSnapshots counts the snapshots of an entry
+func (r *GormWatchlistRepository) CountSnapshots(ctx context.Context, entryID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.WatchlistSnapshot{}).Where("entry_id = ?", entryID).Count(&count).Error
+	return count, err
+}
+
+// FindAlerts finds a page of alerts of one entry or of every entry, latest first
+func (r *GormWatchlistRepository) FindAlerts(ctx context.Context, entryID uint, limit, offset int) ([]*model.WatchlistAlert, error) {
+	var alerts []*model.WatchlistAlert
+	err := applyAlertEntry(r.db.WithContext(ctx), entryID).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&alerts).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// CountAlerts counts the alerts of one entry or of every entry
+func (r *GormWatchlistRepository) CountAlerts(ctx context.Context, entryID uint) (int64, error) {
+	var count int64
+	err := applyAlertEntry(r.db.WithContext(ctx).Model(&model.WatchlistAlert{}), entryID).Count(&count).Error
+	return count, err
+}
+
+// applyAlertEntry restricts a query to the alerts of an entry unless entryID is 0
+func applyAlertEntry(db *gorm.DB, entryID uint) *gorm.DB {
+	if entryID == 0 {
+		return db
+	}
+	return db.Where("entry_id = ?", entryID)
+}
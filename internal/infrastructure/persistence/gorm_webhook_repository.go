@@ -0,0 +1,151 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// GormWebhookRepository is a GORM implementation of WebhookRepository
+type GormWebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWebhookRepository creates a new GormWebhookRepository
+func NewGormWebhookRepository(db *gorm.DB) repository.WebhookRepository {
+	return &GormWebhookRepository{
+		db: db,
+	}
+}
+
+// CreateWebhook creates a new webhook
+func (r *GormWebhookRepository) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	return r.db.WithContext(ctx).Create(webhook).Error
+}
+
+// FindWebhookByID finds a webhook by ID
+func (r *GormWebhookRepository) FindWebhookByID(ctx context.Context, id uint) (*model.Webhook, error) {
+	var webhook model.Webhook
+	if err := r.db.WithContext(ctx).First(&webhook, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// FindWebhooks finds a page of webhooks in the order they were created
+func (r *GormWebhookRepository) FindWebhooks(ctx context.Context, limit, offset int) ([]*model.Webhook, error) {
+	var webhooks []*model.Webhook
+	err := r.db.WithContext(ctx).
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&webhooks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// FindAllWebhooks finds every webhook
+func (r *GormWebhookRepository) FindAllWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	var webhooks []*model.Webhook
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// CountWebhooks counts all webhooks
+func (r *GormWebhookRepository) CountWebhooks(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Webhook{}).Count(&count).Error
+	return count, err
+}
+
+// DeleteWebhook deletes a webhook along with its deliveries
+func (r *GormWebhookRepository) DeleteWebhook(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Webhook{}, id).Error
+	})
+}
+
+// CreateDeliveries creates deliveries in one transaction
+func (r *GormWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(deliveries).Error
+}
+
+// UpdateDelivery updates an existing delivery. Unlike Save it does not
+// recreate a delivery whose webhook was deleted in the meantime.
+func (r *GormWebhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(delivery).Select("*").Updates(delivery).Error
+}
+
+// FindDeliveryByID finds a delivery by ID
+func (r *GormWebhookRepository) FindDeliveryByID(ctx context.Context, id uint) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&delivery, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// FindDeliveries finds a page of the deliveries of a webhook, latest first
+func (r *GormWebhookRepository) FindDeliveries(ctx context.Context, webhookID uint, limit, offset int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// CountDeliveries counts the deliveries of a webhook
+func (r *GormWebhookRepository) CountDeliveries(ctx context.Context, webhookID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookID).Count(&count).Error
+	return count, err
+}
+
+// FindDueDeliveries finds up to limit pending deliveries due at now, oldest first
+func (r *GormWebhookRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", model.DeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
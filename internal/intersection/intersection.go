@@ -1,19 +1,38 @@
 package intersection
 
 import (
+	"math"
 	"sort"
 	"strings"
 
+	"github.com/accio/internal/image"
 	"github.com/accio/internal/output"
 )
 
+// unrelatedHashDistance is the number of bits in which the hashes of
+// unrelated pictures differ on average: half of them
+const unrelatedHashDistance = 32
+
 // ProfileMatch represents a username match across platforms
 type ProfileMatch struct {
-	Username    string          // The username that was matched
-	Results     []output.Result // The results for this username across platforms
-	MatchCount  int             // Number of platforms where this username was found
-	Confidence  float64         // Confidence score (0.0-1.0) that these profiles belong to the same person
-	NameMatches map[string]bool // Map of platforms where the real name matches
+	Username         string            `json:"username"`                    // The username that was matched
+	Results          []output.Result   `json:"results"`                     // The results for this username across platforms
+	MatchCount       int               `json:"match_count"`                 // Number of platforms where this username was found
+	Confidence       float64           `json:"confidence"`                  // Confidence score (0.0-1.0) that these profiles belong to the same person
+	Evidence         []output.Evidence `json:"evidence"`                    // Signals behind the confidence score
+	NameMatches      map[string]bool   `json:"name_matches,omitempty"`      // Map of platforms where the real name matches
+	AvatarSimilarity float64           `json:"avatar_similarity,omitempty"` // Similarity (0.0-1.0) of the profile pictures, if they were compared
+	CrossLinks       int               `json:"cross_links,omitempty"`       // Number of found profiles linking to or linked from another found profile
+	Links            []output.Link     `json:"links,omitempty"`             // Links between these and other found profiles
+}
+
+// Analysis returns the confidence of the match and the evidence behind it
+func (m ProfileMatch) Analysis() *output.Analysis {
+	return &output.Analysis{
+		Confidence: m.Confidence,
+		Evidence:   m.Evidence,
+		Links:      m.Links,
+	}
 }
 
 // AnalysisResult contains the results of an intersection analysis
@@ -24,13 +43,35 @@ type AnalysisResult struct {
 	UniqueProfiles int            // Number of likely unique profiles
 }
 
-// AnalyzeResults performs intersection analysis on results from multiple username checks
+// AnalyzeResults performs intersection analysis on results from multiple
+// username checks, scoring matches with the weights in WeightsFile or the
+// default weights if no model has been trained
 func AnalyzeResults(allResults map[string][]output.Result) AnalysisResult {
+	return AnalyzeResultsWithWeights(allResults, CurrentWeights())
+}
+
+// AnalyzeLinkedResults performs intersection analysis like AnalyzeResults,
+// counting the links between found profiles as cross-links
+func AnalyzeLinkedResults(allResults map[string][]output.Result, links []output.Link) AnalysisResult {
+	return analyze(allResults, links, CurrentWeights())
+}
+
+// AnalyzeResultsWithWeights performs intersection analysis, scoring matches
+// with the given weights
+func AnalyzeResultsWithWeights(allResults map[string][]output.Result, weights *Weights) AnalysisResult {
+	return analyze(allResults, nil, weights)
+}
+
+// analyze groups results by username, links and scores the matches
+func analyze(allResults map[string][]output.Result, links []output.Link, weights *Weights) AnalysisResult {
 	// Group results by username
 	matches := groupResultsByUsername(allResults)
 
+	// Record links between found profiles
+	LinkMatches(matches, links)
+
 	// Calculate confidence scores
-	calculateConfidenceScores(matches)
+	calculateConfidenceScores(matches, weights)
 
 	// Sort matches by confidence score (descending)
 	sort.Slice(matches, func(i, j int) bool {
@@ -88,47 +129,76 @@ func groupResultsByUsername(allResults map[string][]output.Result) []ProfileMatc
 	return matches
 }
 
-// calculateConfidenceScores calculates confidence scores for each match
-func calculateConfidenceScores(matches []ProfileMatch) {
-	// Base confidence on number of matches
-	// More matches = higher confidence
+// LinkMatches records on each match the links from or to its found profiles
+// whose other end is also a found profile of one of the matches, and counts
+// the profiles of the match with such links as its cross-links
+func LinkMatches(matches []ProfileMatch, links []output.Link) {
+	found := make(map[string]bool)
+	for _, match := range matches {
+		for _, result := range match.Results {
+			found[profileKey(result.Site, match.Username)] = true
+		}
+	}
+
 	for i := range matches {
-		// Start with a base confidence based on match count
-		// 1 match = 0.1, 2 matches = 0.3, 3 matches = 0.5, 4+ matches = 0.7+
-		switch matches[i].MatchCount {
-		case 1:
-			matches[i].Confidence = 0.1
-		case 2:
-			matches[i].Confidence = 0.3
-		case 3:
-			matches[i].Confidence = 0.5
-		default:
-			// For 4+ matches, scale up to 0.9 max
-			matches[i].Confidence = 0.7 + float64(matches[i].MatchCount-4)*0.05
-			if matches[i].Confidence > 0.9 {
-				matches[i].Confidence = 0.9
+		match := &matches[i]
+		match.Links = nil
+
+		linked := make(map[string]bool)
+		for _, link := range links {
+			from := profileKey(link.FromSite, link.FromUsername)
+			to := profileKey(link.ToSite, link.ToUsername)
+			if from == to || !found[from] || !found[to] {
+				continue
 			}
-		}
 
-		// Adjust confidence based on platform importance
-		// Some platforms are more likely to be unique identifiers
-		highValuePlatforms := map[string]float64{
-			"GitHub":    0.1,
-			"Twitter":   0.1,
-			"LinkedIn":  0.15,
-			"Facebook":  0.1,
-			"Instagram": 0.1,
-		}
+			outgoing := strings.EqualFold(link.FromUsername, match.Username)
+			incoming := strings.EqualFold(link.ToUsername, match.Username)
+			if !outgoing && !incoming {
+				continue
+			}
 
-		for _, result := range matches[i].Results {
-			if bonus, ok := highValuePlatforms[result.Site]; ok {
-				// Add bonus, but don't exceed 1.0
-				matches[i].Confidence += bonus
-				if matches[i].Confidence > 1.0 {
-					matches[i].Confidence = 1.0
-				}
+			match.Links = append(match.Links, link)
+			if outgoing {
+				linked[from] = true
+			}
+			if incoming {
+				linked[to] = true
 			}
 		}
+		match.CrossLinks = len(linked)
+	}
+}
+
+// AvatarSimilarity returns the average similarity of every pair of profile
+// picture hashes, from 1 for copies of a picture down to 0 for pictures as
+// different as unrelated ones, or 0 if fewer than two pictures were hashed
+func AvatarSimilarity(hashes []uint64) float64 {
+	var total float64
+	pairs := 0
+	for i := range hashes {
+		for j := i + 1; j < len(hashes); j++ {
+			distance := float64(image.HashDistance(hashes[i], hashes[j]))
+			total += math.Max(1-distance/unrelatedHashDistance, 0)
+			pairs++
+		}
+	}
+
+	if pairs == 0 {
+		return 0
+	}
+	return total / float64(pairs)
+}
+
+// profileKey identifies the profile of a username on a site
+func profileKey(site, username string) string {
+	return strings.ToLower(site) + "/" + strings.ToLower(username)
+}
+
+// calculateConfidenceScores scores each match with the confidence model
+func calculateConfidenceScores(matches []ProfileMatch, weights *Weights) {
+	for i := range matches {
+		weights.Apply(&matches[i])
 	}
 }
 
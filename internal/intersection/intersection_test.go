@@ -149,3 +149,72 @@ func TestFindProfilesByPlatforms(t *testing.T) {
 		t.Errorf("Expected 0 profiles with LinkedIn filter, got %d", len(filteredProfiles))
 	}
 }
+
+func TestAnalyzeLinkedResults(t *testing.T) {
+	allResults := map[string][]output.Result{
+		"johndoe": {
+			{Site: "GitHub", URL: "https://github.com/johndoe", Exists: true},
+			{Site: "Twitter", URL: "https://twitter.com/johndoe", Exists: true},
+		},
+		"jdoe": {
+			{Site: "Twitter", URL: "https://twitter.com/jdoe", Exists: true},
+			{Site: "Reddit", URL: "https://www.reddit.com/user/jdoe", Exists: false},
+		},
+	}
+	links := []output.Link{
+		{Type: output.LinksTo, FromSite: "GitHub", FromUsername: "johndoe", ToSite: "Twitter", ToUsername: "JohnDoe", Source: "platform_data"},
+		{Type: output.LinksTo, FromSite: "GitHub", FromUsername: "johndoe", ToSite: "Twitter", ToUsername: "jdoe", Source: "page"},
+		// Links to profiles that were not found are not evidence
+		{Type: output.LinksTo, FromSite: "Twitter", FromUsername: "johndoe", ToSite: "Reddit", ToUsername: "jdoe", Source: "bio"},
+	}
+
+	unlinked := AnalyzeResults(allResults)
+	linked := AnalyzeLinkedResults(allResults, links)
+
+	byUsername := func(result AnalysisResult) map[string]ProfileMatch {
+		matches := make(map[string]ProfileMatch)
+		for _, match := range result.Matches {
+			matches[match.Username] = match
+		}
+		return matches
+	}
+	before, after := byUsername(unlinked), byUsername(linked)
+
+	// johndoe's GitHub links to its Twitter and to jdoe's Twitter
+	if got := after["johndoe"].CrossLinks; got != 2 {
+		t.Errorf("Expected 2 cross-links for johndoe, got %d", got)
+	}
+	if got := len(after["johndoe"].Links); got != 2 {
+		t.Errorf("Expected 2 links for johndoe, got %d", got)
+	}
+	if got := after["jdoe"].CrossLinks; got != 1 {
+		t.Errorf("Expected 1 cross-link for jdoe, got %d", got)
+	}
+
+	for username, match := range after {
+		if match.Confidence <= before[username].Confidence {
+			t.Errorf("Expected links to raise the confidence for %s, got %.2f before and %.2f after", username, before[username].Confidence, match.Confidence)
+		}
+	}
+}
+
+func TestAvatarSimilarity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		hashes   []uint64
+		expected float64
+	}{
+		{"no pictures", nil, 0},
+		{"one picture", []uint64{0xff}, 0},
+		{"copies", []uint64{0xff00ff00, 0xff00ff00}, 1},
+		{"recompressed copy", []uint64{0xff, 0xf7}, 1 - 1.0/32},
+		{"unrelated pictures", []uint64{0, 0xffffffffffffffff}, 0},
+		{"average of pairs", []uint64{0, 0, 0xffffffffffffffff}, 1.0 / 3},
+	}
+
+	for _, tc := range testCases {
+		if got := AvatarSimilarity(tc.hashes); got != tc.expected {
+			t.Errorf("%s: expected %f, got %f", tc.name, tc.expected, got)
+		}
+	}
+}
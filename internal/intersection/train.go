@@ -0,0 +1,147 @@
+package intersection
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// Default training settings
+const (
+	DefaultEpochs       = 2000
+	DefaultLearningRate = 0.5
+	DefaultL2           = 0.01
+)
+
+// ErrNotEnoughSamples is returned when the training data does not contain
+// both correct and incorrect matches
+var ErrNotEnoughSamples = errors.New("training needs at least one correct and one incorrect match")
+
+// Sample is a match labelled by an analyst
+type Sample struct {
+	Match   ProfileMatch
+	Correct bool
+}
+
+// TrainOptions configures Train
+type TrainOptions struct {
+	Epochs       int     // Number of passes of gradient descent over the samples
+	LearningRate float64 // Step size of gradient descent
+	L2           float64 // Strength of the L2 penalty on all weights but the bias
+}
+
+// Train fits the weights of the logistic confidence model to labelled samples
+// with batch gradient descent. Training is deterministic: the same samples
+// and options always produce the same weights.
+func Train(samples []Sample, options TrainOptions) (*Weights, error) {
+	if options.Epochs <= 0 {
+		options.Epochs = DefaultEpochs
+	}
+	if options.LearningRate <= 0 {
+		options.LearningRate = DefaultLearningRate
+	}
+	if options.L2 <= 0 {
+		options.L2 = DefaultL2
+	}
+
+	stats := TrainingStats{Samples: len(samples)}
+	for _, sample := range samples {
+		if sample.Correct {
+			stats.Correct++
+		} else {
+			stats.Incorrect++
+		}
+	}
+	if stats.Correct == 0 || stats.Incorrect == 0 {
+		return nil, ErrNotEnoughSamples
+	}
+
+	inputs := make([]features, len(samples))
+	platformSet := make(map[string]bool)
+	for i, sample := range samples {
+		inputs[i] = extractFeatures(sample.Match)
+		for _, platform := range inputs[i].platforms {
+			platformSet[platform] = true
+		}
+	}
+
+	// Iterate over platforms in a fixed order so floating point sums, and
+	// therefore the weights, do not depend on map iteration order
+	platforms := make([]string, 0, len(platformSet))
+	for platform := range platformSet {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	weights := &Weights{
+		Version:   WeightsVersion,
+		Platforms: make(map[string]float64, len(platforms)),
+	}
+	for _, platform := range platforms {
+		weights.Platforms[platform] = 0
+	}
+
+	n := float64(len(samples))
+	platformGrad := make(map[string]float64, len(platforms))
+	for epoch := 0; epoch < options.Epochs; epoch++ {
+		var biasGrad, countGrad, nameGrad, avatarGrad, crossLinkGrad float64
+		for _, platform := range platforms {
+			platformGrad[platform] = 0
+		}
+
+		for i, f := range inputs {
+			err := sigmoid(weights.logit(f)) - label(samples[i].Correct)
+			biasGrad += err
+			countGrad += err * f.logMatchCount
+			nameGrad += err * f.nameMatchRatio
+			avatarGrad += err * f.avatarSimilarity
+			crossLinkGrad += err * f.logCrossLinks
+			for _, platform := range f.platforms {
+				platformGrad[platform] += err
+			}
+		}
+
+		rate := options.LearningRate
+		weights.Bias -= rate * biasGrad / n
+		weights.LogMatchCount -= rate * (countGrad/n + options.L2*weights.LogMatchCount)
+		weights.NameMatchRatio -= rate * (nameGrad/n + options.L2*weights.NameMatchRatio)
+		weights.AvatarSimilarity -= rate * (avatarGrad/n + options.L2*weights.AvatarSimilarity)
+		weights.LogCrossLinks -= rate * (crossLinkGrad/n + options.L2*weights.LogCrossLinks)
+		for _, platform := range platforms {
+			weights.Platforms[platform] -= rate * (platformGrad[platform]/n + options.L2*weights.Platforms[platform])
+		}
+	}
+
+	var correct int
+	var logLoss float64
+	for i, f := range inputs {
+		p := sigmoid(weights.logit(f))
+		if (p >= 0.5) == samples[i].Correct {
+			correct++
+		}
+		// Clamp to keep the loss finite for confidently wrong predictions
+		p = math.Min(math.Max(p, 1e-15), 1-1e-15)
+		if samples[i].Correct {
+			logLoss -= math.Log(p)
+		} else {
+			logLoss -= math.Log(1 - p)
+		}
+	}
+	stats.Accuracy = float64(correct) / n
+	stats.LogLoss = logLoss / n
+
+	trainedAt := time.Now().UTC()
+	weights.TrainedAt = &trainedAt
+	weights.Training = &stats
+
+	return weights, nil
+}
+
+// label converts a sample label to the model's target value
+func label(correct bool) float64 {
+	if correct {
+		return 1
+	}
+	return 0
+}
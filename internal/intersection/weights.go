@@ -0,0 +1,325 @@
+package intersection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/accio/internal/output"
+)
+
+// Evidence signals
+const (
+	SignalBaseline   = "baseline"
+	SignalMatchCount = "match_count"
+	SignalPlatform   = "platform"
+	SignalNameMatch  = "name_match"
+	SignalAvatar     = "avatar_similarity"
+	SignalCrossLink  = "cross_link"
+)
+
+// WeightsVersion is the version of the weights file format
+const WeightsVersion = 1
+
+// DefaultWeightsFile is where weights are read from and written to when
+// CONFIDENCE_WEIGHTS_FILE is not set
+const DefaultWeightsFile = "./confidence_weights.json"
+
+// Weights are the coefficients of the logistic model that turns the features
+// of a ProfileMatch into a confidence score
+type Weights struct {
+	Version          int                `json:"version"`
+	Bias             float64            `json:"bias"`
+	LogMatchCount    float64            `json:"log_match_count"`
+	NameMatchRatio   float64            `json:"name_match_ratio"`
+	AvatarSimilarity float64            `json:"avatar_similarity"`
+	LogCrossLinks    float64            `json:"log_cross_links"`
+	Platforms        map[string]float64 `json:"platforms"`
+	TrainedAt        *time.Time         `json:"trained_at,omitempty"`
+	Training         *TrainingStats     `json:"training,omitempty"`
+}
+
+// TrainingStats describes the data a set of weights was trained on and how
+// well it fits that data
+type TrainingStats struct {
+	Samples   int     `json:"samples"`
+	Correct   int     `json:"correct"`
+	Incorrect int     `json:"incorrect"`
+	Accuracy  float64 `json:"accuracy"`
+	LogLoss   float64 `json:"log_loss"`
+}
+
+// DefaultWeights returns the weights used until a model has been trained.
+// They give roughly 0.1 for a single match, 0.5 for three and 0.7 for four,
+// with a bonus for platforms where usernames tend to identify a person.
+func DefaultWeights() *Weights {
+	return &Weights{
+		Version:       WeightsVersion,
+		Bias:          -4.5,
+		LogMatchCount: 3.3,
+		// Matching real names and profile pictures are strong signals
+		// wherever they are available
+		NameMatchRatio:   2.5,
+		AvatarSimilarity: 2.0,
+		// A profile explicitly linking to another is close to proof
+		LogCrossLinks: 4.0,
+		Platforms: map[string]float64{
+			"GitHub":    0.5,
+			"Twitter":   0.5,
+			"LinkedIn":  0.7,
+			"Facebook":  0.5,
+			"Instagram": 0.5,
+		},
+	}
+}
+
+// WeightsFile returns the path of the weights file
+func WeightsFile() string {
+	if path := os.Getenv("CONFIDENCE_WEIGHTS_FILE"); path != "" {
+		return path
+	}
+	return DefaultWeightsFile
+}
+
+// LoadWeights reads weights from a file
+func LoadWeights(path string) (*Weights, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var weights Weights
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return nil, fmt.Errorf("invalid weights file %s: %w", path, err)
+	}
+	if weights.Version != WeightsVersion {
+		return nil, fmt.Errorf("weights file %s has version %d, expected %d", path, weights.Version, WeightsVersion)
+	}
+	if weights.Platforms == nil {
+		weights.Platforms = make(map[string]float64)
+	}
+
+	return &weights, nil
+}
+
+// weightsCache holds the weights last loaded by CurrentWeights along with the
+// file they were loaded from
+var weightsCache struct {
+	sync.Mutex
+	path    string
+	exists  bool
+	modTime time.Time
+	size    int64
+	weights *Weights
+}
+
+// CurrentWeights returns the weights in the weights file, falling back to the
+// default weights if there is none or it cannot be read. The file is only
+// read again once its path, modification time or size changes. The returned
+// weights are shared and must not be modified.
+func CurrentWeights() *Weights {
+	path := WeightsFile()
+	info, statErr := os.Stat(path)
+	exists := statErr == nil
+
+	weightsCache.Lock()
+	defer weightsCache.Unlock()
+
+	cache := &weightsCache
+	if cache.weights != nil && cache.path == path && cache.exists == exists &&
+		(!exists || (info.ModTime().Equal(cache.modTime) && info.Size() == cache.size)) {
+		return cache.weights
+	}
+
+	weights, err := LoadWeights(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Warning: using default confidence weights: %v", err)
+		}
+		weights = DefaultWeights()
+	}
+
+	cache.path, cache.exists, cache.weights = path, exists, weights
+	if exists {
+		cache.modTime, cache.size = info.ModTime(), info.Size()
+	}
+	return weights
+}
+
+// Save writes the weights to a file, replacing it atomically
+func (w *Weights) Save(path string) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".confidence_weights-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Score returns the probability that the profiles of a match belong to the
+// same person
+func (w *Weights) Score(match ProfileMatch) float64 {
+	return sigmoid(w.logit(extractFeatures(match)))
+}
+
+// Apply sets the confidence and evidence of a match
+func (w *Weights) Apply(match *ProfileMatch) {
+	match.Evidence = w.Explain(*match)
+
+	var z float64
+	for _, evidence := range match.Evidence {
+		z += evidence.Weight
+	}
+	match.Confidence = sigmoid(z)
+}
+
+// Explain breaks the score of a match down into the contribution of each
+// signal. The weights of the evidence add up to the logit of the score.
+func (w *Weights) Explain(match ProfileMatch) []output.Evidence {
+	f := extractFeatures(match)
+
+	evidence := []output.Evidence{{
+		Signal: SignalBaseline,
+		Weight: w.Bias,
+		Reason: "Baseline before any evidence",
+	}}
+
+	if contribution := w.LogMatchCount * f.logMatchCount; contribution != 0 {
+		evidence = append(evidence, output.Evidence{
+			Signal: SignalMatchCount,
+			Weight: contribution,
+			Reason: fmt.Sprintf("Username found on %d %s", match.MatchCount, plural(match.MatchCount, "site", "sites")),
+		})
+	}
+
+	var platforms []output.Evidence
+	for _, platform := range f.platforms {
+		if contribution := w.Platforms[platform]; contribution != 0 {
+			platforms = append(platforms, output.Evidence{
+				Signal: SignalPlatform,
+				Weight: contribution,
+				Reason: fmt.Sprintf("Profile on %s", platform),
+			})
+		}
+	}
+	sort.SliceStable(platforms, func(i, j int) bool {
+		return platforms[i].Weight > platforms[j].Weight
+	})
+	evidence = append(evidence, platforms...)
+
+	if contribution := w.NameMatchRatio * f.nameMatchRatio; contribution != 0 {
+		evidence = append(evidence, output.Evidence{
+			Signal: SignalNameMatch,
+			Weight: contribution,
+			Reason: fmt.Sprintf("Real name matches on %d of %d sites", f.nameMatches, match.MatchCount),
+		})
+	}
+
+	if contribution := w.AvatarSimilarity * f.avatarSimilarity; contribution != 0 {
+		evidence = append(evidence, output.Evidence{
+			Signal: SignalAvatar,
+			Weight: contribution,
+			Reason: fmt.Sprintf("Profile pictures are %.0f%% similar", f.avatarSimilarity*100),
+		})
+	}
+
+	if contribution := w.LogCrossLinks * f.logCrossLinks; contribution != 0 {
+		evidence = append(evidence, output.Evidence{
+			Signal: SignalCrossLink,
+			Weight: contribution,
+			Reason: fmt.Sprintf("%d %s linked with another found profile", match.CrossLinks, plural(match.CrossLinks, "profile", "profiles")),
+		})
+	}
+
+	return evidence
+}
+
+// logit returns the linear combination of the weights and features
+func (w *Weights) logit(f features) float64 {
+	z := w.Bias +
+		w.LogMatchCount*f.logMatchCount +
+		w.NameMatchRatio*f.nameMatchRatio +
+		w.AvatarSimilarity*f.avatarSimilarity +
+		w.LogCrossLinks*f.logCrossLinks
+	for _, platform := range f.platforms {
+		z += w.Platforms[platform]
+	}
+	return z
+}
+
+// features are the model inputs extracted from a ProfileMatch
+type features struct {
+	logMatchCount    float64
+	nameMatches      int
+	nameMatchRatio   float64
+	avatarSimilarity float64
+	logCrossLinks    float64
+	platforms        []string
+}
+
+// extractFeatures extracts the model inputs from a match
+func extractFeatures(match ProfileMatch) features {
+	f := features{
+		logMatchCount:    math.Log1p(float64(match.MatchCount)),
+		avatarSimilarity: math.Min(math.Max(match.AvatarSimilarity, 0), 1),
+		logCrossLinks:    math.Log1p(float64(max(match.CrossLinks, 0))),
+	}
+
+	seen := make(map[string]bool)
+	for _, result := range match.Results {
+		if !seen[result.Site] {
+			seen[result.Site] = true
+			f.platforms = append(f.platforms, result.Site)
+		}
+	}
+
+	for _, matched := range match.NameMatches {
+		if matched {
+			f.nameMatches++
+		}
+	}
+	if match.MatchCount > 0 {
+		f.nameMatchRatio = math.Min(float64(f.nameMatches)/float64(match.MatchCount), 1)
+	}
+
+	return f
+}
+
+// plural returns singular if n is one and plural otherwise
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// sigmoid maps a logit to a probability
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
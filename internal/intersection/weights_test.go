@@ -0,0 +1,188 @@
+package intersection
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/accio/internal/output"
+)
+
+// newMatch creates a match found on the given sites
+func newMatch(username string, sites ...string) ProfileMatch {
+	match := ProfileMatch{Username: username, NameMatches: make(map[string]bool)}
+	for _, site := range sites {
+		match.Results = append(match.Results, output.Result{Site: site, Exists: true})
+	}
+	match.MatchCount = len(match.Results)
+	return match
+}
+
+func TestDefaultWeights(t *testing.T) {
+	weights := DefaultWeights()
+
+	single := weights.Score(newMatch("a", "Reddit"))
+	if single < 0.05 || single > 0.15 {
+		t.Errorf("Expected a single match to score about 0.1, got %f", single)
+	}
+
+	four := weights.Score(newMatch("b", "Reddit", "Flickr", "Steam", "Vimeo"))
+	if four < 0.65 || four > 0.75 {
+		t.Errorf("Expected four matches to score about 0.7, got %f", four)
+	}
+
+	withGitHub := weights.Score(newMatch("c", "GitHub", "Flickr", "Steam", "Vimeo"))
+	if withGitHub <= four {
+		t.Errorf("Expected a GitHub match to score higher than %f, got %f", four, withGitHub)
+	}
+}
+
+func TestTrain(t *testing.T) {
+	// Matches on GitHub were confirmed by analysts, matches on Pinterest were not
+	var samples []Sample
+	for i := 0; i < 10; i++ {
+		samples = append(samples,
+			Sample{Match: newMatch("dev", "GitHub", "Reddit"), Correct: true},
+			Sample{Match: newMatch("pin", "Pinterest", "Reddit"), Correct: false},
+		)
+	}
+
+	weights, err := Train(samples, TrainOptions{})
+	if err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+
+	if weights.Platforms["GitHub"] <= weights.Platforms["Pinterest"] {
+		t.Errorf("Expected GitHub to outweigh Pinterest, got %f and %f", weights.Platforms["GitHub"], weights.Platforms["Pinterest"])
+	}
+	if weights.Score(newMatch("x", "GitHub", "Reddit")) <= 0.5 {
+		t.Errorf("Expected a confirmed pattern to score above 0.5")
+	}
+	if weights.Score(newMatch("y", "Pinterest", "Reddit")) >= 0.5 {
+		t.Errorf("Expected a rejected pattern to score below 0.5")
+	}
+	if weights.Training == nil || weights.Training.Samples != 20 || weights.Training.Accuracy != 1 {
+		t.Errorf("Unexpected training stats: %+v", weights.Training)
+	}
+
+	again, err := Train(samples, TrainOptions{})
+	if err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+	if again.Bias != weights.Bias || again.Platforms["GitHub"] != weights.Platforms["GitHub"] {
+		t.Errorf("Expected training to be deterministic")
+	}
+}
+
+func TestTrainNeedsBothLabels(t *testing.T) {
+	samples := []Sample{{Match: newMatch("a", "GitHub"), Correct: true}}
+	if _, err := Train(samples, TrainOptions{}); !errors.Is(err, ErrNotEnoughSamples) {
+		t.Errorf("Expected ErrNotEnoughSamples, got %v", err)
+	}
+}
+
+func TestWeightsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models", "weights.json")
+	t.Setenv("CONFIDENCE_WEIGHTS_FILE", path)
+
+	// Without a file the default weights are used
+	allResults := map[string][]output.Result{
+		"johndoe": {{Site: "Pinterest", Exists: true}},
+	}
+	result := AnalyzeResults(allResults)
+	if want := DefaultWeights().Score(result.Matches[0]); result.Matches[0].Confidence != want {
+		t.Errorf("Expected default confidence %f, got %f", want, result.Matches[0].Confidence)
+	}
+
+	weights := &Weights{Version: WeightsVersion, Bias: 2, Platforms: map[string]float64{"Pinterest": 1}}
+	if err := weights.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadWeights(path)
+	if err != nil {
+		t.Fatalf("LoadWeights failed: %v", err)
+	}
+	if loaded.Bias != 2 || loaded.Platforms["Pinterest"] != 1 {
+		t.Errorf("Expected saved weights to round trip, got %+v", loaded)
+	}
+
+	result = AnalyzeResults(allResults)
+	if want := sigmoid(3); result.Matches[0].Confidence != want {
+		t.Errorf("Expected confidence %f from the weights file, got %f", want, result.Matches[0].Confidence)
+	}
+}
+
+func TestCurrentWeightsReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights.json")
+	t.Setenv("CONFIDENCE_WEIGHTS_FILE", path)
+
+	if err := (&Weights{Version: WeightsVersion, Bias: 1}).Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	first := CurrentWeights()
+	if first.Bias != 1 {
+		t.Fatalf("Expected the saved bias, got %f", first.Bias)
+	}
+	if CurrentWeights() != first {
+		t.Error("Expected an unchanged file to be served from the cache")
+	}
+
+	// Retraining replaces the file
+	if err := (&Weights{Version: WeightsVersion, Bias: 2}).Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	if bias := CurrentWeights().Bias; bias != 2 {
+		t.Errorf("Expected the new bias after the file changed, got %f", bias)
+	}
+
+	// A removed file falls back to the defaults
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if bias := CurrentWeights().Bias; bias != DefaultWeights().Bias {
+		t.Errorf("Expected the default bias without a file, got %f", bias)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	weights := DefaultWeights()
+	match := newMatch("johndoe", "GitHub", "Reddit", "Pinterest")
+	match.CrossLinks = 2
+	weights.Apply(&match)
+
+	var sum float64
+	signals := make(map[string]int)
+	for _, evidence := range match.Evidence {
+		sum += evidence.Weight
+		signals[evidence.Signal]++
+		if evidence.Reason == "" {
+			t.Errorf("Expected a reason for %s evidence", evidence.Signal)
+		}
+	}
+
+	if want := weights.Score(match); math.Abs(sigmoid(sum)-want) > 1e-12 || math.Abs(match.Confidence-want) > 1e-12 {
+		t.Errorf("Expected evidence to add up to confidence %f, got %f (confidence %f)", want, sigmoid(sum), match.Confidence)
+	}
+
+	// GitHub has a platform weight, Reddit and Pinterest do not
+	for signal, want := range map[string]int{
+		SignalBaseline:   1,
+		SignalMatchCount: 1,
+		SignalPlatform:   1,
+		SignalCrossLink:  1,
+		SignalNameMatch:  0,
+		SignalAvatar:     0,
+	} {
+		if signals[signal] != want {
+			t.Errorf("Expected %d %s evidence, got %d", want, signal, signals[signal])
+		}
+	}
+}
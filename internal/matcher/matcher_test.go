@@ -155,3 +155,56 @@ func TestGenerateAdvancedUsernames(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"  Zoë O'Brien-Smith ": "zoe obrien smith",
+		"JOSÉ ÁLVAREZ":         "jose alvarez",
+		"Łukasz Weiß":          "lukasz weiss",
+		"J.R.R. Tolkien":       "j r r tolkien",
+		"🚀 John Doe 🚀":         "john doe",
+	}
+
+	for input, expected := range tests {
+		if got := NormalizeName(input); got != expected {
+			t.Errorf("NormalizeName(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestMatchesName(t *testing.T) {
+	tests := []struct {
+		searched    string
+		displayName string
+		expected    bool
+	}{
+		{"John Doe", "John Doe", true},
+		{"John Doe", "john doe", true},
+		{"José Álvarez", "Jose Alvarez", true},
+		{"Jose Alvarez", "José Álvarez", true},
+		{"John Doe", "J. Doe", true},
+		{"John Doe", "John D.", true},
+		{"John Doe", "J. D.", false},
+		{"John Doe", "Doe, John", true},
+		{"John Doe", "John Michael Doe", true},
+		{"John Doe", "John Doe | Engineer", true},
+		{"John Doe", "JohnDoe", true},
+		{"John Doe", "jdoe", true},
+		{"Robert Smith", "Bob Smith", true},
+		{"Bob Smith", "Robert Smith", true},
+		{"William Gates", "Bill Gates", true},
+		{"John Doe", "Jane Doe", false},
+		{"John Doe", "John Smith", false},
+		{"John Doe", "John", false},
+		{"John Doe", "", false},
+		{"John", "John Smith", true},
+		{"John", "J. Smith", false},
+	}
+
+	for _, test := range tests {
+		name := ParseFullName(test.searched)
+		if got := name.MatchesName(test.displayName); got != test.expected {
+			t.Errorf("%q MatchesName(%q) = %v, expected %v", test.searched, test.displayName, got, test.expected)
+		}
+	}
+}
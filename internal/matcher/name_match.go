@@ -0,0 +1,210 @@
+package matcher
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// nicknameGroups lists first names that are commonly used for one another
+var nicknameGroups = [][]string{
+	{"alexander", "alex", "xander", "sasha"},
+	{"alexandra", "alex", "lexi", "sasha"},
+	{"andrew", "andy", "drew"},
+	{"anthony", "tony"},
+	{"benjamin", "ben", "benny"},
+	{"catherine", "katherine", "cathy", "kathy", "kate", "katie", "kat"},
+	{"charles", "charlie", "chuck"},
+	{"christopher", "chris", "kit"},
+	{"christina", "christine", "chris", "tina"},
+	{"daniel", "dan", "danny"},
+	{"david", "dave", "davey"},
+	{"deborah", "debra", "deb", "debbie"},
+	{"donald", "don", "donnie"},
+	{"edward", "ed", "eddie", "ted", "ned"},
+	{"elizabeth", "liz", "lizzie", "beth", "betty", "eliza", "lisa"},
+	{"frederick", "fred", "freddie"},
+	{"gabriel", "gabe"},
+	{"gregory", "greg"},
+	{"henry", "harry", "hank"},
+	{"isabella", "isabel", "bella", "izzy"},
+	{"james", "jim", "jimmy", "jamie"},
+	{"jennifer", "jen", "jenny"},
+	{"john", "jack", "johnny", "jon"},
+	{"jonathan", "jon", "johnny"},
+	{"joseph", "joe", "joey"},
+	{"joshua", "josh"},
+	{"kenneth", "ken", "kenny"},
+	{"lawrence", "larry"},
+	{"margaret", "maggie", "meg", "peggy"},
+	{"matthew", "matt"},
+	{"michael", "mike", "mikey", "mick"},
+	{"nicholas", "nick", "nicky"},
+	{"patricia", "pat", "patty", "trish"},
+	{"patrick", "pat", "paddy"},
+	{"peter", "pete"},
+	{"rebecca", "becky", "becca"},
+	{"richard", "rich", "richie", "rick", "dick"},
+	{"robert", "rob", "robbie", "bob", "bobby"},
+	{"ronald", "ron", "ronnie"},
+	{"samantha", "sam", "sammy"},
+	{"samuel", "sam", "sammy"},
+	{"stephen", "steven", "steve"},
+	{"susan", "sue", "susie"},
+	{"thomas", "tom", "tommy"},
+	{"timothy", "tim", "timmy"},
+	{"victoria", "vicky", "tori"},
+	{"william", "will", "bill", "billy", "liam"},
+	{"zachary", "zach", "zack"},
+}
+
+// nicknames maps each name in nicknameGroups to the indexes of its groups
+var nicknames = func() map[string][]int {
+	index := make(map[string][]int)
+	for i, group := range nicknameGroups {
+		for _, name := range group {
+			index[name] = append(index[name], i)
+		}
+	}
+	return index
+}()
+
+// letterReplacer spells out letters that do not decompose into a base
+// letter and a diacritic
+var letterReplacer = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d", "ð", "d", "þ", "th", "ı", "i",
+)
+
+// NormalizeName lowercases a name, strips diacritics and punctuation and
+// collapses whitespace, so "  Zoë O'Brien-Smith " becomes "zoe obrien smith"
+func NormalizeName(name string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), strings.ToLower(name))
+	if err != nil {
+		stripped = strings.ToLower(name)
+	}
+	stripped = letterReplacer.Replace(stripped)
+
+	var b strings.Builder
+	for _, r := range stripped {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '\'' || r == '’':
+			// "O'Brien" is one word
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// MatchesName reports whether a display name plausibly refers to this
+// person. Matching ignores case, diacritics, punctuation, word order and
+// extra words, and accepts common nicknames, an initial for either name (but
+// not both) and names written together such as "JohnDoe".
+func (n *NameInfo) MatchesName(displayName string) bool {
+	first := firstToken(NormalizeName(n.FirstName))
+	last := lastToken(NormalizeName(n.LastName))
+	if first == "" {
+		return false
+	}
+
+	tokens := nameTokens(displayName)
+	if len(tokens) == 0 {
+		return false
+	}
+
+	// Without a last name there is nothing to confirm a first name with, so
+	// it must match in full
+	if last == "" {
+		for _, token := range tokens {
+			if sameFirstName(token, first) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Display names often carry extra words, such as "John Doe | Engineer",
+	// so any two words may be the first and last name, in either order
+	for i, candidateFirst := range tokens {
+		if matchesJoined(candidateFirst, first, last) {
+			return true
+		}
+		for j, candidateLast := range tokens {
+			if i != j && matchesParts(candidateFirst, candidateLast, first, last) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nameTokens normalizes a display name into words
+func nameTokens(displayName string) []string {
+	return strings.Fields(NormalizeName(displayName))
+}
+
+// matchesParts reports whether a candidate first and last name match the
+// searched ones, allowing at most one of them to be an initial
+func matchesParts(candidateFirst, candidateLast, first, last string) bool {
+	firstInitial := isInitialOf(candidateFirst, first)
+	lastInitial := isInitialOf(candidateLast, last)
+	if firstInitial && lastInitial {
+		return false
+	}
+
+	return (firstInitial || sameFirstName(candidateFirst, first)) &&
+		(lastInitial || candidateLast == last)
+}
+
+// matchesJoined reports whether a single word is the first and last name
+// written together, with the first name possibly a nickname or initial
+func matchesJoined(word, first, last string) bool {
+	prefix, ok := strings.CutSuffix(word, last)
+	if !ok || prefix == "" {
+		return false
+	}
+	return isInitialOf(prefix, first) || sameFirstName(prefix, first)
+}
+
+// sameFirstName reports whether two normalized first names are equal or
+// nicknames of one another
+func sameFirstName(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, i := range nicknames[a] {
+		for _, j := range nicknames[b] {
+			if i == j {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isInitialOf reports whether token is the initial of name
+func isInitialOf(token, name string) bool {
+	return len([]rune(token)) == 1 && strings.HasPrefix(name, token)
+}
+
+// firstToken returns the first word of a normalized name
+func firstToken(name string) string {
+	if i := strings.IndexByte(name, ' '); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// lastToken returns the last word of a normalized name
+func lastToken(name string) string {
+	if i := strings.LastIndexByte(name, ' '); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
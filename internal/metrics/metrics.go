@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every accio metric
+const namespace = "accio"
+
+// Site check outcomes
+const (
+	OutcomeFound    = "found"
+	OutcomeNotFound = "not_found"
+	OutcomeError    = "error"
+)
+
+// Profile cache lookup results
+const (
+	CacheHit      = "hit"
+	CacheStale    = "stale"
+	CacheExpired  = "expired"
+	CacheMiss     = "miss"
+	CacheNegative = "negative"
+	CacheBypass   = "bypass"
+)
+
+var (
+	// HTTPRequestDuration observes HTTP request latencies per route
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latencies by method, route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// ScansStarted counts scan jobs picked up by a worker
+	ScansStarted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scans_started_total",
+		Help:      "Scan jobs started by a worker.",
+	})
+
+	// ScansFinished counts scan jobs that reached a final status
+	ScansFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scans_finished_total",
+		Help:      "Scan jobs that reached a final status, by status.",
+	}, []string{"status"})
+
+	// ScanQueueDepth reports the number of scan jobs waiting for a worker
+	ScanQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "scan_queue_depth",
+		Help:      "Scan jobs waiting for a worker.",
+	})
+
+	// SiteChecks counts username probes per site and outcome
+	SiteChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "site_checks_total",
+		Help:      "Username probes by site and outcome (found, not_found, error).",
+	}, []string{"site", "outcome"})
+
+	// PlatformRequests counts platform API client calls
+	PlatformRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "platform_requests_total",
+		Help:      "Platform API client calls by platform and operation.",
+	}, []string{"platform", "operation"})
+
+	// PlatformErrors counts failed platform API client calls by error class
+	PlatformErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "platform_errors_total",
+		Help:      "Failed platform API client calls by platform, operation and error class.",
+	}, []string{"platform", "operation", "class"})
+
+	// ProfileCacheLookups counts stored profile lookups answered from the
+	// repository (hit, stale, negative) or requiring a platform API call
+	// (expired, miss, bypass)
+	ProfileCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "profile_cache_lookups_total",
+		Help:      "Profile lookups by kind (username, name) and result (hit, stale, expired, miss, negative, bypass).",
+	}, []string{"lookup", "result"})
+
+	// WatchlistRuns counts watchlist entries rescanned by the scheduler
+	WatchlistRuns = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "watchlist_runs_total",
+		Help:      "Watchlist entries rescanned by the scheduler.",
+	})
+
+	// WatchlistAlerts counts changes found between watchlist snapshots
+	WatchlistAlerts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "watchlist_alerts_total",
+		Help:      "Changes found between watchlist snapshots by kind.",
+	}, []string{"kind"})
+
+	// WebhookDeliveries counts webhook delivery attempts
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook delivery attempts by event and outcome (succeeded, retried, failed).",
+	}, []string{"event", "outcome"})
+)
+
+// Handler returns the HTTP handler exposing all registered metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
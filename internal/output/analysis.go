@@ -0,0 +1,117 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Evidence is one signal that raised or lowered the confidence of a match
+type Evidence struct {
+	Signal string  `json:"signal"` // Kind of signal, such as "platform" or "name_match"
+	Weight float64 `json:"weight"` // Contribution to the log-odds of the confidence; negative lowers it
+	Reason string  `json:"reason"` // Human-readable explanation
+}
+
+// LinksTo is the type of a link from one profile to another
+const LinksTo = "links-to"
+
+// Link is an explicit reference from one found profile to another, such as a
+// Twitter handle in GitHub profile data or a URL in a bio
+type Link struct {
+	Type         string `json:"type"`          // Always LinksTo
+	FromSite     string `json:"from_site"`     // Site of the linking profile
+	FromUsername string `json:"from_username"` // Username of the linking profile
+	ToSite       string `json:"to_site"`       // Site of the linked profile
+	ToUsername   string `json:"to_username"`   // Username of the linked profile
+	ToURL        string `json:"to_url"`        // URL of the linked profile
+	Source       string `json:"source"`        // Where the link was found: "platform_data", "bio" or "page"
+}
+
+// Analysis is the confidence that the found profiles belong to the same
+// person, along with the evidence behind it
+type Analysis struct {
+	Confidence float64    `json:"confidence"`
+	Evidence   []Evidence `json:"evidence"`
+	Links      []Link     `json:"links,omitempty"`
+}
+
+// jsonReport is the JSON report output: the results with the analysis, if
+// there is one
+type jsonReport struct {
+	Results []Result `json:"results"`
+	*Analysis
+}
+
+// WithAnalysis adds a confidence analysis to the summary and saved files
+func (f *Formatter) WithAnalysis(analysis *Analysis) *Formatter {
+	f.Analysis = analysis
+	return f
+}
+
+// jsonReport returns the value written in JSON report format
+func (s Summary) jsonReport() jsonReport {
+	results := s.Results
+	if results == nil {
+		results = []Result{}
+	}
+	return jsonReport{Results: results, Analysis: s.Analysis}
+}
+
+// writeAnalysisText writes the analysis as plain text
+func writeAnalysisText(w io.Writer, analysis *Analysis, color bool) {
+	if color {
+		fmt.Fprintf(w, "\n\033[1mConfidence %.0f%% that these profiles belong to the same person\033[0m\n", analysis.Confidence*100)
+	} else {
+		fmt.Fprintf(w, "\nConfidence %.0f%% that these profiles belong to the same person\n", analysis.Confidence*100)
+	}
+
+	for _, evidence := range analysis.Evidence {
+		weight := fmt.Sprintf("%+.2f", evidence.Weight)
+		if color {
+			if evidence.Weight >= 0 {
+				weight = "\033[32m" + weight + "\033[0m"
+			} else {
+				weight = "\033[31m" + weight + "\033[0m"
+			}
+		}
+		fmt.Fprintf(w, "  %s  %s\n", weight, evidence.Reason)
+	}
+
+	if len(analysis.Links) > 0 {
+		fmt.Fprintf(w, "\nLinks between profiles:\n")
+		for _, link := range analysis.Links {
+			fmt.Fprintf(w, "  %s/%s -> %s (%s)\n", link.FromSite, link.FromUsername, link.ToURL, link.Source)
+		}
+	}
+}
+
+// writeAnalysisMarkdown writes the analysis as a Markdown section
+func writeAnalysisMarkdown(w io.Writer, analysis *Analysis) {
+	fmt.Fprintf(w, "\n## Confidence\n\n")
+	fmt.Fprintf(w, "**%.0f%%** that these profiles belong to the same person.\n\n", analysis.Confidence*100)
+
+	if len(analysis.Evidence) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "| Signal | Weight | Reason |\n")
+	fmt.Fprintf(w, "|--------|-------:|--------|\n")
+	for _, evidence := range analysis.Evidence {
+		fmt.Fprintf(w, "| %s | %+.2f | %s |\n", evidence.Signal, evidence.Weight, markdownCell(evidence.Reason))
+	}
+
+	if len(analysis.Links) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n### Links Between Profiles\n\n")
+	for _, link := range analysis.Links {
+		fmt.Fprintf(w, "- %s/%s links to [%s/%s](%s) (%s)\n", link.FromSite, link.FromUsername, link.ToSite, link.ToUsername, link.ToURL, link.Source)
+	}
+}
+
+// markdownCell escapes text for use in a Markdown table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
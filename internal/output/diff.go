@@ -0,0 +1,351 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// StatusMissing is the status of a result present in only one result set
+const StatusMissing = "missing"
+
+// Change is a result whose status or fields differ between two result sets
+type Change struct {
+	Username  string        `json:"username,omitempty"`
+	Site      string        `json:"site"`
+	URL       string        `json:"url"` // URL in the new result set, or the old one if missing there
+	OldStatus string        `json:"old_status"`
+	NewStatus string        `json:"new_status"`
+	Fields    []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange is a field of a result whose value changed
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Diff is the difference between an old and a new result set
+type Diff struct {
+	Claimed       []Change `json:"claimed"`        // Profiles found only in the new set
+	Disappeared   []Change `json:"disappeared"`    // Profiles found only in the old set
+	StatusChanged []Change `json:"status_changed"` // Other status changes, such as checks that started failing
+	FieldsChanged []Change `json:"fields_changed"` // Results with the same status and changed URL or profile fields
+}
+
+// HasChanges reports whether the result sets differ
+func (d *Diff) HasChanges() bool {
+	return d.Count() > 0
+}
+
+// Count returns the number of changes
+func (d *Diff) Count() int {
+	return len(d.Claimed) + len(d.Disappeared) + len(d.StatusChanged) + len(d.FieldsChanged)
+}
+
+// diffKey identifies a result across result sets
+type diffKey struct {
+	username string
+	site     string
+}
+
+// DiffResults compares two result sets. Results are matched by username and
+// site, or by site alone if either set was saved without usernames.
+func DiffResults(old, new []Result) *Diff {
+	byUsername := hasUsernames(old) && hasUsernames(new)
+	oldResults := keyResults(old, byUsername)
+	newResults := keyResults(new, byUsername)
+
+	diff := &Diff{
+		Claimed:       []Change{},
+		Disappeared:   []Change{},
+		StatusChanged: []Change{},
+		FieldsChanged: []Change{},
+	}
+	for key, newResult := range newResults {
+		oldResult, ok := oldResults[key]
+		oldStatus := StatusMissing
+		if ok {
+			oldStatus = resultStatus(oldResult)
+		}
+		diff.add(key, oldResult, oldStatus, newResult.URL, resultStatus(newResult), newResult)
+	}
+	for key, oldResult := range oldResults {
+		if _, ok := newResults[key]; !ok {
+			diff.add(key, oldResult, resultStatus(oldResult), oldResult.URL, StatusMissing, Result{})
+		}
+	}
+
+	for _, changes := range [][]Change{diff.Claimed, diff.Disappeared, diff.StatusChanged, diff.FieldsChanged} {
+		sort.Slice(changes, func(i, j int) bool {
+			if changes[i].Username != changes[j].Username {
+				return changes[i].Username < changes[j].Username
+			}
+			return changes[i].Site < changes[j].Site
+		})
+	}
+	return diff
+}
+
+// hasUsernames reports whether every result has a username
+func hasUsernames(results []Result) bool {
+	for _, result := range results {
+		if result.Username == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// keyResults indexes results by site, and by username too if byUsername
+func keyResults(results []Result, byUsername bool) map[diffKey]Result {
+	keyed := make(map[diffKey]Result, len(results))
+	for _, result := range results {
+		key := diffKey{site: result.Site}
+		if byUsername {
+			key.username = result.Username
+		}
+		keyed[key] = result
+	}
+	return keyed
+}
+
+// add classifies the change of one result, if it changed
+func (d *Diff) add(key diffKey, oldResult Result, oldStatus, url, newStatus string, newResult Result) {
+	change := Change{
+		Username:  key.username,
+		Site:      key.site,
+		URL:       url,
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+	}
+
+	switch {
+	case isAbsent(oldStatus) && isAbsent(newStatus):
+		// Sites dropped from or added to a scan without a profile are no change
+	case oldStatus == newStatus:
+		if oldResult.URL != newResult.URL {
+			change.Fields = append(change.Fields, FieldChange{Field: "url", Old: oldResult.URL, New: newResult.URL})
+		}
+		change.Fields = append(change.Fields, profileChanges(oldResult.Profile, newResult.Profile)...)
+		if len(change.Fields) > 0 {
+			d.FieldsChanged = append(d.FieldsChanged, change)
+		}
+	case newStatus == StatusFound && oldStatus != StatusError:
+		d.Claimed = append(d.Claimed, change)
+	case oldStatus == StatusFound && newStatus != StatusError:
+		d.Disappeared = append(d.Disappeared, change)
+	default:
+		d.StatusChanged = append(d.StatusChanged, change)
+	}
+}
+
+// profileChanges returns the profile fields that changed, or nothing unless
+// both results were saved with their profile
+func profileChanges(old, new *Profile) []FieldChange {
+	if old == nil || new == nil {
+		return nil
+	}
+
+	var changes []FieldChange
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"name", old.Name, new.Name},
+		{"bio", old.Bio, new.Bio},
+		{"avatar_url", old.AvatarURL, new.AvatarURL},
+		{"followers", strconv.FormatInt(old.Followers, 10), strconv.FormatInt(new.Followers, 10)},
+	}
+	for _, field := range fields {
+		if field.old != field.new {
+			changes = append(changes, FieldChange{Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+	return changes
+}
+
+// isAbsent reports whether a status means no profile is known
+func isAbsent(status string) bool {
+	return status == StatusNotFound || status == StatusMissing
+}
+
+// ReadResults reads results saved in JSON, JSON report or NDJSON format
+func ReadResults(r io.Reader) ([]Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("no results")
+	}
+
+	if trimmed[0] == '[' {
+		var results []Result
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	var report struct {
+		Results *[]Result `json:"results"`
+	}
+	if err := json.Unmarshal(trimmed, &report); err == nil && report.Results != nil {
+		return *report.Results, nil
+	}
+
+	return readNDJSONResults(trimmed)
+}
+
+// readNDJSONResults reads the result records of NDJSON output
+func readNDJSONResults(data []byte) ([]Result, error) {
+	var results []Result
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var record ndjsonResult
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if record.Type != RecordResult {
+			continue
+		}
+
+		result := Result{Username: record.Username, Site: record.Site, URL: record.URL, Exists: record.Exists, Profile: record.Profile}
+		if record.Error != "" {
+			result.Error = errors.New(record.Error)
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if results == nil {
+		return nil, errors.New("no results")
+	}
+	return results, nil
+}
+
+// WriteDiffText writes a diff as plain text, optionally colored
+func WriteDiffText(w io.Writer, diff *Diff, color bool) {
+	sections := []struct {
+		title   string
+		symbol  string
+		code    string
+		showURL bool
+		changes []Change
+	}{
+		{"Claimed", "+", "32", true, diff.Claimed},
+		{"Disappeared", "-", "31", true, diff.Disappeared},
+		{"Status changed", "~", "33", false, diff.StatusChanged},
+		{"Fields changed", "~", "33", false, diff.FieldsChanged},
+	}
+
+	for _, section := range sections {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s (%d):\n", section.title, len(section.changes))
+		for _, change := range section.changes {
+			symbol := "[" + section.symbol + "]"
+			if color {
+				symbol = "\033[" + section.code + "m" + symbol + "\033[0m"
+			}
+			detail := changeDetail(change)
+			if section.showURL {
+				detail = change.URL
+			}
+			fmt.Fprintf(w, "  %s %s: %s\n", symbol, changeName(change), detail)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if !diff.HasChanges() {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+	total := fmt.Sprintf("%d changes", diff.Count())
+	if diff.Count() == 1 {
+		total = "1 change"
+	}
+	if color {
+		fmt.Fprintf(w, "\033[1m%s\033[0m\n", total)
+	} else {
+		fmt.Fprintln(w, total)
+	}
+}
+
+// WriteDiffJSON writes a diff as indented JSON
+func WriteDiffJSON(w io.Writer, diff *Diff) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diff)
+}
+
+// WriteDiffMarkdown writes a diff as a Markdown document
+func WriteDiffMarkdown(w io.Writer, diff *Diff) {
+	fmt.Fprintf(w, "# Accio Diff\n\n")
+
+	sections := []struct {
+		title   string
+		changes []Change
+	}{
+		{"Claimed", diff.Claimed},
+		{"Disappeared", diff.Disappeared},
+		{"Status Changed", diff.StatusChanged},
+		{"Fields Changed", diff.FieldsChanged},
+	}
+	for _, section := range sections {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "## %s\n\n", section.title)
+		for _, change := range section.changes {
+			fmt.Fprintf(w, "- [%s](%s): %s\n", changeName(change), change.URL, changeDetail(change))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "## Summary\n\n")
+	fmt.Fprintf(w, "- **Claimed**: %d\n", len(diff.Claimed))
+	fmt.Fprintf(w, "- **Disappeared**: %d\n", len(diff.Disappeared))
+	fmt.Fprintf(w, "- **Status changed**: %d\n", len(diff.StatusChanged))
+	fmt.Fprintf(w, "- **Fields changed**: %d\n", len(diff.FieldsChanged))
+}
+
+// changeName returns the site of a change, with the username if known
+func changeName(change Change) string {
+	if change.Username == "" {
+		return change.Site
+	}
+	return change.Site + "/" + change.Username
+}
+
+// changeDetail describes what changed
+func changeDetail(change Change) string {
+	if len(change.Fields) == 0 {
+		return change.OldStatus + " -> " + change.NewStatus
+	}
+
+	var detail string
+	for i, field := range change.Fields {
+		if i > 0 {
+			detail += ", "
+		}
+		detail += fmt.Sprintf("%s %s -> %s", field.Field, field.Old, field.New)
+	}
+	return detail
+}
@@ -0,0 +1,122 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDiffResults(t *testing.T) {
+	old := []Result{
+		{Username: "johndoe", Site: "GitHub", URL: "https://github.com/johndoe", Exists: true},
+		{Username: "johndoe", Site: "Reddit", URL: "https://www.reddit.com/user/johndoe"},
+		{Username: "johndoe", Site: "Steam", URL: "https://steamcommunity.com/id/johndoe", Exists: true},
+		{Username: "johndoe", Site: "Twitter", URL: "http://twitter.com/johndoe", Exists: true},
+		{Username: "johndoe", Site: "Twitch", URL: "https://twitch.tv/johndoe"},
+		{Username: "jdoe", Site: "GitHub", URL: "https://github.com/jdoe", Exists: true},
+	}
+	new := []Result{
+		{Username: "johndoe", Site: "GitHub", URL: "https://github.com/johndoe", Exists: true},
+		{Username: "johndoe", Site: "Reddit", URL: "https://www.reddit.com/user/johndoe", Exists: true},
+		{Username: "johndoe", Site: "Steam", URL: "https://steamcommunity.com/id/johndoe", Error: errors.New("timeout")},
+		{Username: "johndoe", Site: "Twitter", URL: "https://twitter.com/johndoe", Exists: true},
+	}
+
+	diff := DiffResults(old, new)
+	if len(diff.Claimed) != 1 || diff.Claimed[0].Site != "Reddit" {
+		t.Errorf("Expected Reddit claimed, got %+v", diff.Claimed)
+	}
+	if len(diff.Disappeared) != 1 || diff.Disappeared[0].Username != "jdoe" || diff.Disappeared[0].NewStatus != StatusMissing {
+		t.Errorf("Expected jdoe's GitHub missing, got %+v", diff.Disappeared)
+	}
+	if len(diff.StatusChanged) != 1 || diff.StatusChanged[0].OldStatus != StatusFound || diff.StatusChanged[0].NewStatus != StatusError {
+		t.Errorf("Expected Steam found -> error, got %+v", diff.StatusChanged)
+	}
+	if len(diff.FieldsChanged) != 1 || diff.FieldsChanged[0].Fields[0] != (FieldChange{Field: "url", Old: "http://twitter.com/johndoe", New: "https://twitter.com/johndoe"}) {
+		t.Errorf("Expected Twitter URL change, got %+v", diff.FieldsChanged)
+	}
+	if diff.Count() != 4 {
+		t.Errorf("Expected 4 changes, got %d", diff.Count())
+	}
+
+	if DiffResults(new, new).HasChanges() {
+		t.Error("Expected no changes between identical result sets")
+	}
+}
+
+func TestDiffResultsProfiles(t *testing.T) {
+	old := []Result{
+		{Username: "johndoe", Site: "GitHub", URL: "https://github.com/johndoe", Exists: true,
+			Profile: &Profile{Name: "John Doe", Bio: "Go developer", AvatarURL: "https://avatars.example/1", Followers: 10}},
+		{Username: "johndoe", Site: "Twitter", URL: "https://twitter.com/johndoe", Exists: true},
+	}
+	new := []Result{
+		{Username: "johndoe", Site: "GitHub", URL: "https://github.com/johndoe", Exists: true,
+			Profile: &Profile{Name: "John Doe", Bio: "Rust developer", AvatarURL: "https://avatars.example/1", Followers: 12}},
+		{Username: "johndoe", Site: "Twitter", URL: "https://twitter.com/johndoe", Exists: true,
+			Profile: &Profile{Name: "John", Followers: 5}},
+	}
+
+	// Twitter was saved without its profile the first time, so only GitHub
+	// changed
+	diff := DiffResults(old, new)
+	if len(diff.FieldsChanged) != 1 || diff.FieldsChanged[0].Site != "GitHub" {
+		t.Fatalf("Expected GitHub fields changed, got %+v", diff.FieldsChanged)
+	}
+	want := []FieldChange{
+		{Field: "bio", Old: "Go developer", New: "Rust developer"},
+		{Field: "followers", Old: "10", New: "12"},
+	}
+	if got := diff.FieldsChanged[0].Fields; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestDiffResultsWithoutUsernames(t *testing.T) {
+	// Files saved before results carried usernames match by site
+	old := []Result{{Site: "GitHub", URL: "https://github.com/johndoe", Exists: true}}
+	new := []Result{{Username: "johndoe", Site: "GitHub", URL: "https://github.com/johndoe", Exists: true}}
+	if diff := DiffResults(old, new); diff.HasChanges() {
+		t.Errorf("Expected no changes, got %+v", diff)
+	}
+}
+
+func TestReadResults(t *testing.T) {
+	profile := Profile{Name: "John Doe", Bio: "Go developer", Followers: 42}
+	results := []Result{
+		{Username: "johndoe", Site: "GitHub", URL: "https://github.com/johndoe", Exists: true, Profile: &profile},
+		{Username: "johndoe", Site: "Steam", URL: "https://steamcommunity.com/id/johndoe", Error: errors.New("timeout")},
+	}
+
+	for _, format := range []FormatType{FormatJSON, FormatJSONReport, FormatNDJSON} {
+		for _, analysis := range []*Analysis{nil, {Confidence: 0.5}} {
+			var buf bytes.Buffer
+			if err := Encode(format, &buf, Options{}, Summary{Results: results, Analysis: analysis}); err != nil {
+				t.Fatalf("Failed to encode %s: %v", format, err)
+			}
+
+			read, err := ReadResults(&buf)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", format, err)
+			}
+			if len(read) != 2 || read[0].URL != results[0].URL || read[0].Profile == nil || *read[0].Profile != profile ||
+				read[1].Error == nil || read[1].Error.Error() != "timeout" {
+				t.Errorf("Expected %s results to round-trip, got %+v", format, read)
+			}
+		}
+	}
+
+	if _, err := ReadResults(strings.NewReader("")); err == nil {
+		t.Error("Expected an error for an empty file")
+	}
+}
+
+func TestWriteDiffText(t *testing.T) {
+	var buf bytes.Buffer
+	WriteDiffText(&buf, DiffResults(nil, []Result{{Site: "GitHub", URL: "https://github.com/johndoe", Exists: true}}), false)
+	want := "Claimed (1):\n  [+] GitHub: https://github.com/johndoe\n\n1 change\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
@@ -0,0 +1,172 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/accio/internal/checker"
+)
+
+// ErrUnknownFormat is returned for formats that are not registered
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// Encoder writes results in one format to the writer it was created with.
+// Begin is called before the first result, WriteResult as each result
+// arrives and End once all results are in. Streaming formats write in
+// WriteResult; the others write everything in End.
+type Encoder interface {
+	Begin() error
+	WriteResult(result Result) error
+	End(summary Summary) error
+}
+
+// Options configures an encoder
+type Options struct {
+	Verbose  bool               // Write results that were not found too
+	Color    bool               // Use terminal colors
+	Avatars  AvatarSource       // Optional profile pictures for the HTML report
+	Template *template.Template // Template of the template format
+}
+
+// Summary is what is known once all results are in
+type Summary struct {
+	Results  []Result
+	Analysis *Analysis           // Optional confidence analysis of the found profiles
+	Graph    *Graph              // Optional graph exported by the graph formats
+	Stats    *checker.CheckStats // Optional check statistics
+}
+
+// Registration describes a registered format
+type Registration struct {
+	Format     FormatType
+	NewEncoder func(w io.Writer, options Options) Encoder
+	MediaType  string   // Content type of HTTP responses
+	Extensions []string // File extensions saved in this format, with the dot
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[FormatType]Registration)
+)
+
+// Register adds a format, replacing any format of the same name. Packages
+// outside output call it from an init function to add their own formats.
+func Register(registration Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[registration.Format] = registration
+}
+
+// Lookup returns the registration of a format
+func Lookup(format FormatType) (Registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	registration, ok := registry[format]
+	return registration, ok
+}
+
+// Formats returns the registered formats in alphabetical order
+func Formats() []FormatType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	formats := make([]FormatType, 0, len(registry))
+	for format := range registry {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i] < formats[j]
+	})
+	return formats
+}
+
+// FormatForFile returns the format a file is saved in by its extension,
+// preferring the longest matching extension such as ".stix.json" over ".json"
+func FormatForFile(filename string) (FormatType, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var (
+		format  FormatType
+		longest int
+	)
+	for _, registration := range registry {
+		for _, extension := range registration.Extensions {
+			if len(extension) > longest && strings.HasSuffix(filename, extension) {
+				format, longest = registration.Format, len(extension)
+			}
+		}
+	}
+	return format, longest > 0
+}
+
+// NewEncoder creates an encoder of a registered format writing to w
+func NewEncoder(format FormatType, w io.Writer, options Options) (Encoder, error) {
+	registration, ok := Lookup(format)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+	return registration.NewEncoder(w, options), nil
+}
+
+// Encode writes all results at once with an encoder of a registered format
+func Encode(format FormatType, w io.Writer, options Options, summary Summary) error {
+	encoder, err := NewEncoder(format, w, options)
+	if err != nil {
+		return err
+	}
+
+	if err := encoder.Begin(); err != nil {
+		return err
+	}
+	for _, result := range summary.Results {
+		if err := encoder.WriteResult(result); err != nil {
+			return err
+		}
+	}
+	return encoder.End(summary)
+}
+
+// endEncoder is embedded by encoders that only write in End
+type endEncoder struct{}
+
+// Begin writes nothing
+func (endEncoder) Begin() error { return nil }
+
+// WriteResult writes nothing; results are written in End
+func (endEncoder) WriteResult(Result) error { return nil }
+
+// found counts the found results
+func found(results []Result) int {
+	var count int
+	for _, result := range results {
+		if result.Exists {
+			count++
+		}
+	}
+	return count
+}
+
+func init() {
+	for _, registration := range []Registration{
+		{Format: FormatText, NewEncoder: newTextEncoder, MediaType: "text/plain; charset=utf-8", Extensions: []string{".txt"}},
+		{Format: FormatJSON, NewEncoder: newJSONEncoder, MediaType: "application/json", Extensions: []string{".json"}},
+		{Format: FormatJSONReport, NewEncoder: newJSONReportEncoder, MediaType: "application/json", Extensions: []string{".report.json"}},
+		{Format: FormatNDJSON, NewEncoder: newNDJSONEncoder, MediaType: "application/x-ndjson", Extensions: []string{".ndjson", ".jsonl"}},
+		{Format: FormatCSV, NewEncoder: newCSVEncoder, MediaType: "text/csv; charset=utf-8", Extensions: []string{".csv"}},
+		{Format: FormatMarkdown, NewEncoder: newMarkdownEncoder, MediaType: "text/markdown; charset=utf-8", Extensions: []string{".md"}},
+		{Format: FormatHTML, NewEncoder: newHTMLEncoder, MediaType: "text/html; charset=utf-8", Extensions: []string{".html", ".htm"}},
+		{Format: FormatGraphML, NewEncoder: newGraphMLEncoder, MediaType: "application/graphml+xml", Extensions: []string{".graphml"}},
+		{Format: FormatGEXF, NewEncoder: newGEXFEncoder, MediaType: "application/gexf+xml", Extensions: []string{".gexf"}},
+		{Format: FormatDOT, NewEncoder: newDOTEncoder, MediaType: "text/vnd.graphviz; charset=utf-8", Extensions: []string{".dot", ".gv"}},
+		{Format: FormatSTIX, NewEncoder: newSTIXEncoder, MediaType: "application/stix+json;version=2.1", Extensions: []string{".stix.json"}},
+		{Format: FormatTemplate, NewEncoder: newTemplateEncoder, MediaType: "text/plain; charset=utf-8"},
+	} {
+		Register(registration)
+	}
+}
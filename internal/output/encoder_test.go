@@ -0,0 +1,125 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// countEncoder is a custom format writing one line per found result and
+// the number of results at the end
+type countEncoder struct {
+	w io.Writer
+}
+
+func (e *countEncoder) Begin() error {
+	_, err := fmt.Fprintln(e.w, "begin")
+	return err
+}
+
+func (e *countEncoder) WriteResult(result Result) error {
+	if result.Exists {
+		_, err := fmt.Fprintln(e.w, "found", result.Site)
+		return err
+	}
+	return nil
+}
+
+func (e *countEncoder) End(summary Summary) error {
+	_, err := fmt.Fprintln(e.w, "end", len(summary.Results))
+	return err
+}
+
+func TestRegister(t *testing.T) {
+	Register(Registration{
+		Format:     "count",
+		NewEncoder: func(w io.Writer, _ Options) Encoder { return &countEncoder{w: w} },
+		MediaType:  "text/plain",
+		Extensions: []string{".count"},
+	})
+
+	found := false
+	for _, format := range Formats() {
+		if format == "count" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected registered format in %v", Formats())
+	}
+
+	results := []Result{
+		{Site: "GitHub", URL: "https://github.com/johndoe", Exists: true},
+		{Site: "Reddit", URL: "https://www.reddit.com/user/johndoe"},
+	}
+
+	// The terminal path streams results through the encoder
+	var buf bytes.Buffer
+	formatter := NewFormatter(false).WithFormat("count").WithWriter(&buf)
+	for _, result := range results {
+		formatter.PrintResult(result)
+	}
+	formatter.PrintSummary(results)
+	if want := "begin\nfound GitHub\nend 2\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+
+	// The file path picks the format by extension
+	path := filepath.Join(t.TempDir(), "results.count")
+	if err := NewFormatter(false).SaveToFile(results, path); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != buf.String() {
+		t.Errorf("Expected file to match terminal output, got %q", data)
+	}
+}
+
+func TestFormatForFile(t *testing.T) {
+	for filename, want := range map[string]FormatType{
+		"results.json":      FormatJSON,
+		"results.stix.json": FormatSTIX,
+		"scan.report.json":  FormatJSONReport,
+		"results.jsonl":     FormatNDJSON,
+		"results.gv":        FormatDOT,
+		"results.htm":       FormatHTML,
+	} {
+		if format, ok := FormatForFile(filename); !ok || format != want {
+			t.Errorf("Expected %s for %s, got %s", want, filename, format)
+		}
+	}
+
+	if _, ok := FormatForFile("results"); ok {
+		t.Error("Expected no format for a file without extension")
+	}
+}
+
+func TestNewEncoderUnknownFormat(t *testing.T) {
+	if _, err := NewEncoder("nope", io.Discard, Options{}); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestCSVStreamsRows(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := NewFormatter(false).WithFormat(FormatCSV).WithWriter(&buf)
+	formatter.PrintResult(Result{Site: "GitHub", URL: "https://github.com/johndoe", Exists: true})
+
+	// Rows are written as results arrive, before the summary
+	if want := "Site,URL,Exists,Error\nGitHub,https://github.com/johndoe,true,\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+
+	formatter.PrintSummary(nil)
+	if strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("Expected CSV to have no summary, got %q", buf.String())
+	}
+}
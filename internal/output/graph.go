@@ -0,0 +1,510 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Node types of a graph export
+const (
+	NodeUsername = "username"
+	NodeSite     = "site"
+	NodeProfile  = "profile"
+)
+
+// Edge types of a graph export
+const (
+	// HasProfile connects a username to a profile found for it, weighted by
+	// the confidence that the username's profiles belong to the same person
+	HasProfile = "has-profile"
+	// OnSite connects a profile to the site it was found on
+	OnSite = "on-site"
+)
+
+// GraphNode is a username, site or profile in a graph export
+type GraphNode struct {
+	ID         string
+	Label      string
+	Type       string  // NodeUsername, NodeSite or NodeProfile
+	Platform   string  // Site of a site or profile
+	URL        string  // URL of a site or profile
+	Confidence float64 // Confidence of the username, or of the profile's username
+	Followers  *int64  // Follower count of a profile, if it was fetched
+	username   string  // Username of a username or profile
+}
+
+// GraphEdge is a directed, weighted edge in a graph export
+type GraphEdge struct {
+	Source string
+	Target string
+	Type   string // HasProfile, OnSite or LinksTo
+	Weight float64
+}
+
+// Graph is the graph of the usernames that were searched, the sites they
+// were found on and the profiles found, connected by the intersection and
+// the links between profiles
+type Graph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// NewGraph builds the graph of the found results of each username and the
+// links between them, with the follower counts of the results saved with
+// their profile. Nodes and edges are in a stable order: usernames, then
+// sites, then profiles, each sorted.
+func NewGraph(allResults map[string][]Result, links []Link) *Graph {
+	g := &Graph{}
+
+	usernames := make([]string, 0, len(allResults))
+	for username := range allResults {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	sites := make(map[string]string)
+	var profiles []GraphNode
+	for _, username := range usernames {
+		if username != "" {
+			g.Nodes = append(g.Nodes, GraphNode{
+				ID:       usernameNodeID(username),
+				Label:    username,
+				Type:     NodeUsername,
+				username: username,
+			})
+		}
+		for _, result := range allResults[username] {
+			if !result.Exists {
+				continue
+			}
+			if _, ok := sites[result.Site]; !ok {
+				sites[result.Site] = siteURL(result.URL)
+			}
+			node := GraphNode{
+				ID:       profileNodeID(result.Site, username),
+				Label:    profileLabel(result.Site, username),
+				Type:     NodeProfile,
+				Platform: result.Site,
+				URL:      result.URL,
+				username: username,
+			}
+			if result.Profile != nil {
+				followers := result.Profile.Followers
+				node.Followers = &followers
+			}
+			profiles = append(profiles, node)
+		}
+	}
+
+	siteNames := make([]string, 0, len(sites))
+	for site := range sites {
+		siteNames = append(siteNames, site)
+	}
+	sort.Strings(siteNames)
+	for _, site := range siteNames {
+		g.Nodes = append(g.Nodes, GraphNode{
+			ID:       siteNodeID(site),
+			Label:    site,
+			Type:     NodeSite,
+			Platform: site,
+			URL:      sites[site],
+		})
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].ID < profiles[j].ID
+	})
+	found := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		g.Nodes = append(g.Nodes, profile)
+		found[profile.ID] = true
+		if profile.username != "" {
+			g.Edges = append(g.Edges, GraphEdge{Source: usernameNodeID(profile.username), Target: profile.ID, Type: HasProfile, Weight: 1})
+		}
+		g.Edges = append(g.Edges, GraphEdge{Source: profile.ID, Target: siteNodeID(profile.Platform), Type: OnSite, Weight: 1})
+	}
+
+	for _, link := range links {
+		from, to := profileNodeID(link.FromSite, link.FromUsername), profileNodeID(link.ToSite, link.ToUsername)
+		if found[from] && found[to] {
+			g.Edges = append(g.Edges, GraphEdge{Source: from, Target: to, Type: LinksTo, Weight: 1})
+		}
+	}
+
+	return g
+}
+
+// SetConfidence records the confidence that the profiles found for a
+// username belong to the same person on the username, its profiles and the
+// edges between them
+func (g *Graph) SetConfidence(username string, confidence float64) {
+	for i := range g.Nodes {
+		if g.Nodes[i].Type != NodeSite && g.Nodes[i].username == username {
+			g.Nodes[i].Confidence = confidence
+		}
+	}
+
+	source := usernameNodeID(username)
+	for i := range g.Edges {
+		if g.Edges[i].Type == HasProfile && g.Edges[i].Source == source {
+			g.Edges[i].Weight = confidence
+		}
+	}
+}
+
+// WithGraph sets the graph exported by the graph formats
+func (f *Formatter) WithGraph(graph *Graph) *Formatter {
+	f.Graph = graph
+	return f
+}
+
+// graphEncoder writes the graph of the results in one of the graph formats
+type graphEncoder struct {
+	endEncoder
+	w     io.Writer
+	write func(io.Writer, *Graph) error
+}
+
+// newGraphMLEncoder creates a GraphML encoder
+func newGraphMLEncoder(w io.Writer, _ Options) Encoder {
+	return &graphEncoder{w: w, write: WriteGraphML}
+}
+
+// newGEXFEncoder creates a GEXF encoder
+func newGEXFEncoder(w io.Writer, _ Options) Encoder {
+	return &graphEncoder{w: w, write: WriteGEXF}
+}
+
+// newDOTEncoder creates a Graphviz DOT encoder
+func newDOTEncoder(w io.Writer, _ Options) Encoder {
+	return &graphEncoder{w: w, write: WriteDOT}
+}
+
+// End writes the graph
+func (e *graphEncoder) End(summary Summary) error {
+	return e.write(e.w, summary.graph())
+}
+
+// graph returns the summary's graph or, without one, the graph of the
+// results and the analysis of the first result's username
+func (s Summary) graph() *Graph {
+	if s.Graph != nil {
+		return s.Graph
+	}
+
+	byUsername := make(map[string][]Result)
+	for _, result := range s.Results {
+		byUsername[result.Username] = append(byUsername[result.Username], result)
+	}
+	if s.Analysis == nil || len(s.Results) == 0 {
+		return NewGraph(byUsername, nil)
+	}
+
+	graph := NewGraph(byUsername, s.Analysis.Links)
+	graph.SetConfidence(s.Results[0].Username, s.Analysis.Confidence)
+	return graph
+}
+
+// usernameNodeID returns the node ID of a username
+func usernameNodeID(username string) string {
+	return "username:" + username
+}
+
+// siteNodeID returns the node ID of a site
+func siteNodeID(site string) string {
+	return "site:" + site
+}
+
+// profileNodeID returns the node ID of a profile
+func profileNodeID(site, username string) string {
+	return "profile:" + site + "/" + username
+}
+
+// profileLabel returns the label of a profile node
+func profileLabel(site, username string) string {
+	if username == "" {
+		return site
+	}
+	return site + "/" + username
+}
+
+// siteURL returns the scheme and host of a profile URL
+func siteURL(profileURL string) string {
+	scheme, rest, ok := strings.Cut(profileURL, "://")
+	if !ok {
+		return ""
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host
+}
+
+// graphAttribute is a node attribute written to the graph formats
+type graphAttribute struct {
+	name     string
+	kind     string // GraphML type; GEXF and DOT derive theirs from it
+	value    func(GraphNode) string
+	optional func(GraphNode) bool
+}
+
+// nodeAttributes are the attributes written for every node, in order
+var nodeAttributes = []graphAttribute{
+	{name: "type", kind: "string", value: func(n GraphNode) string { return n.Type }},
+	{name: "platform", kind: "string", value: func(n GraphNode) string { return n.Platform }},
+	{name: "url", kind: "string", value: func(n GraphNode) string { return n.URL }},
+	{name: "confidence", kind: "double", value: func(n GraphNode) string { return formatFloat(n.Confidence) }},
+	{
+		name:     "follower_count",
+		kind:     "long",
+		value:    func(n GraphNode) string { return strconv.FormatInt(*n.Followers, 10) },
+		optional: func(n GraphNode) bool { return n.Followers == nil },
+	},
+}
+
+// skip reports whether the attribute is left out for a node
+func (a graphAttribute) skip(node GraphNode) bool {
+	if a.optional != nil {
+		return a.optional(node)
+	}
+	return a.value(node) == ""
+}
+
+// formatFloat formats a weight or confidence with up to four decimals
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
+
+// graphML is the GraphML document of a graph
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph as GraphML, readable by Gephi, yEd and
+// Cytoscape
+func WriteGraphML(w io.Writer, g *Graph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  []graphMLKey{{ID: "label", For: "node", Name: "label", Type: "string"}},
+		Graph: graphMLGraph{ID: "accio", EdgeDefault: "directed"},
+	}
+	for _, attribute := range nodeAttributes {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: attribute.name, For: "node", Name: attribute.name, Type: attribute.kind})
+	}
+	doc.Keys = append(doc.Keys,
+		graphMLKey{ID: "edge_type", For: "edge", Name: "type", Type: "string"},
+		graphMLKey{ID: "weight", For: "edge", Name: "weight", Type: "double"},
+	)
+
+	for _, node := range g.Nodes {
+		n := graphMLNode{ID: node.ID, Data: []graphMLData{{Key: "label", Value: node.Label}}}
+		for _, attribute := range nodeAttributes {
+			if !attribute.skip(node) {
+				n.Data = append(n.Data, graphMLData{Key: attribute.name, Value: attribute.value(node)})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+	}
+	for i, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     "e" + strconv.Itoa(i),
+			Source: edge.Source,
+			Target: edge.Target,
+			Data: []graphMLData{
+				{Key: "edge_type", Value: edge.Type},
+				{Key: "weight", Value: formatFloat(edge.Weight)},
+			},
+		})
+	}
+
+	return writeXML(w, doc)
+}
+
+// gexf is the GEXF 1.3 document of a graph
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	Creator     string `xml:"creator"`
+	Description string `xml:"description"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue,omitempty"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Label     string         `xml:"label,attr"`
+	Weight    string         `xml:"weight,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// WriteGEXF writes the graph as GEXF 1.3, Gephi's native format
+func WriteGEXF(w io.Writer, g *Graph) error {
+	nodeClass := gexfAttributes{Class: "node"}
+	for _, attribute := range nodeAttributes {
+		nodeClass.Attributes = append(nodeClass.Attributes, gexfAttribute{ID: attribute.name, Title: attribute.name, Type: attribute.kind})
+	}
+	edgeClass := gexfAttributes{Class: "edge", Attributes: []gexfAttribute{{ID: "type", Title: "type", Type: "string"}}}
+
+	doc := gexf{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Meta:    gexfMeta{Creator: "accio", Description: "Usernames, sites and profiles found by accio"},
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes:      []gexfAttributes{nodeClass, edgeClass},
+		},
+	}
+
+	for _, node := range g.Nodes {
+		n := gexfNode{ID: node.ID, Label: node.Label}
+		for _, attribute := range nodeAttributes {
+			if !attribute.skip(node) {
+				n.AttValues = append(n.AttValues, gexfAttValue{For: attribute.name, Value: attribute.value(node)})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+	}
+	for i, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:        strconv.Itoa(i),
+			Source:    edge.Source,
+			Target:    edge.Target,
+			Label:     edge.Type,
+			Weight:    formatFloat(edge.Weight),
+			AttValues: []gexfAttValue{{For: "type", Value: edge.Type}},
+		})
+	}
+
+	return writeXML(w, doc)
+}
+
+// writeXML writes an indented XML document with its declaration
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// dotShapes are the Graphviz shapes of the node types
+var dotShapes = map[string]string{
+	NodeUsername: "ellipse",
+	NodeSite:     "box",
+	NodeProfile:  "note",
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language. Node attributes
+// are written as DOT attributes, which Graphviz ignores and Gephi imports.
+func WriteDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph accio {\n")
+	b.WriteString("  rankdir=LR;\n")
+
+	for _, node := range g.Nodes {
+		attributes := []string{"label=" + dotQuote(node.Label), "shape=" + dotShapes[node.Type]}
+		for _, attribute := range nodeAttributes {
+			if attribute.skip(node) {
+				continue
+			}
+			value := attribute.value(node)
+			if attribute.kind == "string" {
+				value = dotQuote(value)
+			}
+			attributes = append(attributes, attribute.name+"="+value)
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(node.ID), strings.Join(attributes, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [type=%s, weight=%s];\n", dotQuote(edge.Source), dotQuote(edge.Target), dotQuote(edge.Type), formatFloat(edge.Weight))
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote quotes a DOT identifier
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,130 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func testGraph() *Graph {
+	graph := NewGraph(map[string][]Result{
+		"johndoe": {
+			{Site: "GitHub", URL: "https://github.com/johndoe", Exists: true, Profile: &Profile{Followers: 42}},
+			{Site: "Twitter", URL: "https://twitter.com/johndoe", Exists: true},
+			{Site: "Reddit", URL: "https://www.reddit.com/user/johndoe", Exists: false},
+		},
+		"jdoe": {
+			{Site: "Twitter", URL: "https://twitter.com/jdoe", Exists: true},
+		},
+	}, []Link{
+		{Type: LinksTo, FromSite: "GitHub", FromUsername: "johndoe", ToSite: "Twitter", ToUsername: "jdoe"},
+		{Type: LinksTo, FromSite: "GitHub", FromUsername: "johndoe", ToSite: "Reddit", ToUsername: "johndoe"},
+	})
+	graph.SetConfidence("johndoe", 0.8)
+	return graph
+}
+
+func TestNewGraph(t *testing.T) {
+	graph := testGraph()
+
+	var ids []string
+	for _, node := range graph.Nodes {
+		ids = append(ids, node.ID)
+	}
+	want := []string{
+		"username:jdoe", "username:johndoe",
+		"site:GitHub", "site:Twitter",
+		"profile:GitHub/johndoe", "profile:Twitter/jdoe", "profile:Twitter/johndoe",
+	}
+	if strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected nodes %v, got %v", want, ids)
+	}
+
+	var hasProfile, onSite, linksTo int
+	for _, edge := range graph.Edges {
+		switch edge.Type {
+		case HasProfile:
+			hasProfile++
+			if edge.Source == "username:johndoe" && edge.Weight != 0.8 {
+				t.Errorf("Expected has-profile weight 0.8, got %v", edge.Weight)
+			}
+		case OnSite:
+			onSite++
+		case LinksTo:
+			linksTo++
+		}
+	}
+	// The link to the Reddit profile that was not found is left out
+	if hasProfile != 3 || onSite != 3 || linksTo != 1 {
+		t.Errorf("Expected 3 has-profile, 3 on-site and 1 links-to edges, got %d, %d and %d", hasProfile, onSite, linksTo)
+	}
+
+	for _, node := range graph.Nodes {
+		if node.ID == "profile:GitHub/johndoe" && (node.Followers == nil || *node.Followers != 42 || node.Confidence != 0.8) {
+			t.Errorf("Expected GitHub profile with 42 followers and confidence 0.8, got %+v", node)
+		}
+		if node.ID == "site:GitHub" && node.URL != "https://github.com" {
+			t.Errorf("Expected site URL https://github.com, got %s", node.URL)
+		}
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, testGraph()); err != nil {
+		t.Fatalf("Failed to write GraphML: %v", err)
+	}
+
+	var doc graphML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to parse GraphML: %v", err)
+	}
+	if len(doc.Graph.Nodes) != 7 || len(doc.Graph.Edges) != 7 {
+		t.Errorf("Expected 7 nodes and 7 edges, got %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	if !strings.Contains(buf.String(), `<data key="follower_count">42</data>`) {
+		t.Errorf("Expected follower count in GraphML, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteGEXF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGEXF(&buf, testGraph()); err != nil {
+		t.Fatalf("Failed to write GEXF: %v", err)
+	}
+
+	var doc gexf
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to parse GEXF: %v", err)
+	}
+	if len(doc.Graph.Nodes) != 7 || len(doc.Graph.Edges) != 7 {
+		t.Errorf("Expected 7 nodes and 7 edges, got %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	if !strings.Contains(buf.String(), `<attvalue for="confidence" value="0.8"></attvalue>`) {
+		t.Errorf("Expected confidence in GEXF, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, testGraph()); err != nil {
+		t.Fatalf("Failed to write DOT: %v", err)
+	}
+
+	dot := buf.String()
+	for _, want := range []string{
+		"digraph accio {",
+		`"profile:GitHub/johndoe" [label="GitHub/johndoe", shape=note, type="profile", platform="GitHub", url="https://github.com/johndoe", confidence=0.8, follower_count=42];`,
+		`"username:johndoe" -> "profile:GitHub/johndoe" [type="has-profile", weight=0.8];`,
+		`"profile:GitHub/johndoe" -> "profile:Twitter/jdoe" [type="links-to", weight=1];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Expected DOT to contain %s, got:\n%s", want, dot)
+		}
+	}
+
+	if got := dotQuote(`say "hi" \ bye`); got != `"say \"hi\" \\ bye"` {
+		t.Errorf("Unexpected quoting: %s", got)
+	}
+}
@@ -0,0 +1,221 @@
+package output
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/accio/internal/checker"
+	"github.com/accio/internal/sites"
+)
+
+// Statuses of a result in the HTML report, in the order they are grouped
+const (
+	StatusFound    = "found"
+	StatusError    = "error"
+	StatusNotFound = "not_found"
+)
+
+// statusLabels are the headings of the status groups
+var statusLabels = map[string]string{
+	StatusFound:    "Found",
+	StatusError:    "Error",
+	StatusNotFound: "Not Found",
+}
+
+//go:embed report.html
+var reportTemplateText string
+
+// reportTemplate renders the HTML report
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"signed":  func(f float64) string { return fmt.Sprintf("%+.2f", f) },
+}).Parse(reportTemplateText))
+
+// AvatarSource returns the picture of a profile as PNG or JPEG data, or nil
+// if there is none
+type AvatarSource func(site, username string) []byte
+
+// WithStats sets the check statistics shown in the HTML report header
+func (f *Formatter) WithStats(stats checker.CheckStats) *Formatter {
+	f.Stats = &stats
+	return f
+}
+
+// WithAvatars sets where the HTML report gets profile pictures from
+func (f *Formatter) WithAvatars(avatars AvatarSource) *Formatter {
+	f.Avatars = avatars
+	return f
+}
+
+// htmlReport is the data of the HTML report template
+type htmlReport struct {
+	Title      string
+	Generated  string
+	Stats      htmlStats
+	Categories []htmlCategory
+	Groups     []htmlGroup
+	Analysis   *Analysis
+}
+
+// htmlStats is the summary in the report header
+type htmlStats struct {
+	Total    int
+	Found    int
+	NotFound int
+	Errors   int
+	Duration string
+}
+
+// htmlCategory is the number of results and profiles found in a category
+type htmlCategory struct {
+	Name  string
+	Total int
+	Found int
+}
+
+// htmlGroup is the results with one status
+type htmlGroup struct {
+	Status string
+	Label  string
+	Rows   []htmlRow
+}
+
+// htmlRow is one result in the report table
+type htmlRow struct {
+	Site     string
+	Category string
+	Username string
+	URL      string
+	Status   string
+	Error    string
+	Avatar   template.URL // data: URL of the profile picture, if any
+}
+
+// htmlEncoder writes the results as a single HTML page
+type htmlEncoder struct {
+	endEncoder
+	w       io.Writer
+	avatars AvatarSource
+}
+
+// newHTMLEncoder creates an HTML report encoder
+func newHTMLEncoder(w io.Writer, options Options) Encoder {
+	return &htmlEncoder{w: w, avatars: options.Avatars}
+}
+
+// End writes the results as a single HTML page with inline styles and
+// scripts, grouped by status and then by category
+func (e *htmlEncoder) End(summary Summary) error {
+	results := summary.Results
+	report := htmlReport{
+		Title:     "Accio Results",
+		Generated: time.Now().Format(time.RFC3339),
+		Analysis:  summary.Analysis,
+	}
+	if len(results) > 0 && results[0].Username != "" {
+		report.Title = "Accio Results for " + results[0].Username
+	}
+
+	groups := make(map[string][]htmlRow)
+	categories := make(map[string]*htmlCategory)
+	for _, result := range results {
+		row := htmlRow{
+			Site:     result.Site,
+			Category: sites.GetCategory(result.Site),
+			Username: result.Username,
+			URL:      result.URL,
+			Status:   resultStatus(result),
+		}
+		if result.Error != nil {
+			row.Error = result.Error.Error()
+		}
+		if row.Status == StatusFound && e.avatars != nil {
+			row.Avatar = avatarURL(e.avatars(result.Site, result.Username))
+		}
+		groups[row.Status] = append(groups[row.Status], row)
+
+		category, ok := categories[row.Category]
+		if !ok {
+			category = &htmlCategory{Name: row.Category}
+			categories[row.Category] = category
+		}
+		category.Total++
+		if row.Status == StatusFound {
+			category.Found++
+		}
+
+		switch row.Status {
+		case StatusFound:
+			report.Stats.Found++
+		case StatusError:
+			report.Stats.Errors++
+		default:
+			report.Stats.NotFound++
+		}
+	}
+	report.Stats.Total = len(results)
+
+	if stats := summary.Stats; stats != nil && !stats.StartTime.IsZero() {
+		// Check statistics count every attempt, including retried errors
+		report.Stats = htmlStats{
+			Total:    stats.Total,
+			Found:    stats.Found,
+			NotFound: stats.NotFound,
+			Errors:   stats.Errors,
+			Duration: stats.EndTime.Sub(stats.StartTime).Round(time.Millisecond).String(),
+		}
+	}
+
+	for _, status := range []string{StatusFound, StatusError, StatusNotFound} {
+		rows := groups[status]
+		if len(rows) == 0 {
+			continue
+		}
+		sort.SliceStable(rows, func(i, j int) bool {
+			if rows[i].Category != rows[j].Category {
+				return rows[i].Category < rows[j].Category
+			}
+			return rows[i].Site < rows[j].Site
+		})
+		report.Groups = append(report.Groups, htmlGroup{Status: status, Label: statusLabels[status], Rows: rows})
+	}
+
+	for _, category := range categories {
+		report.Categories = append(report.Categories, *category)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		return report.Categories[i].Name < report.Categories[j].Name
+	})
+
+	return reportTemplate.Execute(e.w, report)
+}
+
+// resultStatus returns the status of a result in the HTML report
+func resultStatus(result Result) string {
+	switch {
+	case result.Exists:
+		return StatusFound
+	case result.Error != nil:
+		return StatusError
+	default:
+		return StatusNotFound
+	}
+}
+
+// avatarURL returns a data: URL embedding a PNG or JPEG picture
+func avatarURL(data []byte) template.URL {
+	if len(data) == 0 {
+		return ""
+	}
+
+	mediaType := "image/png"
+	if len(data) > 2 && data[0] == 0xff && data[1] == 0xd8 {
+		mediaType = "image/jpeg"
+	}
+	return template.URL("data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data))
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/accio/internal/checker"
+)
+
+func TestWriteHTML(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	formatter := NewFormatter(false).
+		WithFormat(FormatHTML).
+		WithStats(checker.CheckStats{Total: 4, Found: 2, NotFound: 1, Errors: 1, StartTime: start, EndTime: start.Add(1500 * time.Millisecond)}).
+		WithAvatars(func(site, username string) []byte {
+			if site == "GitHub" {
+				return []byte{0x89, 'P', 'N', 'G'}
+			}
+			return nil
+		}).
+		WithAnalysis(&Analysis{
+			Confidence: 0.53,
+			Evidence:   []Evidence{{Signal: "baseline", Weight: -4.5, Reason: "Baseline <before> any evidence"}},
+			Links:      []Link{{Type: LinksTo, FromSite: "GitHub", FromUsername: "johndoe", ToSite: "Twitter", ToUsername: "johndoe", ToURL: "https://twitter.com/johndoe", Source: "page"}},
+		})
+
+	var buf bytes.Buffer
+	formatter.WithWriter(&buf).PrintSummary([]Result{
+		{Username: "johndoe", Site: "Twitter", URL: "https://twitter.com/johndoe", Exists: true},
+		{Username: "johndoe", Site: "GitHub", URL: "https://github.com/johndoe", Exists: true},
+		{Username: "johndoe", Site: "Reddit", URL: "https://www.reddit.com/user/johndoe"},
+		{Username: "johndoe", Site: "Steam", URL: "https://steamcommunity.com/id/johndoe", Error: errors.New("timeout")},
+	})
+	page := buf.String()
+
+	for _, want := range []string{
+		"<title>Accio Results for johndoe</title>",
+		"<strong>1.5s</strong>Duration",
+		"<strong>53%</strong>Confidence",
+		`<img src="data:image/png;base64,iVBORw==" alt="">`,
+		`<option value="development">development</option>`,
+		"Baseline &lt;before&gt; any evidence",
+		"-4.50",
+		"GitHub/johndoe links to",
+		`<div class="error-message">timeout</div>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected HTML to contain %s", want)
+		}
+	}
+
+	// Results are grouped by status, found first, and by category within
+	found := strings.Index(page, `<tbody data-status="found">`)
+	failed := strings.Index(page, `<tbody data-status="error">`)
+	notFound := strings.Index(page, `<tbody data-status="not_found">`)
+	if found < 0 || failed < found || notFound < failed {
+		t.Errorf("Expected found, error and not found groups in order, got %d, %d and %d", found, failed, notFound)
+	}
+	if github, twitter := strings.Index(page, "https://github.com/johndoe"), strings.Index(page, "https://twitter.com/johndoe"); github > twitter {
+		t.Error("Expected GitHub (development) before Twitter (social)")
+	}
+
+	// The report is self-contained
+	for _, external := range []string{`<link `, `<script src`, `src="http`} {
+		if strings.Contains(page, external) {
+			t.Errorf("Expected no external assets, found %s", external)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Record types of NDJSON output
+const (
+	RecordResult  = "result"
+	RecordSummary = "summary"
+)
+
+// ndjsonResult is the NDJSON record of one result
+type ndjsonResult struct {
+	Type     string   `json:"type"`
+	Username string   `json:"username,omitempty"`
+	Site     string   `json:"site"`
+	URL      string   `json:"url"`
+	Exists   bool     `json:"exists"`
+	Error    string   `json:"error,omitempty"`
+	Profile  *Profile `json:"profile,omitempty"`
+}
+
+// ndjsonSummary is the final NDJSON record, with the totals and the
+// analysis if there is one
+type ndjsonSummary struct {
+	Type  string `json:"type"`
+	Found int    `json:"found"`
+	Total int    `json:"total"`
+	Time  string `json:"time"`
+	*Analysis
+}
+
+// ndjsonEncoder writes a JSON line per result as it arrives and a summary line
+type ndjsonEncoder struct {
+	w io.Writer
+}
+
+// newNDJSONEncoder creates an NDJSON encoder
+func newNDJSONEncoder(w io.Writer, _ Options) Encoder {
+	return &ndjsonEncoder{w: w}
+}
+
+// Begin writes nothing
+func (e *ndjsonEncoder) Begin() error {
+	return nil
+}
+
+// WriteResult writes a result as one line of JSON
+func (e *ndjsonEncoder) WriteResult(result Result) error {
+	record := ndjsonResult{
+		Type:     RecordResult,
+		Username: result.Username,
+		Site:     result.Site,
+		URL:      result.URL,
+		Exists:   result.Exists,
+		Profile:  result.Profile,
+	}
+	if result.Error != nil {
+		record.Error = result.Error.Error()
+	}
+	return writeNDJSONLine(e.w, record)
+}
+
+// End writes the summary record as one line of JSON
+func (e *ndjsonEncoder) End(summary Summary) error {
+	return writeNDJSONLine(e.w, ndjsonSummary{
+		Type:     RecordSummary,
+		Found:    found(summary.Results),
+		Total:    len(summary.Results),
+		Time:     time.Now().Format(time.RFC3339),
+		Analysis: summary.Analysis,
+	})
+}
+
+// writeNDJSONLine writes a record and a newline in a single write, so lines
+// from concurrent writers never interleave
+func writeNDJSONLine(w io.Writer, record any) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestFormatterNDJSON(t *testing.T) {
+	// Redirect stdout to capture output
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	formatter := NewFormatter(false).
+		WithFormat(FormatNDJSON).
+		WithAnalysis(&Analysis{Confidence: 0.5, Evidence: []Evidence{{Signal: "baseline", Weight: -4.5, Reason: "Baseline before any evidence"}}})
+	results := []Result{
+		{Username: "johndoe", Site: "GitHub", URL: "https://github.com/johndoe", Exists: true},
+		{Username: "johndoe", Site: "Reddit", URL: "https://www.reddit.com/user/johndoe", Error: errors.New("timeout")},
+	}
+
+	// Each result is printed as it arrives, even when not found
+	for _, result := range results {
+		formatter.PrintResult(result)
+	}
+	formatter.PrintSummary(results)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	var records []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Line is not JSON: %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected 2 result records and a summary, got %d", len(records))
+	}
+	if records[0]["type"] != RecordResult || records[0]["username"] != "johndoe" || records[0]["exists"] != true {
+		t.Errorf("Unexpected first record: %v", records[0])
+	}
+	if records[1]["error"] != "timeout" {
+		t.Errorf("Expected error in second record, got %v", records[1])
+	}
+	summary := records[2]
+	if summary["type"] != RecordSummary || summary["found"] != float64(1) || summary["total"] != float64(2) || summary["confidence"] != 0.5 {
+		t.Errorf("Unexpected summary record: %v", summary)
+	}
+	if _, ok := summary["evidence"]; !ok {
+		t.Errorf("Expected evidence in summary record, got %v", summary)
+	}
+}
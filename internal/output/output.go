@@ -3,19 +3,37 @@ package output
 import (
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"strconv"
+	"text/template"
 	"time"
+
+	"github.com/accio/internal/checker"
 )
 
 // Result represents the result of checking a username on a site
 type Result struct {
+	Username string `json:"username,omitempty"` // Username that was checked
 	Site     string `json:"site"`
 	URL      string `json:"url"`
 	Exists   bool   `json:"exists"`
 	Error    error  `json:"-"`
 	Response string `json:"-"`
+
+	// Profile is fetched from the platform API for found results when
+	// requested, and nil otherwise
+	Profile *Profile `json:"profile,omitempty"`
+}
+
+// Profile is what a platform API returned for a found profile
+type Profile struct {
+	Name      string `json:"name,omitempty"`
+	Bio       string `json:"bio,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	Followers int64  `json:"followers"`
 }
 
 // MarshalJSON custom JSON marshaling to handle the error field
@@ -34,25 +52,72 @@ func (r Result) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// UnmarshalJSON custom JSON unmarshaling to restore the error field
+func (r *Result) UnmarshalJSON(data []byte) error {
+	type Alias Result
+	var aux struct {
+		Alias
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*r = Result(aux.Alias)
+	if aux.Error != "" {
+		r.Error = errors.New(aux.Error)
+	}
+	return nil
+}
+
 // FormatType represents the output format type
 type FormatType string
 
 const (
 	// FormatText is plain text output
 	FormatText FormatType = "text"
-	// FormatJSON is JSON output
+	// FormatJSON is JSON output, the bare array of results
 	FormatJSON FormatType = "json"
+	// FormatJSONReport is a JSON object with the results and the confidence
+	// analysis of the found profiles
+	FormatJSONReport FormatType = "json-report"
+	// FormatNDJSON is newline-delimited JSON, one record per result as it
+	// arrives followed by a summary record
+	FormatNDJSON FormatType = "ndjson"
 	// FormatCSV is CSV output
 	FormatCSV FormatType = "csv"
 	// FormatMarkdown is Markdown output
 	FormatMarkdown FormatType = "markdown"
+	// FormatGraphML is a GraphML graph of the found profiles
+	FormatGraphML FormatType = "graphml"
+	// FormatGEXF is a GEXF graph of the found profiles
+	FormatGEXF FormatType = "gexf"
+	// FormatDOT is a Graphviz DOT graph of the found profiles
+	FormatDOT FormatType = "dot"
+	// FormatHTML is a self-contained HTML report
+	FormatHTML FormatType = "html"
+	// FormatSTIX is a STIX 2.1 bundle of the found profiles
+	FormatSTIX FormatType = "stix"
+	// FormatTemplate is the output of a user-defined Go text/template
+	FormatTemplate FormatType = "template"
 )
 
-// Formatter handles the formatting and output of results
+// Formatter handles the formatting and output of results. Results are
+// printed to Writer as they arrive with an encoder of Format, and saved to
+// files with an encoder chosen by the file extension.
 type Formatter struct {
-	Verbose bool
-	Format  FormatType
-	Color   bool
+	Verbose  bool
+	Format   FormatType
+	Color    bool
+	Analysis *Analysis // Optional confidence analysis of the found profiles
+	Graph    *Graph    // Optional graph exported by the graph formats
+
+	Stats    *checker.CheckStats // Optional check statistics for the HTML report
+	Avatars  AvatarSource        // Optional profile pictures for the HTML report
+	Template *template.Template  // Template of the template format
+
+	Writer  io.Writer // Where results are printed; os.Stdout if nil
+	encoder Encoder   // Encoder of Writer, created by the first print
 }
 
 // NewFormatter creates a new Formatter instance
@@ -76,194 +141,260 @@ func (f *Formatter) WithColor(color bool) *Formatter {
 	return f
 }
 
-// PrintResult prints a single result
-func (f *Formatter) PrintResult(result Result) {
-	switch f.Format {
-	case FormatJSON:
-		// For JSON format, we don't print individual results
-		// They will be collected and printed at the end
-		return
-	case FormatCSV:
-		// For CSV format, we don't print individual results
-		// They will be collected and printed at the end
-		return
-	case FormatMarkdown:
-		if result.Exists {
-			fmt.Printf("- [x] %s: [%s](%s)\n", result.Site, result.Site, result.URL)
-		} else if f.Verbose {
-			fmt.Printf("- [ ] %s\n", result.Site)
-		}
-	default: // FormatText
-		if result.Exists {
-			if f.Color {
-				fmt.Printf("\033[32m[+]\033[0m %s: %s\n", result.Site, result.URL)
-			} else {
-				fmt.Printf("[+] %s: %s\n", result.Site, result.URL)
-			}
-		} else if f.Verbose {
-			if f.Color {
-				fmt.Printf("\033[31m[-]\033[0m %s: Not Found\n", result.Site)
-			} else {
-				fmt.Printf("[-] %s: Not Found\n", result.Site)
-			}
-		}
+// WithWriter sets where results are printed
+func (f *Formatter) WithWriter(w io.Writer) *Formatter {
+	f.Writer = w
+	return f
+}
 
-		if result.Error != nil && f.Verbose {
-			if f.Color {
-				fmt.Printf("    \033[33mError: %v\033[0m\n", result.Error)
-			} else {
-				fmt.Printf("    Error: %v\n", result.Error)
-			}
-		}
+// PrintResult prints a single result as it arrives. Formats that are not
+// streamed print nothing until PrintSummary.
+func (f *Formatter) PrintResult(result Result) {
+	encoder, err := f.stream()
+	if err == nil {
+		err = encoder.WriteResult(result)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s output: %v\n", f.Format, err)
 	}
 }
 
 // PrintSummary prints a summary of all results
 func (f *Formatter) PrintSummary(results []Result) {
-	switch f.Format {
-	case FormatJSON:
-		// Print all results as JSON
-		jsonData, err := json.MarshalIndent(results, "", "  ")
-		if err != nil {
-			fmt.Printf("Error generating JSON: %v\n", err)
-			return
-		}
-		fmt.Println(string(jsonData))
-		return
-	case FormatCSV:
-		// Print all results as CSV to stdout
-		writer := csv.NewWriter(os.Stdout)
-		writer.Write([]string{"Site", "URL", "Exists", "Error"})
-		for _, result := range results {
-			var errStr string
-			if result.Error != nil {
-				errStr = result.Error.Error()
-			}
-			writer.Write([]string{
-				result.Site,
-				result.URL,
-				fmt.Sprintf("%t", result.Exists),
-				errStr,
-			})
-		}
-		writer.Flush()
-		return
-	case FormatMarkdown:
-		var found int
-		for _, result := range results {
-			if result.Exists {
-				found++
-			}
-		}
-		fmt.Printf("\n## Summary\n\n")
-		fmt.Printf("- **Found**: %d\n", found)
-		fmt.Printf("- **Total**: %d\n", len(results))
-		fmt.Printf("- **Time**: %s\n", time.Now().Format(time.RFC3339))
-		return
-	default: // FormatText
-		var found int
-		for _, result := range results {
-			if result.Exists {
-				found++
-			}
-		}
-
-		if f.Color {
-			fmt.Printf("\n\033[1mFound %d results out of %d sites\033[0m\n", found, len(results))
-		} else {
-			fmt.Printf("\nFound %d results out of %d sites\n", found, len(results))
-		}
+	encoder, err := f.stream()
+	if err == nil {
+		err = encoder.End(f.summary(results))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s output: %v\n", f.Format, err)
 	}
 }
 
-// SaveToFile saves results to a file
+// SaveToFile saves results to a file, in the format registered for the
+// file extension or else in the formatter's format. With a template, files
+// are always saved with the template, whatever their extension.
 func (f *Formatter) SaveToFile(results []Result, filename string) error {
+	format, ok := FormatForFile(filename)
+	if f.Template != nil {
+		format = FormatTemplate
+	} else if !ok {
+		format = f.format()
+	}
+
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	// Determine format based on file extension
-	format := f.Format
-	if strings.HasSuffix(filename, ".json") {
-		format = FormatJSON
-	} else if strings.HasSuffix(filename, ".csv") {
-		format = FormatCSV
-	} else if strings.HasSuffix(filename, ".md") {
-		format = FormatMarkdown
+	options := f.options()
+	options.Color = false
+	if err := Encode(format, file, options, f.summary(results)); err != nil {
+		return err
 	}
+	return file.Close()
+}
+
+// stream returns the encoder of the formatter's writer, beginning it on
+// first use
+func (f *Formatter) stream() (Encoder, error) {
+	if f.encoder != nil {
+		return f.encoder, nil
+	}
+
+	w := f.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	encoder, err := NewEncoder(f.format(), w, f.options())
+	if err != nil {
+		return nil, err
+	}
+	if err := encoder.Begin(); err != nil {
+		return nil, err
+	}
+	f.encoder = encoder
+	return encoder, nil
+}
+
+// format returns the formatter's format, or text if it is not registered
+func (f *Formatter) format() FormatType {
+	if _, ok := Lookup(f.Format); ok {
+		return f.Format
+	}
+	return FormatText
+}
+
+// options returns the encoder options of the formatter
+func (f *Formatter) options() Options {
+	return Options{Verbose: f.Verbose, Color: f.Color, Avatars: f.Avatars, Template: f.Template}
+}
 
-	switch format {
-	case FormatJSON:
-		// Save as JSON
-		encoder := json.NewEncoder(file)
-		encoder.SetIndent("", "  ")
-		return encoder.Encode(results)
-	case FormatCSV:
-		// Save as CSV
-		writer := csv.NewWriter(file)
-		writer.Write([]string{"Site", "URL", "Exists", "Error"})
-		for _, result := range results {
-			var errStr string
-			if result.Error != nil {
-				errStr = result.Error.Error()
-			}
-			writer.Write([]string{
-				result.Site,
-				result.URL,
-				fmt.Sprintf("%t", result.Exists),
-				errStr,
-			})
+// summary returns the summary of the results with the formatter's analysis,
+// graph and statistics
+func (f *Formatter) summary(results []Result) Summary {
+	return Summary{Results: results, Analysis: f.Analysis, Graph: f.Graph, Stats: f.Stats}
+}
+
+// textEncoder writes results as plain text, optionally colored
+type textEncoder struct {
+	w       io.Writer
+	options Options
+}
+
+// newTextEncoder creates a text encoder
+func newTextEncoder(w io.Writer, options Options) Encoder {
+	return &textEncoder{w: w, options: options}
+}
+
+// Begin writes nothing
+func (e *textEncoder) Begin() error {
+	return nil
+}
+
+// WriteResult writes a found result, or any result when verbose
+func (e *textEncoder) WriteResult(result Result) error {
+	color := e.options.Color
+	if result.Exists {
+		if color {
+			fmt.Fprintf(e.w, "\033[32m[+]\033[0m %s: %s\n", result.Site, result.URL)
+		} else {
+			fmt.Fprintf(e.w, "[+] %s: %s\n", result.Site, result.URL)
 		}
-		writer.Flush()
-		return writer.Error()
-	case FormatMarkdown:
-		// Save as Markdown
-		fmt.Fprintf(file, "# Accio Results\n\n")
-		fmt.Fprintf(file, "Username search results generated on %s\n\n", time.Now().Format(time.RFC3339))
-		fmt.Fprintf(file, "## Found Accounts\n\n")
-
-		for _, result := range results {
-			if result.Exists {
-				fmt.Fprintf(file, "- [%s](%s)\n", result.Site, result.URL)
-			}
+	} else if e.options.Verbose {
+		if color {
+			fmt.Fprintf(e.w, "\033[31m[-]\033[0m %s: Not Found\n", result.Site)
+		} else {
+			fmt.Fprintf(e.w, "[-] %s: Not Found\n", result.Site)
 		}
+	}
 
-		var found int
-		for _, result := range results {
-			if result.Exists {
-				found++
-			}
+	if result.Error != nil && e.options.Verbose {
+		if color {
+			fmt.Fprintf(e.w, "    \033[33mError: %v\033[0m\n", result.Error)
+		} else {
+			fmt.Fprintf(e.w, "    Error: %v\n", result.Error)
 		}
+	}
+	return nil
+}
 
-		fmt.Fprintf(file, "\n## Summary\n\n")
-		fmt.Fprintf(file, "- **Found**: %d\n", found)
-		fmt.Fprintf(file, "- **Total**: %d\n", len(results))
-
-		return nil
-	default: // FormatText
-		// Save as plain text
-		for _, result := range results {
-			if result.Exists {
-				fmt.Fprintf(file, "[+] %s: %s\n", result.Site, result.URL)
-			} else if f.Verbose {
-				fmt.Fprintf(file, "[-] %s: Not Found\n", result.Site)
-			}
-
-			if result.Error != nil && f.Verbose {
-				fmt.Fprintf(file, "    Error: %v\n", result.Error)
-			}
-		}
+// End writes the number of results found and the analysis
+func (e *textEncoder) End(summary Summary) error {
+	if e.options.Color {
+		fmt.Fprintf(e.w, "\n\033[1mFound %d results out of %d sites\033[0m\n", found(summary.Results), len(summary.Results))
+	} else {
+		fmt.Fprintf(e.w, "\nFound %d results out of %d sites\n", found(summary.Results), len(summary.Results))
+	}
+	if summary.Analysis != nil {
+		writeAnalysisText(e.w, summary.Analysis, e.options.Color)
+	}
+	return nil
+}
 
-		var found int
-		for _, result := range results {
-			if result.Exists {
-				found++
-			}
-		}
+// jsonEncoder writes all results as one indented JSON value
+type jsonEncoder struct {
+	endEncoder
+	w      io.Writer
+	report bool // Write an object with the analysis instead of the bare results
+}
+
+// newJSONEncoder creates a JSON encoder
+func newJSONEncoder(w io.Writer, _ Options) Encoder {
+	return &jsonEncoder{w: w}
+}
+
+// newJSONReportEncoder creates a JSON report encoder
+func newJSONReportEncoder(w io.Writer, _ Options) Encoder {
+	return &jsonEncoder{w: w, report: true}
+}
+
+// End writes the results, or the report with the analysis
+func (e *jsonEncoder) End(summary Summary) error {
+	var value any = summary.Results
+	if e.report {
+		value = summary.jsonReport()
+	}
+
+	encoder := json.NewEncoder(e.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// csvEncoder writes a CSV row per result as it arrives
+type csvEncoder struct {
+	writer *csv.Writer
+}
+
+// newCSVEncoder creates a CSV encoder
+func newCSVEncoder(w io.Writer, _ Options) Encoder {
+	return &csvEncoder{writer: csv.NewWriter(w)}
+}
+
+// Begin writes the header row
+func (e *csvEncoder) Begin() error {
+	e.writer.Write([]string{"Site", "URL", "Exists", "Error"})
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// WriteResult writes the row of a result
+func (e *csvEncoder) WriteResult(result Result) error {
+	var errStr string
+	if result.Error != nil {
+		errStr = result.Error.Error()
+	}
+	e.writer.Write([]string{
+		result.Site,
+		result.URL,
+		strconv.FormatBool(result.Exists),
+		errStr,
+	})
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// End writes nothing; CSV has no summary
+func (e *csvEncoder) End(Summary) error {
+	return nil
+}
+
+// markdownEncoder writes results as a Markdown document
+type markdownEncoder struct {
+	w       io.Writer
+	options Options
+}
+
+// newMarkdownEncoder creates a Markdown encoder
+func newMarkdownEncoder(w io.Writer, options Options) Encoder {
+	return &markdownEncoder{w: w, options: options}
+}
+
+// Begin writes the title
+func (e *markdownEncoder) Begin() error {
+	fmt.Fprintf(e.w, "# Accio Results\n\n")
+	fmt.Fprintf(e.w, "Username search results generated on %s\n\n", time.Now().Format(time.RFC3339))
+	_, err := fmt.Fprintf(e.w, "## Found Accounts\n\n")
+	return err
+}
+
+// WriteResult writes a found result as a link, or any result when verbose
+func (e *markdownEncoder) WriteResult(result Result) error {
+	if result.Exists {
+		fmt.Fprintf(e.w, "- [%s](%s)\n", result.Site, result.URL)
+	} else if e.options.Verbose {
+		fmt.Fprintf(e.w, "- %s: Not Found\n", result.Site)
+	}
+	return nil
+}
 
-		fmt.Fprintf(file, "\nFound %d results out of %d sites\n", found, len(results))
-		return nil
+// End writes the summary and the analysis
+func (e *markdownEncoder) End(summary Summary) error {
+	fmt.Fprintf(e.w, "\n## Summary\n\n")
+	fmt.Fprintf(e.w, "- **Found**: %d\n", found(summary.Results))
+	fmt.Fprintf(e.w, "- **Total**: %d\n", len(summary.Results))
+	if summary.Analysis != nil {
+		writeAnalysisMarkdown(e.w, summary.Analysis)
 	}
+	return nil
 }
@@ -142,3 +142,69 @@ func TestSaveToFile(t *testing.T) {
 		t.Errorf("Expected file to contain https://example.com/user1, got %s", content)
 	}
 }
+
+func TestSaveToFileWithAnalysis(t *testing.T) {
+	dir := t.TempDir()
+	results := []Result{{Site: "GitHub", URL: "https://github.com/johndoe", Exists: true}}
+	formatter := NewFormatter(false).WithAnalysis(&Analysis{
+		Confidence: 0.85,
+		Evidence: []Evidence{
+			{Signal: "match_count", Weight: 1.2, Reason: "Username found on 4 sites"},
+			{Signal: "platform", Weight: -0.4, Reason: "Profile on Pinterest"},
+		},
+	})
+
+	// JSON output stays the bare array of results
+	jsonFile := dir + "/results.json"
+	if err := formatter.SaveToFile(results, jsonFile); err != nil {
+		t.Fatalf("Failed to save JSON: %v", err)
+	}
+	data, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	var bare []Result
+	if err := json.Unmarshal(data, &bare); err != nil || len(bare) != 1 {
+		t.Errorf("Expected a bare array of results, got %s", data)
+	}
+
+	// The JSON report is an object holding the results and the analysis
+	reportFile := dir + "/results.report.json"
+	if err := formatter.SaveToFile(results, reportFile); err != nil {
+		t.Fatalf("Failed to save JSON report: %v", err)
+	}
+	data, err = os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	var report struct {
+		Results    []Result   `json:"results"`
+		Confidence float64    `json:"confidence"`
+		Evidence   []Evidence `json:"evidence"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Failed to unmarshal report: %v", err)
+	}
+	if len(report.Results) != 1 || report.Confidence != 0.85 || len(report.Evidence) != 2 {
+		t.Errorf("Unexpected JSON report: %s", data)
+	}
+
+	for name, want := range map[string][]string{
+		"results.txt": {"Confidence 85%", "+1.20  Username found on 4 sites", "-0.40  Profile on Pinterest"},
+		"results.md":  {"## Confidence", "| match_count | +1.20 | Username found on 4 sites |"},
+	} {
+		path := dir + "/" + name
+		if err := formatter.SaveToFile(results, path); err != nil {
+			t.Fatalf("Failed to save %s: %v", name, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+		for _, s := range want {
+			if !strings.Contains(string(data), s) {
+				t.Errorf("Expected %s to contain %q, got %s", name, s, data)
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// stixVersion is the STIX specification version of exported objects
+const stixVersion = "2.1"
+
+// stixTimestampFormat is the STIX timestamp format, in UTC with millisecond
+// precision
+const stixTimestampFormat = "2006-01-02T15:04:05.000Z"
+
+// stixCreated is the created timestamp of every exported object. Objects
+// have the same ID in every scan, so their creation time must not change
+// either; only modified follows the scan.
+var stixCreated = time.Unix(0, 0).UTC().Format(stixTimestampFormat)
+
+// stixSCONamespace is the namespace STIX 2.1 defines for the UUIDv5 IDs of
+// cyber observables, so other tools derive the same IDs for the same accounts
+var stixSCONamespace = uuid.MustParse("00abedb4-aa42-466c-9c01-fed23315a9b7")
+
+// stixNamespace is the namespace of the UUIDv5 IDs of the domain objects and
+// relationships accio exports
+var stixNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/accio"))
+
+// stixObject is a STIX object; fields are set by object type
+type stixObject struct {
+	Type          string `json:"type"`
+	SpecVersion   string `json:"spec_version"`
+	ID            string `json:"id"`
+	CreatedByRef  string `json:"created_by_ref,omitempty"`
+	Created       string `json:"created,omitempty"`
+	Modified      string `json:"modified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Confidence    *int   `json:"confidence,omitempty"`
+	IdentityClass string `json:"identity_class,omitempty"`
+
+	// user-account
+	AccountLogin  string `json:"account_login,omitempty"`
+	AccountType   string `json:"account_type,omitempty"`
+	ProfileURL    string `json:"x_accio_profile_url,omitempty"`
+	FollowerCount *int64 `json:"x_accio_follower_count,omitempty"`
+
+	// relationship
+	RelationshipType string `json:"relationship_type,omitempty"`
+	SourceRef        string `json:"source_ref,omitempty"`
+	TargetRef        string `json:"target_ref,omitempty"`
+
+	// report
+	ReportTypes []string `json:"report_types,omitempty"`
+	Published   string   `json:"published,omitempty"`
+	ObjectRefs  []string `json:"object_refs,omitempty"`
+}
+
+// stixBundle is a STIX bundle
+type stixBundle struct {
+	Type    string       `json:"type"`
+	ID      string       `json:"id"`
+	Objects []stixObject `json:"objects"`
+}
+
+// stixEncoder writes the graph of the results as a STIX bundle
+type stixEncoder struct {
+	endEncoder
+	w io.Writer
+}
+
+// newSTIXEncoder creates a STIX encoder
+func newSTIXEncoder(w io.Writer, _ Options) Encoder {
+	return &stixEncoder{w: w}
+}
+
+// End writes the bundle
+func (e *stixEncoder) End(summary Summary) error {
+	return WriteSTIX(e.w, summary.graph(), time.Now())
+}
+
+// WriteSTIX writes the graph as a STIX 2.1 bundle: an individual identity
+// for each username, a user-account observable for each profile,
+// relationships from usernames to their profiles (with the intersection
+// confidence) and between linking profiles, and a report of the scan
+// referencing them all. IDs only depend on the usernames, sites and links
+// and objects share a fixed created timestamp, so threat intelligence
+// platforms merge the objects of repeated scans.
+func WriteSTIX(w io.Writer, g *Graph, at time.Time) error {
+	timestamp := at.UTC().Format(stixTimestampFormat)
+
+	tool := stixObject{
+		Type:          "identity",
+		SpecVersion:   stixVersion,
+		ID:            stixID("identity", "tool", "accio"),
+		Created:       stixCreated,
+		Modified:      timestamp,
+		Name:          "Accio",
+		IdentityClass: "system",
+	}
+	objects := []stixObject{tool}
+
+	ids := make(map[string]string, len(g.Nodes))
+	var usernames []string
+	var profiles int
+	for _, node := range g.Nodes {
+		switch node.Type {
+		case NodeUsername:
+			object := stixObject{
+				Type:          "identity",
+				SpecVersion:   stixVersion,
+				ID:            stixID("identity", "username", node.username),
+				CreatedByRef:  tool.ID,
+				Created:       stixCreated,
+				Modified:      timestamp,
+				Name:          node.username,
+				Description:   "Person behind the username " + node.username,
+				IdentityClass: "individual",
+			}
+			ids[node.ID] = object.ID
+			usernames = append(usernames, node.username)
+			objects = append(objects, object)
+		case NodeProfile:
+			object := stixObject{
+				Type:          "user-account",
+				SpecVersion:   stixVersion,
+				AccountLogin:  node.username,
+				AccountType:   strings.ToLower(node.Platform),
+				ProfileURL:    node.URL,
+				FollowerCount: node.Followers,
+			}
+			object.ID = stixObservableID("user-account", map[string]string{
+				"account_login": object.AccountLogin,
+				"account_type":  object.AccountType,
+			})
+			ids[node.ID] = object.ID
+			profiles++
+			objects = append(objects, object)
+		}
+	}
+
+	for _, edge := range g.Edges {
+		source, target := ids[edge.Source], ids[edge.Target]
+		if source == "" || target == "" {
+			continue
+		}
+
+		relationship := stixObject{
+			Type:         "relationship",
+			SpecVersion:  stixVersion,
+			CreatedByRef: tool.ID,
+			Created:      stixCreated,
+			Modified:     timestamp,
+			SourceRef:    source,
+			TargetRef:    target,
+		}
+		switch edge.Type {
+		case HasProfile:
+			relationship.RelationshipType = "related-to"
+			relationship.Description = "Profile found for the username"
+			relationship.Confidence = stixConfidence(edge.Weight)
+		case LinksTo:
+			relationship.RelationshipType = LinksTo
+			relationship.Description = "Profile links to the other profile"
+		default:
+			continue
+		}
+		relationship.ID = stixID("relationship", relationship.SourceRef, relationship.RelationshipType, relationship.TargetRef)
+		objects = append(objects, relationship)
+	}
+
+	sort.Strings(usernames)
+	report := stixObject{
+		Type:         "report",
+		SpecVersion:  stixVersion,
+		ID:           stixID("report", usernames...),
+		CreatedByRef: tool.ID,
+		Created:      stixCreated,
+		Modified:     timestamp,
+		Name:         "Accio scan",
+		Description:  fmt.Sprintf("%d profiles found", profiles),
+		ReportTypes:  []string{"identity"},
+		Published:    timestamp,
+	}
+	if len(usernames) > 0 {
+		report.Name = "Accio scan of " + strings.Join(usernames, ", ")
+	}
+	for _, object := range objects {
+		report.ObjectRefs = append(report.ObjectRefs, object.ID)
+	}
+	objects = append(objects, report)
+
+	bundle := stixBundle{
+		Type:    "bundle",
+		ID:      stixID("bundle", report.ID, timestamp),
+		Objects: objects,
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundle)
+}
+
+// stixID returns the ID of an accio object of a type, derived from the
+// type and the given parts
+func stixID(objectType string, parts ...string) string {
+	name := objectType + "\x00" + strings.Join(parts, "\x00")
+	return objectType + "--" + uuid.NewSHA1(stixNamespace, []byte(name)).String()
+}
+
+// stixObservableID returns the ID of a cyber observable as STIX 2.1 defines
+// it: a UUIDv5 of the canonical JSON of its ID contributing properties
+func stixObservableID(objectType string, properties map[string]string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	// Maps are encoded with sorted keys and no whitespace, as the JSON
+	// canonicalization scheme requires for strings
+	encoder.Encode(properties)
+	return objectType + "--" + uuid.NewSHA1(stixSCONamespace, bytes.TrimSuffix(buf.Bytes(), []byte("\n"))).String()
+}
+
+// stixConfidence converts a confidence between 0 and 1 to the STIX 0-100 scale
+func stixConfidence(confidence float64) *int {
+	value := int(math.Round(math.Max(0, math.Min(1, confidence)) * 100))
+	return &value
+}
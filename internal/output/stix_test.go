@@ -0,0 +1,104 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stixTestBundle writes the test graph as a STIX bundle at a time
+func stixTestBundle(t *testing.T, at time.Time) stixBundle {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := WriteSTIX(&buf, testGraph(), at); err != nil {
+		t.Fatalf("Failed to write STIX: %v", err)
+	}
+
+	var bundle stixBundle
+	if err := json.Unmarshal(buf.Bytes(), &bundle); err != nil {
+		t.Fatalf("Failed to parse STIX: %v", err)
+	}
+	return bundle
+}
+
+func TestWriteSTIX(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	bundle := stixTestBundle(t, at)
+
+	if bundle.Type != "bundle" || !strings.HasPrefix(bundle.ID, "bundle--") {
+		t.Errorf("Unexpected bundle %s %s", bundle.Type, bundle.ID)
+	}
+
+	counts := make(map[string]int)
+	ids := make(map[string]bool)
+	var report stixObject
+	for _, object := range bundle.Objects {
+		counts[object.Type]++
+		if !strings.HasPrefix(object.ID, object.Type+"--") {
+			t.Errorf("ID %s does not match type %s", object.ID, object.Type)
+		}
+		if ids[object.ID] {
+			t.Errorf("Duplicate ID %s", object.ID)
+		}
+		ids[object.ID] = true
+		if object.SpecVersion != "2.1" {
+			t.Errorf("Expected spec_version 2.1, got %s", object.SpecVersion)
+		}
+
+		switch object.Type {
+		case "report":
+			report = object
+		case "relationship":
+			if !ids[object.SourceRef] || !ids[object.TargetRef] {
+				t.Errorf("Relationship %s refers to objects listed after it or missing", object.ID)
+			}
+			if object.RelationshipType == "related-to" && object.SourceRef == stixID("identity", "username", "johndoe") && (object.Confidence == nil || *object.Confidence != 80) {
+				t.Errorf("Expected confidence 80 on related-to, got %v", object.Confidence)
+			}
+		case "user-account":
+			if object.AccountLogin == "johndoe" && object.AccountType == "github" && (object.FollowerCount == nil || *object.FollowerCount != 42) {
+				t.Errorf("Expected follower count 42, got %v", object.FollowerCount)
+			}
+		}
+	}
+
+	// The tool and two usernames, three profiles, three related-to and one
+	// links-to relationship, and the report
+	if counts["identity"] != 3 || counts["user-account"] != 3 || counts["relationship"] != 4 || counts["report"] != 1 {
+		t.Errorf("Unexpected object counts: %v", counts)
+	}
+	if len(report.ObjectRefs) != len(bundle.Objects)-1 || report.Published != "2024-01-01T12:00:00.000Z" {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	// Repeated scans produce the same object IDs and creation times, and
+	// only bump the modification time
+	again := stixTestBundle(t, at.Add(time.Hour))
+	for i, object := range again.Objects {
+		first := bundle.Objects[i]
+		if object.ID != first.ID {
+			t.Errorf("Expected stable ID %s, got %s", first.ID, object.ID)
+		}
+		if object.Created != first.Created {
+			t.Errorf("Expected stable created %s for %s, got %s", first.Created, object.ID, object.Created)
+		}
+		if object.Type != "user-account" && (object.Modified != "2024-01-01T13:00:00.000Z" || first.Modified != "2024-01-01T12:00:00.000Z") {
+			t.Errorf("Expected modified to follow the scan for %s, got %s then %s", object.ID, first.Modified, object.Modified)
+		}
+	}
+}
+
+func TestSTIXObservableID(t *testing.T) {
+	// Observable IDs follow the STIX 2.1 UUIDv5 scheme, so other producers
+	// derive the same ID for the same account
+	id := stixObservableID("user-account", map[string]string{"account_login": "johndoe", "account_type": "github"})
+	if id != "user-account--9e7eaabc-4783-544a-83ba-d238c0f99cbf" {
+		t.Errorf("Unexpected user-account ID %s", id)
+	}
+	if id != stixObservableID("user-account", map[string]string{"account_type": "github", "account_login": "johndoe"}) {
+		t.Error("Expected the ID not to depend on property order")
+	}
+}
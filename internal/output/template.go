@@ -0,0 +1,192 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/accio/internal/checker"
+	"github.com/accio/internal/sites"
+)
+
+// ErrNoTemplate is returned by the template format when no template was given
+var ErrNoTemplate = errors.New("template format requires a template")
+
+// statusColors are the colors of the statuses, as in the HTML report
+var statusColors = map[string]string{
+	StatusFound:    "#2f855a",
+	StatusError:    "#c05621",
+	StatusNotFound: "#9aa5b1",
+}
+
+// TemplateData is the run passed to user-defined templates
+type TemplateData struct {
+	Username  string              // Username searched
+	Usernames []string            // Usernames checked, the searched one first
+	Results   []Result            // Results of every username, in the order they were checked
+	Found     int                 // Number of profiles found
+	Total     int                 // Number of results
+	Stats     *checker.CheckStats // Check statistics, if known
+	Duration  time.Duration       // Duration of the scan, if known
+	Analysis  *Analysis           // Confidence analysis of the found profiles, if any
+	Generated time.Time           // When the template was executed
+}
+
+// ResultGroup is a set of results sharing a category or a status
+type ResultGroup struct {
+	Name    string
+	Results []Result
+	Found   int
+}
+
+// TemplateFuncs are the helper functions available to user-defined templates
+var TemplateFuncs = template.FuncMap{
+	"status":      resultStatus,
+	"statusLabel": func(result Result) string { return statusLabels[resultStatus(result)] },
+	"statusColor": func(result Result) string { return statusColors[resultStatus(result)] },
+	"category":    func(result Result) string { return sites.GetCategory(result.Site) },
+	"date":        func(layout string, t time.Time) string { return t.Format(layout) },
+	"percent":     func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"found":       foundResults,
+	"byCategory":  groupByCategory,
+	"byStatus":    groupByStatus,
+	"join":        strings.Join,
+	"upper":       strings.ToUpper,
+	"lower":       strings.ToLower,
+}
+
+// ParseTemplate parses a template file with the template helper functions
+func ParseTemplate(filename string) (*template.Template, error) {
+	return template.New(filepath.Base(filename)).Funcs(TemplateFuncs).ParseFiles(filename)
+}
+
+// WithTemplate sets the template of the template format
+func (f *Formatter) WithTemplate(tmpl *template.Template) *Formatter {
+	f.Template = tmpl
+	return f
+}
+
+// templateEncoder executes a user-defined template once all results are in
+type templateEncoder struct {
+	w        io.Writer
+	template *template.Template
+}
+
+// newTemplateEncoder creates a template encoder
+func newTemplateEncoder(w io.Writer, options Options) Encoder {
+	return &templateEncoder{w: w, template: options.Template}
+}
+
+// Begin fails without a template, before anything is written
+func (e *templateEncoder) Begin() error {
+	if e.template == nil {
+		return ErrNoTemplate
+	}
+	return nil
+}
+
+// WriteResult writes nothing; the template is executed in End
+func (e *templateEncoder) WriteResult(Result) error {
+	return nil
+}
+
+// End executes the template with the run
+func (e *templateEncoder) End(summary Summary) error {
+	if e.template == nil {
+		return ErrNoTemplate
+	}
+	return e.template.Execute(e.w, newTemplateData(summary))
+}
+
+// newTemplateData returns the run passed to templates
+func newTemplateData(summary Summary) TemplateData {
+	data := TemplateData{
+		Results:   summary.Results,
+		Found:     found(summary.Results),
+		Total:     len(summary.Results),
+		Stats:     summary.Stats,
+		Analysis:  summary.Analysis,
+		Generated: time.Now(),
+	}
+
+	seen := make(map[string]bool)
+	for _, result := range summary.Results {
+		if result.Username != "" && !seen[result.Username] {
+			seen[result.Username] = true
+			data.Usernames = append(data.Usernames, result.Username)
+		}
+	}
+	if len(data.Usernames) > 0 {
+		data.Username = data.Usernames[0]
+	}
+
+	if stats := summary.Stats; stats != nil && !stats.StartTime.IsZero() {
+		data.Duration = stats.EndTime.Sub(stats.StartTime).Round(time.Millisecond)
+	}
+	return data
+}
+
+// foundResults returns the results whose profile was found
+func foundResults(results []Result) []Result {
+	var found []Result
+	for _, result := range results {
+		if result.Exists {
+			found = append(found, result)
+		}
+	}
+	return found
+}
+
+// groupByCategory groups results by the category of their site, in
+// alphabetical order of categories and then sites
+func groupByCategory(results []Result) []ResultGroup {
+	groups := groupResults(results, func(result Result) string {
+		return sites.GetCategory(result.Site)
+	})
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// groupByStatus groups results by status, found first, then errors and
+// profiles not found
+func groupByStatus(results []Result) []ResultGroup {
+	order := map[string]int{StatusFound: 0, StatusError: 1, StatusNotFound: 2}
+	groups := groupResults(results, resultStatus)
+	sort.Slice(groups, func(i, j int) bool {
+		return order[groups[i].Name] < order[groups[j].Name]
+	})
+	return groups
+}
+
+// groupResults groups results by key, sorting each group by site
+func groupResults(results []Result, key func(Result) string) []ResultGroup {
+	var groups []ResultGroup
+	index := make(map[string]int)
+	for _, result := range results {
+		name := key(result)
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, ResultGroup{Name: name})
+		}
+		groups[i].Results = append(groups[i].Results, result)
+		if result.Exists {
+			groups[i].Found++
+		}
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group.Results, func(i, j int) bool {
+			return group.Results[i].Site < group.Results[j].Site
+		})
+	}
+	return groups
+}
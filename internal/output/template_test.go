@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/accio/internal/checker"
+)
+
+func TestTemplateFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticket.tmpl")
+	text := `{{.Username}} {{.Found}}/{{.Total}} in {{.Duration}} at {{date "2006" .Stats.StartTime}}
+{{range byCategory .Results}}{{.Name}}:{{range .Results}} {{.Site}}={{status .}}({{statusColor .}}){{end}}
+{{end}}{{range found .Results}}[{{upper .Site}}]{{end}}
+{{with .Analysis}}{{percent .Confidence}}{{end}}`
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	tmpl, err := ParseTemplate(path)
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	formatter := NewFormatter(false).
+		WithFormat(FormatTemplate).
+		WithTemplate(tmpl).
+		WithStats(checker.CheckStats{StartTime: start, EndTime: start.Add(2 * time.Second)}).
+		WithAnalysis(&Analysis{Confidence: 0.8}).
+		WithWriter(&buf)
+	results := []Result{
+		{Username: "johndoe", Site: "Twitter", URL: "https://twitter.com/johndoe", Exists: true},
+		{Username: "johndoe", Site: "GitHub", URL: "https://github.com/johndoe", Exists: true},
+		{Username: "johndoe", Site: "GitLab", Error: errors.New("timeout")},
+	}
+	formatter.PrintSummary(results)
+
+	want := `johndoe 2/3 in 2s at 2024
+development: GitHub=found(#2f855a) GitLab=error(#c05621)
+social: Twitter=found(#2f855a)
+[TWITTER][GITHUB]
+80%`
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+
+	// Files are saved with the template whatever their extension
+	file := filepath.Join(t.TempDir(), "ticket.md")
+	if err := formatter.SaveToFile(results, file); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("Expected file to match template output, got %q", data)
+	}
+}
+
+func TestTemplateFormatWithoutTemplate(t *testing.T) {
+	if err := Encode(FormatTemplate, io.Discard, Options{}, Summary{}); !errors.Is(err, ErrNoTemplate) {
+		t.Errorf("Expected ErrNoTemplate, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	appservice "github.com/accio/internal/application/service"
+	"github.com/accio/internal/domain/model"
+)
+
+// apiKeyContextKey is the request context key of the authenticated API key
+type apiKeyContextKey struct{}
+
+// apiKeyFromContext returns the API key that authenticated the request, if any
+func apiKeyFromContext(ctx context.Context) *model.APIKey {
+	apiKey, _ := ctx.Value(apiKeyContextKey{}).(*model.APIKey)
+	return apiKey
+}
+
+// apiKeyCookieName is the cookie holding the API key of a web UI session
+const apiKeyCookieName = "accio_api_key"
+
+// requireScope authenticates requests with an API key holding the given scope
+// and enforces the key's rate limit and daily quota
+func (s *Server) requireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !s.authEnabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			apiKey, status, message := s.authenticate(w, r, apiKeyFromRequest(r), scope)
+			if status != 0 {
+				writeError(w, status, message)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, apiKey)))
+		})
+	}
+}
+
+// requireSession authenticates web UI requests with the API key stored in
+// the session cookie by the login page, like requireScope does for the API.
+// Visitors without a valid key are sent to the login page.
+func (s *Server) requireSession(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !s.authEnabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var rawKey string
+			if cookie, err := r.Cookie(apiKeyCookieName); err == nil {
+				rawKey = cookie.Value
+			}
+			if rawKey == "" {
+				s.redirectToLogin(w, r)
+				return
+			}
+
+			apiKey, status, message := s.authenticate(w, r, rawKey, scope)
+			switch status {
+			case 0:
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, apiKey)))
+			case http.StatusUnauthorized:
+				clearSessionCookie(w, r)
+				s.redirectToLogin(w, r)
+			default:
+				s.renderError(w, status, "Access denied", message)
+			}
+		})
+	}
+}
+
+// authenticate checks that a raw API key holds the scope and is within its
+// rate limit and daily quota, setting the limit headers. It returns the key,
+// or the status code and message of the failure.
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request, rawKey, scope string) (*model.APIKey, int, string) {
+	if rawKey == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="accio"`)
+		return nil, http.StatusUnauthorized, "API key required"
+	}
+
+	ctx := r.Context()
+	apiKey, err := s.container.APIKeyService.Authenticate(ctx, rawKey)
+	if err != nil {
+		if errors.Is(err, appservice.ErrInvalidAPIKey) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="accio", error="invalid_token"`)
+		}
+		status, message := serviceErrorStatus(err)
+		return nil, status, message
+	}
+
+	if !apiKey.HasScope(scope) {
+		return nil, http.StatusForbidden, "API key lacks the " + scope + " scope"
+	}
+
+	if apiKey.RateLimit > 0 {
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(apiKey.RateLimit))
+	}
+	if ok, wait := s.rateLimiter.allow(apiKey.ID, apiKey.RateLimit); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		return nil, http.StatusTooManyRequests, "rate limit exceeded"
+	}
+
+	remaining, err := s.container.APIKeyService.ConsumeQuota(ctx, apiKey)
+	if err != nil && !errors.Is(err, appservice.ErrQuotaExceeded) {
+		log.Printf("Error recording API key usage: %v", err)
+		return nil, http.StatusInternalServerError, "internal server error"
+	}
+	if apiKey.DailyQuota > 0 {
+		w.Header().Set("X-Quota-Limit", strconv.Itoa(apiKey.DailyQuota))
+		w.Header().Set("X-Quota-Remaining", strconv.Itoa(remaining))
+	}
+	if err != nil {
+		status, message := serviceErrorStatus(err)
+		return nil, status, message
+	}
+
+	return apiKey, 0, ""
+}
+
+// redirectToLogin sends the visitor to the login page, returning afterwards
+// to the requested page. htmx requests are redirected with HX-Redirect so
+// the whole page is replaced rather than the fragment.
+func (s *Server) redirectToLogin(w http.ResponseWriter, r *http.Request) {
+	next := r.URL.RequestURI()
+	if r.Header.Get("HX-Request") == "true" || r.Method != http.MethodGet {
+		next = "/"
+		if current, err := url.Parse(r.Header.Get("HX-Current-URL")); err == nil && current.Path != "" {
+			next = current.RequestURI()
+		}
+	}
+
+	location := "/login?next=" + url.QueryEscape(next)
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", location)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	http.Redirect(w, r, location, http.StatusSeeOther)
+}
+
+// setSessionCookie stores the API key of a web UI session. The cookie is
+// not readable by scripts and not sent with cross-site requests.
+func setSessionCookie(w http.ResponseWriter, r *http.Request, rawKey string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     apiKeyCookieName,
+		Value:    rawKey,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// clearSessionCookie removes the API key of a web UI session
+func clearSessionCookie(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     apiKeyCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// apiKeyFromRequest reads the API key from the Authorization or X-API-Key
+// header. Browsers cannot set headers on WebSocket and EventSource requests,
+// so those may pass the key in the api_key query parameter or the session
+// cookie instead.
+func apiKeyFromRequest(r *http.Request) string {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		if key := r.URL.Query().Get("api_key"); key != "" {
+			return key
+		}
+
+		// The live search of a logged in web UI session
+		if cookie, err := r.Cookie(apiKeyCookieName); err == nil {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,280 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	appservice "github.com/accio/internal/application/service"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/infrastructure/container"
+	"github.com/accio/internal/infrastructure/persistence"
+)
+
+func newAuthTestServer(t *testing.T) (*Server, appservice.APIKeyService) {
+	t.Helper()
+
+	db, err := persistence.NewDatabase()
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	apiKeyService := appservice.NewAPIKeyService(persistence.NewGormAPIKeyRepository(db.DB))
+	server := &Server{
+		container:   &container.Container{APIKeyService: apiKeyService},
+		authEnabled: true,
+		rateLimiter: newRateLimiter(),
+	}
+	return server, apiKeyService
+}
+
+func TestRequireScope(t *testing.T) {
+	server, apiKeyService := newAuthTestServer(t)
+	ctx := context.Background()
+
+	readKey, _, err := apiKeyService.CreateKey(ctx, "reader", []string{model.ScopeRead}, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+	scanKey, _, err := apiKeyService.CreateKey(ctx, "scanner", []string{model.ScopeScan}, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apiKeyFromContext(r.Context()) == nil {
+			t.Error("Expected API key in request context")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		scope  string
+		header string
+		value  string
+		status int
+	}{
+		{"missing key", model.ScopeRead, "", "", http.StatusUnauthorized},
+		{"malformed key", model.ScopeRead, "X-API-Key", "not-a-key", http.StatusUnauthorized},
+		{"wrong secret", model.ScopeRead, "X-API-Key", readKey + "x", http.StatusUnauthorized},
+		{"bearer read", model.ScopeRead, "Authorization", "Bearer " + readKey, http.StatusOK},
+		{"header read", model.ScopeRead, "X-API-Key", readKey, http.StatusOK},
+		{"read key on scan route", model.ScopeScan, "X-API-Key", readKey, http.StatusForbidden},
+		{"scan key on scan route", model.ScopeScan, "X-API-Key", scanKey, http.StatusOK},
+		{"scan key on read route", model.ScopeRead, "X-API-Key", scanKey, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/profiles", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			server.requireScope(tt.scope)(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+}
+
+func TestRequireScopeRevokedKey(t *testing.T) {
+	server, apiKeyService := newAuthTestServer(t)
+	ctx := context.Background()
+
+	rawKey, apiKey, err := apiKeyService.CreateKey(ctx, "revoked", []string{model.ScopeRead}, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+	if err := apiKeyService.RevokeKey(ctx, apiKey.ID); err != nil {
+		t.Fatalf("Failed to revoke key: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profiles", nil)
+	req.Header.Set("X-API-Key", rawKey)
+	rec := httptest.NewRecorder()
+	server.requireScope(model.ScopeRead)(http.NotFoundHandler()).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestRequireScopeLimits(t *testing.T) {
+	server, apiKeyService := newAuthTestServer(t)
+	ctx := context.Background()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func(rawKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/profiles", nil)
+		req.Header.Set("X-API-Key", rawKey)
+		rec := httptest.NewRecorder()
+		server.requireScope(model.ScopeRead)(ok).ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Rate limit
+	rateLimited, _, err := apiKeyService.CreateKey(ctx, "rate", []string{model.ScopeRead}, 2, 0)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if rec := request(rateLimited); rec.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status %d, got %d", i, http.StatusOK, rec.Code)
+		}
+	}
+	rec := request(rateLimited)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header")
+	}
+
+	// Daily quota
+	quotaLimited, _, err := apiKeyService.CreateKey(ctx, "quota", []string{model.ScopeRead}, 0, 1)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+	rec = request(quotaLimited)
+	if rec.Code != http.StatusOK || rec.Header().Get("X-Quota-Remaining") != "0" {
+		t.Errorf("Expected status %d with 0 remaining, got %d with %q", http.StatusOK, rec.Code, rec.Header().Get("X-Quota-Remaining"))
+	}
+	if rec := request(quotaLimited); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	if ok, _ := limiter.allow(1, 1); !ok {
+		t.Fatal("Expected first request to be allowed")
+	}
+	ok, wait := limiter.allow(1, 1)
+	if ok {
+		t.Fatal("Expected second request to be limited")
+	}
+	if wait != time.Minute {
+		t.Errorf("Expected to wait %v, got %v", time.Minute, wait)
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := limiter.allow(1, 1); !ok {
+		t.Error("Expected request to be allowed after refill")
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	server, apiKeyService := newAuthTestServer(t)
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+	server.templates = templates
+	ctx := context.Background()
+
+	readKey, _, err := apiKeyService.CreateKey(ctx, "browser", []string{model.ScopeRead}, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		scope    string
+		cookie   string
+		htmx     bool
+		status   int
+		location string
+	}{
+		{"no session", model.ScopeRead, "", false, http.StatusSeeOther, "/login?next=%2Fprofile%2FGitHub%2Foctocat%3Frefresh%3Dtrue"},
+		{"no session htmx", model.ScopeRead, "", true, http.StatusUnauthorized, "/login?next=%2F"},
+		{"invalid session", model.ScopeRead, readKey + "x", false, http.StatusSeeOther, "/login?next=%2Fprofile%2FGitHub%2Foctocat%3Frefresh%3Dtrue"},
+		{"valid session", model.ScopeRead, readKey, false, http.StatusOK, ""},
+		{"read session on scan route", model.ScopeScan, readKey, false, http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/profile/GitHub/octocat?refresh=true", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: apiKeyCookieName, Value: tt.cookie})
+			}
+			if tt.htmx {
+				req.Header.Set("HX-Request", "true")
+			}
+			rec := httptest.NewRecorder()
+			server.requireSession(tt.scope)(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			location := rec.Header().Get("Location")
+			if tt.htmx {
+				location = rec.Header().Get("HX-Redirect")
+			}
+			if location != tt.location {
+				t.Errorf("Expected redirect to %q, got %q", tt.location, location)
+			}
+		})
+	}
+}
+
+func TestLogin(t *testing.T) {
+	server, apiKeyService := newAuthTestServer(t)
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+	server.templates = templates
+
+	readKey, _, err := apiKeyService.CreateKey(context.Background(), "browser", []string{model.ScopeRead}, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	login := func(rawKey, next string) *httptest.ResponseRecorder {
+		form := url.Values{"api_key": {rawKey}, "next": {next}}
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		server.handleLogin().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := login(readKey, "/profile/GitHub/octocat")
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/profile/GitHub/octocat" {
+		t.Fatalf("Expected redirect to the profile, got %d to %q", rec.Code, rec.Header().Get("Location"))
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != apiKeyCookieName || cookies[0].Value != readKey ||
+		!cookies[0].HttpOnly || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Errorf("Expected an HttpOnly, SameSite=Strict session cookie, got %+v", cookies)
+	}
+
+	if rec := login(readKey, "//evil.example"); rec.Header().Get("Location") != "/" {
+		t.Errorf("Expected an off-site next to redirect to /, got %q", rec.Header().Get("Location"))
+	}
+
+	rec = login("not-a-key", "/")
+	if rec.Code != http.StatusUnauthorized || len(rec.Result().Cookies()) != 0 {
+		t.Errorf("Expected status %d without a cookie, got %d with %v", http.StatusUnauthorized, rec.Code, rec.Result().Cookies())
+	}
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/accio/internal/application/dto"
+)
+
+// handleCreateFeedback handles the create profile feedback endpoint
+func (s *Server) handleCreateFeedback() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profileID, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		var request dto.CreateFeedbackDTO
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		feedback, err := s.container.FeedbackService.SubmitFeedback(r.Context(), profileID, feedbackKeyID(r), request)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, feedback)
+	}
+}
+
+// handleListFeedback handles the feedback moderation listing endpoint
+func (s *Server) handleListFeedback() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		feedback, total, err := s.container.FeedbackService.ListFeedback(r.Context(), r.URL.Query().Get("type"), limit, offset)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, dto.FeedbackListDTO{
+			Feedback: feedback,
+			Total:    total,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
+
+// handleFeedbackForm handles feedback submitted from the profile page by a
+// session with the scan scope. Only htmx requests are accepted: browsers do
+// not send the HX-Request header on cross-site form posts, so other sites
+// cannot submit feedback on behalf of visitors.
+func (s *Server) handleFeedbackForm() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("HX-Request") != "true" {
+			s.renderError(w, http.StatusForbidden, "Feedback rejected", "Feedback must be submitted from the profile page")
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		profileID, ok := parsePositiveID(r.PostFormValue("profile_id"))
+		if !ok {
+			s.renderError(w, http.StatusBadRequest, "Feedback rejected", "Invalid profile")
+			return
+		}
+
+		request := dto.CreateFeedbackDTO{
+			Type:    r.PostFormValue("type"),
+			Comment: r.PostFormValue("comment"),
+		}
+		if _, err := s.container.FeedbackService.SubmitFeedback(r.Context(), profileID, feedbackKeyID(r), request); err != nil {
+			status := statusForError(err)
+			message := err.Error()
+			if status == http.StatusInternalServerError {
+				log.Printf("Error submitting feedback: %v", err)
+				message = "Error submitting feedback"
+			}
+			s.renderError(w, status, "Feedback rejected", message)
+			return
+		}
+
+		s.templates.RenderPartial(w, http.StatusOK, "feedback_thanks", nil)
+	}
+}
+
+// feedbackKeyID returns the ID of the API key submitting feedback, or 0 if
+// authentication is disabled
+func feedbackKeyID(r *http.Request) uint {
+	if apiKey := apiKeyFromContext(r.Context()); apiKey != nil {
+		return apiKey.ID
+	}
+	return 0
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/accio/internal/application/dto"
+	appservice "github.com/accio/internal/application/service"
+)
+
+// handleLiveness handles the liveness probe endpoint
+func (s *Server) handleLiveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, s.container.HealthService.Liveness(r.Context()))
+	}
+}
+
+// handleReadiness handles the readiness probe endpoint. Degraded reports
+// still count as ready; only failing critical checks return 503.
+func (s *Server) handleReadiness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, s.container.HealthService.Readiness(r.Context()))
+	}
+}
+
+// writeHealthReport writes a health report with a status code matching its status
+func writeHealthReport(w http.ResponseWriter, report *dto.HealthReportDTO) {
+	status := http.StatusOK
+	if report.Status == appservice.HealthFail {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, report)
+}
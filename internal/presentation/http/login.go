@@ -0,0 +1,66 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/accio/internal/domain/model"
+)
+
+// loginPageData is the data for the login page
+type loginPageData struct {
+	Next  string
+	Error string
+}
+
+// handleLoginPage handles the web UI login page
+func (s *Server) handleLoginPage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next := safeRedirect(r.URL.Query().Get("next"))
+		if !s.authEnabled {
+			http.Redirect(w, r, next, http.StatusSeeOther)
+			return
+		}
+
+		s.templates.RenderPage(w, http.StatusOK, "login", loginPageData{Next: next})
+	}
+}
+
+// handleLogin handles the login form. A key holding at least the read scope
+// is stored in the session cookie used by the web UI and live search.
+func (s *Server) handleLogin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		next := safeRedirect(r.PostFormValue("next"))
+		if !s.authEnabled {
+			http.Redirect(w, r, next, http.StatusSeeOther)
+			return
+		}
+
+		rawKey := strings.TrimSpace(r.PostFormValue("api_key"))
+		if _, status, message := s.authenticate(w, r, rawKey, model.ScopeRead); status != 0 {
+			s.templates.RenderPage(w, status, "login", loginPageData{Next: next, Error: message})
+			return
+		}
+
+		setSessionCookie(w, r, rawKey)
+		http.Redirect(w, r, next, http.StatusSeeOther)
+	}
+}
+
+// handleLogout handles the logout form
+func (s *Server) handleLogout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		clearSessionCookie(w, r)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	}
+}
+
+// safeRedirect returns the path to return to after logging in, restricted
+// to this site so the login page cannot redirect elsewhere
+func safeRedirect(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
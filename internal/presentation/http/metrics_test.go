@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/infrastructure/api"
+	"github.com/accio/internal/metrics"
+)
+
+// rateLimitedClient is a platform client whose every call is rate limited
+type rateLimitedClient struct{}
+
+func (c rateLimitedClient) GetProfileByUsername(ctx context.Context, username string) (*model.Profile, error) {
+	return nil, api.ErrRateLimited
+}
+
+func (c rateLimitedClient) SearchProfilesByName(ctx context.Context, name string) ([]*model.Profile, error) {
+	return nil, nil
+}
+
+func (c rateLimitedClient) GetProfileImage(ctx context.Context, profile *model.Profile) (io.ReadCloser, error) {
+	return nil, api.ErrRateLimited
+}
+
+func (c rateLimitedClient) GetPlatformName() string {
+	return "MetricsTest"
+}
+
+func TestMetricsAfterInstrumentedCall(t *testing.T) {
+	client := api.NewInstrumentedClient(rateLimitedClient{})
+
+	router := chi.NewRouter()
+	router.Use(instrumentRequests)
+	router.Get("/profiles/{username}", func(w http.ResponseWriter, r *http.Request) {
+		_, err := client.GetProfileByUsername(r.Context(), chi.URLParam(r, "username"))
+		writeServiceError(w, err)
+	})
+	router.Handle("/metrics", metrics.Handler())
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/profiles/johndoe")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected status %d, got %d", http.StatusTooManyRequests, resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read metrics: %v", err)
+	}
+
+	for _, sample := range []string{
+		`accio_platform_requests_total{operation="get_profile",platform="MetricsTest"} 1`,
+		`accio_platform_errors_total{class="rate_limited",operation="get_profile",platform="MetricsTest"} 1`,
+		`accio_http_request_duration_seconds_count{method="GET",route="/profiles/{username}",status="429"} 1`,
+		`accio_http_request_duration_seconds_bucket{method="GET",route="/profiles/{username}",status="429",le="+Inf"} 1`,
+	} {
+		if !strings.Contains(string(body), sample) {
+			t.Errorf("Expected sample %s in the scrape", sample)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+//go:embed openapi.yaml
+var openAPIYAML []byte
+
+// OpenAPI holds the parsed API specification used to document and validate requests
+type OpenAPI struct {
+	doc    *openapi3.T
+	router routers.Router
+	json   []byte
+}
+
+// LoadOpenAPI parses and validates the embedded OpenAPI document
+func LoadOpenAPI() (*OpenAPI, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPIYAML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI router: %w", err)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
+	return &OpenAPI{
+		doc:    doc,
+		router: router,
+		json:   data,
+	}, nil
+}
+
+// handleOpenAPI serves the OpenAPI document as JSON
+func (s *Server) handleOpenAPI() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(s.openapi.json)
+	}
+}
+
+// validateRequest rejects requests whose parameters or body do not match the
+// OpenAPI document. Requests for undocumented routes are passed through so
+// the router can answer them. Authentication is checked by requireScope.
+func (s *Server) validateRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := s.openapi.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			writeError(w, http.StatusBadRequest, validationMessage(err))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validationMessage returns a concise message for a request validation error
+func validationMessage(err error) string {
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(requestErr.Err, &schemaErr) {
+			if requestErr.Parameter != nil {
+				return fmt.Sprintf("parameter %q in %s: %s", requestErr.Parameter.Name, requestErr.Parameter.In, schemaErr.Reason)
+			}
+			if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+				return fmt.Sprintf("request body field %q: %s", strings.Join(pointer, "."), schemaErr.Reason)
+			}
+			return fmt.Sprintf("request body: %s", schemaErr.Reason)
+		}
+		return requestErr.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	openapi, err := LoadOpenAPI()
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI document: %v", err)
+	}
+
+	server := &Server{router: chi.NewRouter(), openapi: openapi}
+	server.registerRoutes()
+
+	documented := make(map[string]bool)
+	err = chi.Walk(server.router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(route, "/")
+		if route == "" {
+			route = "/"
+		}
+		if strings.HasSuffix(route, "/*") {
+			// Static files
+			return nil
+		}
+
+		pathItem := openapi.doc.Paths.Find(route)
+		if pathItem == nil || pathItem.GetOperation(method) == nil {
+			t.Errorf("Route %s %s is not documented", method, route)
+		}
+		documented[method+" "+route] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk routes: %v", err)
+	}
+
+	for path, pathItem := range openapi.doc.Paths.Map() {
+		for method := range pathItem.Operations() {
+			if !documented[method+" "+path] {
+				t.Errorf("Documented operation %s %s is not routed", method, path)
+			}
+		}
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	openapi, err := LoadOpenAPI()
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI document: %v", err)
+	}
+	server := &Server{openapi: openapi}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{"valid query", http.MethodGet, "/api/profiles?limit=5&verified=true", "", http.StatusOK},
+		{"invalid limit", http.MethodGet, "/api/profiles?limit=0", "", http.StatusBadRequest},
+		{"invalid boolean", http.MethodGet, "/api/profiles?verified=maybe", "", http.StatusBadRequest},
+		{"missing required", http.MethodGet, "/api/profiles/search", "", http.StatusBadRequest},
+		{"valid body", http.MethodPost, "/api/scans", `{"username":"johndoe","sites":["GitHub"]}`, http.StatusOK},
+		{"empty username", http.MethodPost, "/api/scans", `{"username":""}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/api/scans", `{"username":"johndoe","foo":1}`, http.StatusBadRequest},
+		{"malformed body", http.MethodPost, "/api/scans", `{`, http.StatusBadRequest},
+		{"undocumented route", http.MethodGet, "/static/css/styles.css", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+			server.validateRequest(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
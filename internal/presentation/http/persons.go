@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/accio/internal/application/dto"
+)
+
+// handleListPersons handles the list persons endpoint
+func (s *Server) handleListPersons() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		persons, total, err := s.container.IdentityService.ListPersons(r.Context(), limit, offset)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, dto.PersonListDTO{
+			Persons: persons,
+			Total:   total,
+			Limit:   limit,
+			Offset:  offset,
+		})
+	}
+}
+
+// handleGetPerson handles the get person endpoint
+func (s *Server) handleGetPerson() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 0)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "id must be a positive integer")
+			return
+		}
+
+		person, err := s.container.IdentityService.GetPerson(r.Context(), uint(id))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, person)
+	}
+}
+
+// handleResolveIdentities handles the identity resolution endpoint, starting
+// a resolution in the background. The request body is optional.
+func (s *Server) handleResolveIdentities() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request dto.ResolveIdentitiesDTO
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		job, err := s.container.IdentityService.StartResolve(r.Context(), request)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Location", "/api/persons/resolve/"+job.ID)
+		writeJSON(w, http.StatusAccepted, job)
+	}
+}
+
+// handleGetResolveJob handles the get resolution job endpoint
+func (s *Server) handleGetResolveJob() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, err := s.container.IdentityService.GetResolveJob(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, job)
+	}
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/accio/internal/application/dto"
+)
+
+// profileTimelineLimit is the number of snapshots shown on the profile page
+const profileTimelineLimit = 20
+
+// handleListProfileHistory handles the list profile snapshots endpoint
+func (s *Server) handleListProfileHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		snapshots, total, err := s.container.ProfileService.ListSnapshots(r.Context(), id, limit, offset)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, dto.ProfileSnapshotListDTO{
+			Snapshots: snapshots,
+			Total:     total,
+			Limit:     limit,
+			Offset:    offset,
+		})
+	}
+}
+
+// handleDiffProfileSnapshots handles the diff profile snapshots endpoint
+func (s *Server) handleDiffProfileSnapshots() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		var snapshotIDs [2]uint
+		for i, name := range []string{"from", "to"} {
+			value, err := strconv.ParseUint(r.URL.Query().Get(name), 10, 0)
+			if err != nil || value == 0 {
+				writeError(w, http.StatusBadRequest, name+" must be a positive integer")
+				return
+			}
+			snapshotIDs[i] = uint(value)
+		}
+
+		diff, err := s.container.ProfileService.DiffSnapshots(r.Context(), id, snapshotIDs[0], snapshotIDs[1])
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, diff)
+	}
+}
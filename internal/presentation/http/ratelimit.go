@@ -0,0 +1,61 @@
+package http
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// rateLimiter is an in-memory token bucket limiter keyed by API key ID
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[uint]*tokenBucket
+	now     func() time.Time
+}
+
+// tokenBucket holds the tokens left for one key
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a new rateLimiter
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		buckets: make(map[uint]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token for the key if one is available. perMinute is both the
+// bucket size and the refill rate; a value of zero or less disables limiting.
+// When no token is available it returns how long to wait for the next one.
+func (l *rateLimiter) allow(id uint, perMinute int) (bool, time.Duration) {
+	if perMinute <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	capacity := float64(perMinute)
+	rate := capacity / time.Minute.Seconds()
+
+	bucket, ok := l.buckets[id]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, last: now}
+		l.buckets[id] = bucket
+	}
+
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+		return false, wait
+	}
+
+	bucket.tokens--
+	return true, 0
+}
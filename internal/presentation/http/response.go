@@ -0,0 +1,177 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/accio/internal/application/dto"
+	appservice "github.com/accio/internal/application/service"
+	domainservice "github.com/accio/internal/domain/service"
+	"github.com/accio/internal/infrastructure/api"
+	"github.com/accio/internal/output"
+)
+
+// Pagination defaults for list endpoints
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
+
+// writeError writes an error envelope with the given status code
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, dto.ErrorDTO{
+		Error: dto.ErrorDetailDTO{
+			Status:  status,
+			Code:    errorCode(status),
+			Message: message,
+		},
+	})
+}
+
+// writeServiceError maps a service error to a status code and writes it
+func writeServiceError(w http.ResponseWriter, err error) {
+	status, message := serviceErrorStatus(err)
+	writeError(w, status, message)
+}
+
+// serviceErrorStatus returns the status code and client-facing message of a
+// service error. Internal errors are logged and their details withheld.
+func serviceErrorStatus(err error) (int, string) {
+	status := statusForError(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("Internal error: %v", err)
+		return status, "internal server error"
+	}
+	return status, err.Error()
+}
+
+// statusForError returns the HTTP status code for a service error
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, api.ErrNotFound), errors.Is(err, domainservice.ErrUnsupportedPlatform),
+		errors.Is(err, appservice.ErrScanJobNotFound), errors.Is(err, appservice.ErrAPIKeyNotFound),
+		errors.Is(err, appservice.ErrProfileNotFound), errors.Is(err, appservice.ErrPersonNotFound),
+		errors.Is(err, appservice.ErrWatchlistEntryNotFound), errors.Is(err, appservice.ErrWebhookNotFound),
+		errors.Is(err, appservice.ErrWebhookDeliveryNotFound), errors.Is(err, appservice.ErrProfileSnapshotNotFound),
+		errors.Is(err, appservice.ErrResolveJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, api.ErrInvalidParams), errors.Is(err, appservice.ErrInvalidUsername),
+		errors.Is(err, appservice.ErrUnknownSite), errors.Is(err, appservice.ErrInvalidScope),
+		errors.Is(err, appservice.ErrInvalidFeedback), errors.Is(err, appservice.ErrInvalidThreshold),
+		errors.Is(err, output.ErrUnknownFormat), errors.Is(err, output.ErrNoTemplate),
+		errors.Is(err, appservice.ErrInvalidInterval), errors.Is(err, appservice.ErrInvalidWebhookURL),
+		errors.Is(err, appservice.ErrForbiddenWebhookTarget), errors.Is(err, appservice.ErrUnknownEvent):
+		return http.StatusBadRequest
+	case errors.Is(err, appservice.ErrInvalidAPIKey):
+		return http.StatusUnauthorized
+	case errors.Is(err, appservice.ErrScanJobFinished), errors.Is(err, appservice.ErrWatchlistEntryExists),
+		errors.Is(err, appservice.ErrResolveInProgress):
+		return http.StatusConflict
+	case errors.Is(err, appservice.ErrScanQueueFull), errors.Is(err, appservice.ErrScanStopped),
+		errors.Is(err, appservice.ErrResolveStopped):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, api.ErrRateLimited), errors.Is(err, appservice.ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, api.ErrUnauthorized), errors.Is(err, api.ErrAPIError):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorCode returns a machine-readable error code for a status code
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusBadGateway:
+		return "upstream_error"
+	case http.StatusNotImplemented:
+		return "not_implemented"
+	case http.StatusServiceUnavailable:
+		return "unavailable"
+	default:
+		return "internal_error"
+	}
+}
+
+// parsePagination parses the limit and offset query parameters
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	limit = defaultPageLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+		if limit > maxPageLimit {
+			limit = maxPageLimit
+		}
+	}
+
+	if value := r.URL.Query().Get("offset"); value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+
+	return limit, offset, nil
+}
+
+// parseRefresh parses the refresh query parameter, which bypasses the stored
+// profile when true
+func parseRefresh(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("refresh")
+	if value == "" {
+		return false, nil
+	}
+
+	refresh, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("refresh must be true or false")
+	}
+	return refresh, nil
+}
+
+// parseID parses the numeric id path parameter, writing a bad request
+// response if it is invalid
+func parseID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, ok := parsePositiveID(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "id must be a positive integer")
+	}
+	return id, ok
+}
+
+// parsePositiveID parses a non-zero numeric ID
+func parsePositiveID(value string) (uint, bool) {
+	id, err := strconv.ParseUint(value, 10, 0)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/accio/internal/application/dto"
+	appservice "github.com/accio/internal/application/service"
+	"github.com/accio/internal/output"
+)
+
+// Server-Sent Events settings
+const (
+	sseKeepAliveInterval = 15 * time.Second
+	maxRequestBodySize   = 1 << 20
+)
+
+// handleCreateScan handles the create scan job endpoint
+func (s *Server) handleCreateScan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request dto.CreateScanJobDTO
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		job, err := s.container.ScanJobService.Enqueue(r.Context(), request)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Location", "/api/scans/"+job.ID)
+		writeJSON(w, http.StatusAccepted, job)
+	}
+}
+
+// handleGetScan handles the get scan job endpoint
+func (s *Server) handleGetScan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, err := s.container.ScanJobService.GetJob(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, job)
+	}
+}
+
+// handleExportScan handles the export scan job endpoint, writing the results
+// collected so far with the encoder of the requested format
+func (s *Server) handleExportScan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := output.FormatJSON
+		if value := r.URL.Query().Get("format"); value != "" {
+			format = output.FormatType(value)
+		}
+		registration, ok := output.Lookup(format)
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown format %q", format))
+			return
+		}
+
+		job, summary, err := s.container.ScanJobService.GetSummary(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		// Encode before sending the status, so encoding errors are reported
+		var body bytes.Buffer
+		if err := output.Encode(format, &body, output.Options{}, *summary); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		filename := job.Username
+		if len(registration.Extensions) > 0 {
+			filename += registration.Extensions[0]
+		}
+		w.Header().Set("Content-Type", registration.MediaType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.WriteHeader(http.StatusOK)
+		if _, err := body.WriteTo(w); err != nil {
+			log.Printf("Error exporting scan %s as %s: %v", job.ID, format, err)
+		}
+	}
+}
+
+// handleCancelScan handles the cancel scan job endpoint
+func (s *Server) handleCancelScan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, err := s.container.ScanJobService.CancelJob(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, job)
+	}
+}
+
+// handleScanEvents streams the events of a scan job as Server-Sent Events.
+// Results already collected are replayed first, so clients may connect late.
+func (s *Server) handleScanEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeError(w, http.StatusInternalServerError, "streaming is not supported")
+			return
+		}
+
+		events, unsubscribe, err := s.container.ScanJobService.Subscribe(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		keepAlive := time.NewTicker(sseKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+				flusher.Flush()
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := writeScanEvent(w, event); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// writeScanEvent writes a scan event in Server-Sent Events format. Result
+// events carry the result, status and done events carry the job summary.
+func writeScanEvent(w http.ResponseWriter, event dto.ScanEventDTO) error {
+	var payload any = event.Job
+	if event.Type == appservice.ScanEventResult {
+		payload = event.Result
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
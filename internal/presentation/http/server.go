@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -15,14 +17,26 @@ import (
 	"github.com/go-chi/cors"
 
 	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	"github.com/accio/internal/domain/repository"
 	"github.com/accio/internal/infrastructure/container"
+	"github.com/accio/internal/metrics"
 )
 
+// requestTimeout bounds every request except long-lived streams
+const requestTimeout = 60 * time.Second
+
 // Server represents the HTTP server
 type Server struct {
 	router    *chi.Mux
 	container *container.Container
+	templates *Templates
+	openapi   *OpenAPI
 	port      int
+
+	// API key authentication
+	authEnabled bool
+	rateLimiter *rateLimiter
 }
 
 // NewServer creates a new HTTP server
@@ -34,23 +48,76 @@ func NewServer(container *container.Container, port int) *Server {
 	router.Use(middleware.RealIP)
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.Timeout(60 * time.Second))
-
-	// CORS
-	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+	router.Use(instrumentRequests)
+	router.Use(securityHeaders)
+
+	// CORS is only enabled for explicitly allowed origins. API keys are sent
+	// in headers, so cross-origin requests never need credentials.
+	if origins := corsAllowedOrigins(); len(origins) > 0 {
+		router.Use(cors.Handler(cors.Options{
+			AllowedOrigins:   origins,
+			AllowedMethods:   []string{"GET", "POST", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key"},
+			ExposedHeaders:   []string{"Link", "Location", "Retry-After", "X-RateLimit-Limit", "X-Quota-Limit", "X-Quota-Remaining"},
+			AllowCredentials: false,
+			MaxAge:           300,
+		}))
+	}
+
+	// Templates
+	templates, err := LoadTemplates()
+	if err != nil {
+		log.Fatalf("Error loading templates: %v", err)
+	}
+
+	// API specification
+	openapi, err := LoadOpenAPI()
+	if err != nil {
+		log.Fatalf("Error loading OpenAPI document: %v", err)
+	}
+
+	// Authentication
+	authEnabled := apiAuthEnabled()
+	if !authEnabled {
+		log.Println("Warning: API key authentication is disabled")
+	}
 
 	return &Server{
-		router:    router,
-		container: container,
-		port:      port,
+		router:      router,
+		container:   container,
+		templates:   templates,
+		openapi:     openapi,
+		port:        port,
+		authEnabled: authEnabled,
+		rateLimiter: newRateLimiter(),
+	}
+}
+
+// corsAllowedOrigins reads the comma-separated CORS_ALLOWED_ORIGINS allowlist
+func corsAllowedOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// apiAuthEnabled reports whether API_AUTH_ENABLED allows unauthenticated
+// access. Authentication is enabled unless explicitly turned off.
+func apiAuthEnabled() bool {
+	value := os.Getenv("API_AUTH_ENABLED")
+	if value == "" {
+		return true
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: Invalid value %q for API_AUTH_ENABLED, enabling authentication", value)
+		return true
 	}
+	return enabled
 }
 
 // Start starts the HTTP server
@@ -93,106 +160,150 @@ func (s *Server) Start() error {
 
 // registerRoutes registers all routes
 func (s *Server) registerRoutes() {
+	// Prometheus metrics
+	s.router.Method(http.MethodGet, "/metrics", metrics.Handler())
+
+	// Liveness and readiness probes
+	s.router.With(middleware.Timeout(requestTimeout)).Get("/healthz", s.handleLiveness())
+	s.router.With(middleware.Timeout(requestTimeout)).Get("/readyz", s.handleReadiness())
+
 	// API routes
 	s.router.Route("/api", func(r chi.Router) {
-		// Health check
-		r.Get("/health", s.handleHealth())
-
-		// Profiles
-		r.Route("/profiles", func(r chi.Router) {
-			r.Get("/", s.handleGetProfiles())
-			r.Get("/{platform}/{username}", s.handleGetProfile())
-			r.Get("/search", s.handleSearchProfiles())
+		r.Use(s.validateRequest)
+
+		// Health check, kept for existing clients
+		r.With(middleware.Timeout(requestTimeout)).Get("/health", s.handleReadiness())
+
+		// API specification
+		r.Get("/openapi.json", s.handleOpenAPI())
+
+		// Streaming endpoints are not bound by the request timeout
+		r.With(s.requireScope(model.ScopeRead)).Get("/scans/{id}/events", s.handleScanEvents())
+
+		// Read-only endpoints
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(requestTimeout))
+			r.Use(s.requireScope(model.ScopeRead))
+
+			// Profiles
+			r.Route("/profiles", func(r chi.Router) {
+				r.Get("/", s.handleGetProfiles())
+				r.Get("/{platform}/{username}", s.handleGetProfile())
+				r.Get("/search", s.handleSearchProfiles())
+
+				// Stored profiles by ID have their own prefix so their paths
+				// cannot be taken for a platform and username
+				r.Get("/by-id/{id}/history", s.handleListProfileHistory())
+				r.Get("/by-id/{id}/history/diff", s.handleDiffProfileSnapshots())
+			})
+
+			// Feedback moderation
+			r.Get("/feedback", s.handleListFeedback())
+
+			// Persons resolved from stored profiles
+			r.Get("/persons", s.handleListPersons())
+			r.Get("/persons/{id}", s.handleGetPerson())
+			r.Get("/persons/resolve/{id}", s.handleGetResolveJob())
+
+			// Search history
+			r.Route("/search-history", func(r chi.Router) {
+				r.Get("/popular", s.handleGetPopularSearches())
+			})
+
+			// Watchlist
+			r.Get("/watchlist", s.handleListWatchlistEntries())
+			r.Get("/watchlist/alerts", s.handleListWatchlistAlerts())
+			r.Get("/watchlist/{id}", s.handleGetWatchlistEntry())
+			r.Get("/watchlist/{id}/snapshots", s.handleListWatchlistSnapshots())
+
+			// Scan jobs
+			r.Get("/scans/{id}", s.handleGetScan())
+			r.Get("/scans/{id}/export", s.handleExportScan())
 		})
 
-		// Search history
-		r.Route("/search-history", func(r chi.Router) {
-			r.Get("/popular", s.handleGetPopularSearches())
+		// Endpoints that start or stop scans, write feedback, rebuild derived
+		// data or manage webhooks
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(requestTimeout))
+			r.Use(s.requireScope(model.ScopeScan))
+
+			r.Post("/profiles/{id}/feedback", s.handleCreateFeedback())
+			r.Post("/scans", s.handleCreateScan())
+			r.Delete("/scans/{id}", s.handleCancelScan())
+			r.Post("/persons/resolve", s.handleResolveIdentities())
+			r.Post("/watchlist", s.handleCreateWatchlistEntry())
+			r.Delete("/watchlist/{id}", s.handleDeleteWatchlistEntry())
+			r.Post("/watchlist/{id}/run", s.handleRunWatchlistEntry())
+
+			// Webhooks, whose URLs often embed credentials
+			r.Get("/webhooks", s.handleListWebhooks())
+			r.Post("/webhooks", s.handleCreateWebhook())
+			r.Get("/webhooks/{id}", s.handleGetWebhook())
+			r.Delete("/webhooks/{id}", s.handleDeleteWebhook())
+			r.Get("/webhooks/{id}/deliveries", s.handleListWebhookDeliveries())
+			r.Post("/webhooks/deliveries/{id}/replay", s.handleReplayWebhookDelivery())
 		})
 	})
 
-	// Web UI routes
-	s.router.Get("/", s.handleIndex())
-	s.router.Get("/search", s.handleSearchPage())
-	s.router.Get("/profile/{platform}/{username}", s.handleProfilePage())
+	// Live search is a long-lived WebSocket connection that runs scans
+	s.router.With(s.requireScope(model.ScopeScan)).Get("/ws/search", s.handleSearchSocket())
+
+	// Web UI login, storing an API key in the session cookie
+	s.router.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(requestTimeout))
+		r.Get("/login", s.handleLoginPage())
+		r.Post("/login", s.handleLogin())
+		r.Post("/logout", s.handleLogout())
+	})
+
+	// Web UI routes, which fetch profiles from platform APIs like the
+	// read scoped API does
+	s.router.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(requestTimeout))
+		r.Use(s.requireSession(model.ScopeRead))
+		r.Get("/", s.handleIndex())
+		r.Get("/search", s.handleSearchPage())
+		r.Get("/profile/{platform}/{username}", s.handleProfilePage())
+	})
+
+	// Feedback from the web UI changes search rankings, like the scan scoped
+	// feedback endpoint
+	s.router.With(middleware.Timeout(requestTimeout), s.requireSession(model.ScopeScan)).Post("/feedback", s.handleFeedbackForm())
 
 	// Static files
 	fileServer := http.FileServer(http.Dir("./ascendio/static"))
 	s.router.Handle("/static/*", http.StripPrefix("/static", fileServer))
 }
 
-// handleHealth handles the health check endpoint
-func (s *Server) handleHealth() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
-	}
+// indexPageData is the data for the index page
+type indexPageData struct {
+	LoggedIn bool // Authenticated with a session cookie, so a logout button is shown
+}
+
+// searchResultsData is the data for the search results partial
+type searchResultsData struct {
+	Profiles []*dto.ProfileDTO
+}
+
+// profilePageData is the data for the profile page and partial
+type profilePageData struct {
+	Profile     *dto.ProfileDTO
+	History     []*dto.ProfileSnapshotDTO // Latest snapshots, for the timeline
+	CanFeedback bool                      // The session may submit feedback, which needs the scan scope
+}
+
+// errorData is the data for the error partial
+type errorData struct {
+	Title   string
+	Message string
 }
 
 // handleIndex handles the index page
 func (s *Server) handleIndex() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Render index template
-		w.Header().Set("Content-Type", "text/html")
-		w.Write([]byte(`
-<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Accio - Username Search Tool</title>
-    <link rel="stylesheet" href="/static/css/styles.css">
-    <script src="https://unpkg.com/htmx.org@1.9.6"></script>
-</head>
-<body>
-    <header>
-        <h1>Accio</h1>
-        <p>Username Search Tool</p>
-    </header>
-    <main>
-        <section class="search-section">
-            <h2>Search for Usernames</h2>
-            <form hx-get="/search" hx-target="#results" hx-indicator="#spinner">
-                <div class="form-group">
-                    <label for="search-type">Search by:</label>
-                    <select id="search-type" name="type">
-                        <option value="username">Username</option>
-                        <option value="name">Real Name</option>
-                    </select>
-                </div>
-                <div class="form-group">
-                    <label for="query">Search:</label>
-                    <input type="text" id="query" name="query" placeholder="Enter username or real name" required>
-                </div>
-                <div class="form-group">
-                    <label for="platforms">Platforms:</label>
-                    <select id="platforms" name="platforms" multiple>
-                        <option value="Twitter">Twitter</option>
-                        <option value="GitHub">GitHub</option>
-                        <option value="Twitch">Twitch</option>
-                        <option value="Instagram">Instagram</option>
-                    </select>
-                    <small>Hold Ctrl/Cmd to select multiple platforms</small>
-                </div>
-                <div class="form-group">
-                    <button type="submit">Search</button>
-                </div>
-            </form>
-            <div id="spinner" class="htmx-indicator">
-                <div class="spinner"></div>
-            </div>
-        </section>
-        <section id="results" class="results-section">
-            <!-- Results will be loaded here -->
-        </section>
-    </main>
-    <footer>
-        <p>&copy; 2023 Accio - Username Search Tool</p>
-    </footer>
-</body>
-</html>
-`))
+		s.templates.RenderPage(w, http.StatusOK, "index", indexPageData{
+			LoggedIn: apiKeyFromContext(r.Context()) != nil,
+		})
 	}
 }
 
@@ -205,8 +316,7 @@ func (s *Server) handleSearchPage() http.HandlerFunc {
 		platforms := r.URL.Query()["platforms"]
 
 		if query == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("Query parameter is required"))
+			s.renderError(w, http.StatusBadRequest, "Invalid search", "Query parameter is required")
 			return
 		}
 
@@ -218,8 +328,8 @@ func (s *Server) handleSearchPage() http.HandlerFunc {
 			// Search by real name
 			result, err := s.container.ProfileService.SearchProfilesByName(ctx, query)
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write([]byte(fmt.Sprintf("Error searching profiles: %v", err)))
+				log.Printf("Error searching profiles: %v", err)
+				s.renderError(w, http.StatusInternalServerError, "Search failed", "Error searching profiles")
 				return
 			}
 			profiles = result
@@ -237,57 +347,15 @@ func (s *Server) handleSearchPage() http.HandlerFunc {
 		}
 
 		// Record search in history
-		if s.container.SearchHistoryService != nil {
-			go func() {
-				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-				defer cancel()
-				s.container.SearchHistoryService.RecordSearch(ctx, query, len(profiles))
-			}()
-		}
+		s.recordSearch(query, len(profiles))
 
 		// Render search results
-		w.Header().Set("Content-Type", "text/html")
-		if len(profiles) == 0 {
-			w.Write([]byte(`
-<div class="no-results">
-    <h3>No profiles found</h3>
-    <p>No profiles were found matching your search criteria.</p>
-</div>
-`))
-			return
-		}
-
-		// Render profiles
-		w.Write([]byte(`
-<div class="results">
-    <h3>Search Results</h3>
-    <p>Found ` + fmt.Sprintf("%d", len(profiles)) + ` profiles matching your search criteria.</p>
-    <div class="profiles-grid">
-`))
-
-		for _, profile := range profiles {
-			w.Write([]byte(`
-<div class="profile-card" hx-get="/profile/` + profile.Platform + `/` + profile.Username + `" hx-target="#results">
-    <div class="profile-image">
-        <img src="` + profile.ImageURL + `" alt="` + profile.RealName + `">
-    </div>
-    <div class="profile-info">
-        <h4>` + profile.RealName + `</h4>
-        <p>@` + profile.Username + ` on ` + profile.Platform + `</p>
-        <p>` + fmt.Sprintf("%d", profile.FollowerCount) + ` followers</p>
-    </div>
-</div>
-`))
-		}
-
-		w.Write([]byte(`
-    </div>
-</div>
-`))
+		s.templates.RenderPartial(w, http.StatusOK, "search_results", searchResultsData{Profiles: profiles})
 	}
 }
 
-// handleProfilePage handles the profile page
+// handleProfilePage handles the profile page. htmx requests receive the
+// profile fragment, direct visits the full page.
 func (s *Server) handleProfilePage() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get path parameters
@@ -295,72 +363,64 @@ func (s *Server) handleProfilePage() http.HandlerFunc {
 		username := chi.URLParam(r, "username")
 
 		if platform == "" || username == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("Platform and username parameters are required"))
+			s.renderError(w, http.StatusBadRequest, "Invalid profile", "Platform and username parameters are required")
+			return
+		}
+
+		refresh, err := parseRefresh(r)
+		if err != nil {
+			s.renderError(w, http.StatusBadRequest, "Invalid profile", err.Error())
 			return
 		}
 
 		// Get profile
 		ctx := r.Context()
-		profile, err := s.container.ProfileService.GetProfileByUsername(ctx, username, platform)
+		profile, err := s.getProfile(ctx, username, platform, refresh)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("Error getting profile: %v", err)))
+			status := statusForError(err)
+			if status == http.StatusInternalServerError {
+				log.Printf("Error getting profile: %v", err)
+			}
+			s.renderError(w, status, "Profile unavailable", "Error getting profile")
 			return
 		}
 
 		if profile == nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("Profile not found"))
+			s.renderError(w, http.StatusNotFound, "Profile not found", "No profile was found for this username")
 			return
 		}
 
-		// Render profile
-		w.Header().Set("Content-Type", "text/html")
-		w.Write([]byte(`
-<div class="profile-detail">
-    <div class="profile-header">
-        <div class="profile-image">
-            <img src="` + profile.ImageURL + `" alt="` + profile.RealName + `">
-        </div>
-        <div class="profile-info">
-            <h3>` + profile.RealName + `</h3>
-            <p>@` + profile.Username + ` on ` + profile.Platform + `</p>
-            <p>` + fmt.Sprintf("%d", profile.FollowerCount) + ` followers</p>
-            <p><a href="` + profile.ProfileURL + `" target="_blank">View Profile</a></p>
-        </div>
-    </div>
-    <div class="profile-bio">
-        <h4>Bio</h4>
-        <p>` + profile.Bio + `</p>
-    </div>
-    <div class="profile-data">
-        <h4>Profile Data</h4>
-        <table>
-            <tr>
-                <th>Key</th>
-                <th>Value</th>
-            </tr>
-`))
-
-		for key, value := range profile.PlatformData {
-			w.Write([]byte(`
-            <tr>
-                <td>` + key + `</td>
-                <td>` + value + `</td>
-            </tr>
-`))
+		// Render profile with its latest snapshots
+		apiKey := apiKeyFromContext(ctx)
+		data := profilePageData{
+			Profile:     profile,
+			CanFeedback: apiKey == nil || apiKey.HasScope(model.ScopeScan),
 		}
+		if profile.ID != 0 {
+			data.History, _, err = s.container.ProfileService.ListSnapshots(ctx, profile.ID, profileTimelineLimit, 0)
+			if err != nil {
+				log.Printf("Error listing profile snapshots: %v", err)
+			}
+		}
+		if r.Header.Get("HX-Request") == "true" {
+			s.templates.RenderPartial(w, http.StatusOK, "profile_detail", data)
+			return
+		}
+		s.templates.RenderPage(w, http.StatusOK, "profile", data)
+	}
+}
 
-		w.Write([]byte(`
-        </table>
-    </div>
-    <div class="profile-actions">
-        <button hx-get="/search" hx-target="#results" class="back-button">Back to Search</button>
-    </div>
-</div>
-`))
+// getProfile gets a profile, fetching it from its platform if refresh is set
+func (s *Server) getProfile(ctx context.Context, username, platform string, refresh bool) (*dto.ProfileDTO, error) {
+	if refresh {
+		return s.container.ProfileService.RefreshProfileByUsername(ctx, username, platform)
 	}
+	return s.container.ProfileService.GetProfileByUsername(ctx, username, platform)
+}
+
+// renderError renders the error partial
+func (s *Server) renderError(w http.ResponseWriter, status int, title, message string) {
+	s.templates.RenderPartial(w, status, "error", errorData{Title: title, Message: message})
 }
 
 // API Handlers
@@ -368,39 +428,145 @@ func (s *Server) handleProfilePage() http.HandlerFunc {
 // handleGetProfiles handles the get profiles endpoint
 func (s *Server) handleGetProfiles() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// TODO: Implement
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotImplemented)
-		w.Write([]byte(`{"error":"Not implemented"}`))
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Build filter from query parameters
+		filter := repository.ProfileFilter{
+			Platform: r.URL.Query().Get("platform"),
+		}
+		if value := r.URL.Query().Get("verified"); value != "" {
+			verified, err := strconv.ParseBool(value)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "verified must be true or false")
+				return
+			}
+			filter.Verified = &verified
+		}
+
+		profiles, total, err := s.container.ProfileService.ListProfiles(r.Context(), filter, limit, offset)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, dto.ProfileListDTO{
+			Profiles: profiles,
+			Total:    total,
+			Limit:    limit,
+			Offset:   offset,
+		})
 	}
 }
 
 // handleGetProfile handles the get profile endpoint
 func (s *Server) handleGetProfile() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// TODO: Implement
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotImplemented)
-		w.Write([]byte(`{"error":"Not implemented"}`))
+		platform := chi.URLParam(r, "platform")
+		username := chi.URLParam(r, "username")
+
+		refresh, err := parseRefresh(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		profile, err := s.getProfile(r.Context(), username, platform, refresh)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		if profile == nil {
+			writeError(w, http.StatusNotFound, "profile not found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, profile)
 	}
 }
 
 // handleSearchProfiles handles the search profiles endpoint
 func (s *Server) handleSearchProfiles() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// TODO: Implement
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotImplemented)
-		w.Write([]byte(`{"error":"Not implemented"}`))
+		name := strings.TrimSpace(r.URL.Query().Get("name"))
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "name query parameter is required")
+			return
+		}
+
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		profiles, err := s.container.ProfileService.SearchProfilesByName(r.Context(), name)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		// Record search in history
+		s.recordSearch(name, len(profiles))
+
+		// Paginate ranked results
+		total := len(profiles)
+		if offset > total {
+			offset = total
+		}
+		end := offset + limit
+		if end > total {
+			end = total
+		}
+
+		writeJSON(w, http.StatusOK, dto.ProfileListDTO{
+			Profiles: profiles[offset:end],
+			Total:    int64(total),
+			Limit:    limit,
+			Offset:   offset,
+		})
 	}
 }
 
 // handleGetPopularSearches handles the get popular searches endpoint
 func (s *Server) handleGetPopularSearches() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// TODO: Implement
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotImplemented)
-		w.Write([]byte(`{"error":"Not implemented"}`))
+		limit, _, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		searches, err := s.container.SearchHistoryService.GetPopularSearches(r.Context(), limit)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		searchDTOs := make([]*dto.SearchHistoryDTO, 0, len(searches))
+		for _, search := range searches {
+			searchDTOs = append(searchDTOs, dto.NewSearchHistoryDTO(search))
+		}
+
+		writeJSON(w, http.StatusOK, searchDTOs)
 	}
 }
+
+// recordSearch records a search in the history without blocking the request
+func (s *Server) recordSearch(query string, resultCount int) {
+	if s.container.SearchHistoryService == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.container.SearchHistoryService.RecordSearch(ctx, query, resultCount); err != nil {
+			log.Printf("Error recording search: %v", err)
+		}
+	}()
+}
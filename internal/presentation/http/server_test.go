@@ -0,0 +1,77 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/infrastructure/container"
+)
+
+func TestProfileRoutes(t *testing.T) {
+	openapi, err := LoadOpenAPI()
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI document: %v", err)
+	}
+
+	server := &Server{
+		router:  chi.NewRouter(),
+		openapi: openapi,
+		container: &container.Container{ProfileService: &stubProfileService{profiles: []*dto.ProfileDTO{
+			{ID: 7, Username: "history", Platform: "GitHub"},
+		}}},
+	}
+	server.registerRoutes()
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected int
+		check    func(t *testing.T, body []byte)
+	}{
+		{
+			name:     "username named like the history route",
+			path:     "/api/profiles/GitHub/history",
+			expected: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var profile dto.ProfileDTO
+				if err := json.Unmarshal(body, &profile); err != nil || profile.ID != 7 {
+					t.Errorf("Expected profile 7, got %s", body)
+				}
+			},
+		},
+		{
+			name:     "history by ID",
+			path:     "/api/profiles/by-id/7/history",
+			expected: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var list dto.ProfileSnapshotListDTO
+				if err := json.Unmarshal(body, &list); err != nil || list.Snapshots == nil {
+					t.Errorf("Expected a snapshot list, got %s", body)
+				}
+			},
+		},
+		{
+			name:     "history by invalid ID",
+			path:     "/api/profiles/by-id/0/history",
+			expected: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if recorder.Code != tc.expected {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expected, recorder.Code, recorder.Body.String())
+			}
+			if tc.check != nil {
+				tc.check(t, recorder.Body.Bytes())
+			}
+		})
+	}
+}
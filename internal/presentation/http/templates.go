@@ -0,0 +1,138 @@
+package http
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// contentSecurityPolicy restricts pages to same-origin resources, plus htmx
+// from unpkg and remote profile images
+const contentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' https://unpkg.com; " +
+	"style-src 'self'; " +
+	"img-src 'self' https: data:; " +
+	"connect-src 'self'; " +
+	"object-src 'none'; " +
+	"base-uri 'self'; " +
+	"form-action 'self'; " +
+	"frame-ancestors 'none'"
+
+// templateFuncs are the helper functions available to all templates
+var templateFuncs = template.FuncMap{
+	"httpURL":      httpURL,
+	"join":         strings.Join,
+	"percent":      percent,
+	"signedWeight": signedWeight,
+}
+
+// Templates holds the parsed page and partial templates
+type Templates struct {
+	partials *template.Template
+	pages    map[string]*template.Template
+}
+
+// LoadTemplates parses the embedded layout, partial and page templates.
+// Each page is parsed into its own copy of the layout so pages can
+// define the same blocks.
+func LoadTemplates() (*Templates, error) {
+	partials, err := template.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/layout.html", "templates/partials/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	pageFiles, err := fs.Glob(templateFS, "templates/pages/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make(map[string]*template.Template, len(pageFiles))
+	for _, file := range pageFiles {
+		page, err := partials.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := page.ParseFS(templateFS, file); err != nil {
+			return nil, err
+		}
+		pages[strings.TrimSuffix(path.Base(file), ".html")] = page
+	}
+
+	return &Templates{
+		partials: partials,
+		pages:    pages,
+	}, nil
+}
+
+// RenderPage renders a full page inside the layout
+func (t *Templates) RenderPage(w http.ResponseWriter, status int, name string, data any) {
+	page, ok := t.pages[name]
+	if !ok {
+		log.Printf("Error rendering page: unknown page %q", name)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	t.execute(w, status, page, "layout", data)
+}
+
+// RenderPartial renders a partial, such as an htmx fragment
+func (t *Templates) RenderPartial(w http.ResponseWriter, status int, name string, data any) {
+	t.execute(w, status, t.partials, name, data)
+}
+
+// execute renders into a buffer first so template errors never produce a
+// half-written response
+func (t *Templates) execute(w http.ResponseWriter, status int, tmpl *template.Template, name string, data any) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("Error rendering template %s: %v", name, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+// securityHeaders sets the Content-Security-Policy and related headers
+func securityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", contentSecurityPolicy)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// httpURL returns the URL if it is an absolute http or https URL and an
+// empty string otherwise, so remote data cannot smuggle other schemes
+// into src and href attributes
+func httpURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+// percent formats a fraction between 0 and 1 as a whole percentage
+func percent(fraction float64) string {
+	return fmt.Sprintf("%.0f%%", fraction*100)
+}
+
+// signedWeight formats an evidence weight with an explicit sign
+func signedWeight(weight float64) string {
+	return fmt.Sprintf("%+.2f", weight)
+}
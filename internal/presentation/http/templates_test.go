@@ -0,0 +1,88 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/accio/internal/application/dto"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+
+	for _, page := range []string{"index", "profile"} {
+		if _, ok := templates.pages[page]; !ok {
+			t.Errorf("Expected page %s to be loaded", page)
+		}
+	}
+}
+
+func TestRenderEscapesRemoteData(t *testing.T) {
+	templates, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+
+	profile := &dto.ProfileDTO{
+		RealName:   `"><script>alert(1)</script>`,
+		Username:   "johndoe",
+		Platform:   "Twitter",
+		ProfileURL: "javascript:alert(1)",
+		ImageURL:   `https://example.com/a.jpg" onerror="alert(1)`,
+		Bio:        "<img src=x onerror=alert(1)>",
+		PlatformData: map[string]string{
+			"<b>key</b>": "<i>value</i>",
+		},
+	}
+
+	previousID := uint(1)
+	history := []*dto.ProfileSnapshotDTO{{
+		ID:         2,
+		PreviousID: &previousID,
+		Changes: []dto.ProfileFieldChangeDTO{
+			{Field: "bio", Old: "", New: "<script>alert(2)</script>"},
+		},
+	}}
+
+	recorder := httptest.NewRecorder()
+	templates.RenderPage(recorder, 200, "profile", profilePageData{Profile: profile, History: history})
+	body := recorder.Body.String()
+
+	for _, unsafe := range []string{"<script>", "<img src=x", "javascript:", `" onerror="`, "<b>key</b>", "<i>value</i>"} {
+		if strings.Contains(body, unsafe) {
+			t.Errorf("Expected %q to be escaped, got %s", unsafe, body)
+		}
+	}
+
+	if !strings.Contains(body, "&lt;img src=x onerror=alert(1)&gt;") {
+		t.Errorf("Expected bio to be rendered escaped, got %s", body)
+	}
+
+	if !strings.Contains(body, "&lt;script&gt;alert(2)&lt;/script&gt;") {
+		t.Errorf("Expected history changes to be rendered escaped, got %s", body)
+	}
+}
+
+func TestHTTPURL(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"https://example.com/a.jpg", "https://example.com/a.jpg"},
+		{"http://example.com/", "http://example.com/"},
+		{"javascript:alert(1)", ""},
+		{"data:text/html,<script>alert(1)</script>", ""},
+		{"//example.com/a.jpg", ""},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if result := httpURL(tc.input); result != tc.expected {
+			t.Errorf("httpURL(%q): expected %q, got %q", tc.input, tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/accio/internal/application/dto"
+)
+
+// handleCreateWatchlistEntry handles the create watchlist entry endpoint
+func (s *Server) handleCreateWatchlistEntry() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request dto.CreateWatchlistEntryDTO
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		entry, err := s.container.WatchlistService.CreateEntry(r.Context(), request)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Location", "/api/watchlist/"+strconv.FormatUint(uint64(entry.ID), 10))
+		writeJSON(w, http.StatusCreated, entry)
+	}
+}
+
+// handleListWatchlistEntries handles the list watchlist entries endpoint
+func (s *Server) handleListWatchlistEntries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		entries, total, err := s.container.WatchlistService.ListEntries(r.Context(), limit, offset)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, dto.WatchlistEntryListDTO{
+			Entries: entries,
+			Total:   total,
+			Limit:   limit,
+			Offset:  offset,
+		})
+	}
+}
+
+// handleGetWatchlistEntry handles the get watchlist entry endpoint
+func (s *Server) handleGetWatchlistEntry() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		entry, err := s.container.WatchlistService.GetEntry(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, entry)
+	}
+}
+
+// handleDeleteWatchlistEntry handles the delete watchlist entry endpoint
+func (s *Server) handleDeleteWatchlistEntry() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.container.WatchlistService.DeleteEntry(r.Context(), id); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleRunWatchlistEntry handles the run watchlist entry endpoint. The run
+// happens in the background; its snapshot and alerts appear once it is done.
+func (s *Server) handleRunWatchlistEntry() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		entry, err := s.container.WatchlistService.RunEntry(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusAccepted, entry)
+	}
+}
+
+// handleListWatchlistSnapshots handles the list watchlist snapshots endpoint
+func (s *Server) handleListWatchlistSnapshots() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		snapshots, total, err := s.container.WatchlistService.ListSnapshots(r.Context(), id, limit, offset)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, dto.WatchlistSnapshotListDTO{
+			Snapshots: snapshots,
+			Total:     total,
+			Limit:     limit,
+			Offset:    offset,
+		})
+	}
+}
+
+// handleListWatchlistAlerts handles the list watchlist alerts endpoint,
+// optionally filtered by entry_id
+func (s *Server) handleListWatchlistAlerts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var entryID uint64
+		if value := r.URL.Query().Get("entry_id"); value != "" {
+			entryID, err = strconv.ParseUint(value, 10, 0)
+			if err != nil || entryID == 0 {
+				writeError(w, http.StatusBadRequest, "entry_id must be a positive integer")
+				return
+			}
+		}
+
+		alerts, total, err := s.container.WatchlistService.ListAlerts(r.Context(), uint(entryID), limit, offset)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, dto.WatchlistAlertListDTO{
+			Alerts: alerts,
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		})
+	}
+}
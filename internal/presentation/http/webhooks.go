@@ -0,0 +1,138 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/accio/internal/application/dto"
+)
+
+// handleCreateWebhook handles the create webhook endpoint. The response is
+// the only one including the secret.
+func (s *Server) handleCreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request dto.CreateWebhookDTO
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		webhook, err := s.container.WebhookService.CreateWebhook(r.Context(), request)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Location", "/api/webhooks/"+strconv.FormatUint(uint64(webhook.ID), 10))
+		writeJSON(w, http.StatusCreated, webhook)
+	}
+}
+
+// handleListWebhooks handles the list webhooks endpoint
+func (s *Server) handleListWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		webhooks, total, err := s.container.WebhookService.ListWebhooks(r.Context(), limit, offset)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, dto.WebhookListDTO{
+			Webhooks: webhooks,
+			Total:    total,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
+
+// handleGetWebhook handles the get webhook endpoint
+func (s *Server) handleGetWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		webhook, err := s.container.WebhookService.GetWebhook(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, webhook)
+	}
+}
+
+// handleDeleteWebhook handles the delete webhook endpoint
+func (s *Server) handleDeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.container.WebhookService.DeleteWebhook(r.Context(), id); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleListWebhookDeliveries handles the list webhook deliveries endpoint
+func (s *Server) handleListWebhookDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		deliveries, total, err := s.container.WebhookService.ListDeliveries(r.Context(), id, limit, offset)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, dto.WebhookDeliveryListDTO{
+			Deliveries: deliveries,
+			Total:      total,
+			Limit:      limit,
+			Offset:     offset,
+		})
+	}
+}
+
+// handleReplayWebhookDelivery handles the replay webhook delivery endpoint.
+// The replay is sent in the background like any other delivery.
+func (s *Server) handleReplayWebhookDelivery() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseID(w, r)
+		if !ok {
+			return
+		}
+
+		delivery, err := s.container.WebhookService.ReplayDelivery(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusAccepted, delivery)
+	}
+}
@@ -0,0 +1,299 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"github.com/accio/internal/application/dto"
+	appservice "github.com/accio/internal/application/service"
+)
+
+// Live search settings
+const (
+	searchSocketReadLimit    = 4096
+	searchSocketWriteTimeout = 10 * time.Second
+)
+
+// Live search message types
+const (
+	searchMessageStarted  = "started"
+	searchMessageProfile  = "profile"
+	searchMessageSite     = "site"
+	searchMessageProgress = "progress"
+	searchMessageError    = "error"
+	searchMessageComplete = "complete"
+)
+
+// searchSession is a single live search WebSocket connection. Each new
+// search request cancels the one still running on the same connection.
+type searchSession struct {
+	server *Server
+	conn   *websocket.Conn
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// searchProgress tracks the progress counters of a live search
+type searchProgress struct {
+	mu       sync.Mutex
+	progress dto.SearchProgressDTO
+}
+
+// handleSearchSocket handles the live search WebSocket endpoint
+func (s *Server) handleSearchSocket() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			log.Printf("Error accepting WebSocket connection: %v", err)
+			return
+		}
+		defer conn.CloseNow()
+		conn.SetReadLimit(searchSocketReadLimit)
+
+		ctx := r.Context()
+		session := &searchSession{server: s, conn: conn}
+		defer session.stop()
+
+		for {
+			var request dto.SearchRequestMessageDTO
+			if err := wsjson.Read(ctx, conn, &request); err != nil {
+				if websocket.CloseStatus(err) == -1 && !errors.Is(err, context.Canceled) {
+					log.Printf("Error reading WebSocket message: %v", err)
+				}
+				return
+			}
+
+			switch request.Action {
+			case "search":
+				session.start(ctx, request)
+			case "cancel":
+				session.stop()
+			default:
+				session.send(ctx, dto.SearchMessageDTO{Type: searchMessageError, Error: "unknown action"})
+			}
+		}
+	}
+}
+
+// start cancels the running search and starts a new one
+func (ss *searchSession) start(ctx context.Context, request dto.SearchRequestMessageDTO) {
+	ss.stop()
+
+	ctx, cancel := context.WithCancel(ctx)
+	ss.mu.Lock()
+	ss.cancel = cancel
+	ss.mu.Unlock()
+
+	ss.wg.Add(1)
+	go func() {
+		defer ss.wg.Done()
+		defer cancel()
+		ss.run(ctx, request)
+	}()
+}
+
+// stop cancels the running search and waits for it to exit
+func (ss *searchSession) stop() {
+	ss.mu.Lock()
+	if ss.cancel != nil {
+		ss.cancel()
+		ss.cancel = nil
+	}
+	ss.mu.Unlock()
+	ss.wg.Wait()
+}
+
+// run executes a single search request
+func (ss *searchSession) run(ctx context.Context, request dto.SearchRequestMessageDTO) {
+	query := strings.TrimSpace(request.Query)
+	if query == "" {
+		ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageError, Error: "query is required"})
+		return
+	}
+
+	var progress *searchProgress
+	if request.Type == "name" {
+		progress = ss.searchByName(ctx, query)
+	} else {
+		progress = ss.searchByUsername(ctx, query, request.Platforms)
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	snapshot := progress.snapshot()
+	ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageComplete, Query: query, Progress: &snapshot})
+	ss.server.recordSearch(query, snapshot.Profiles+snapshot.Sites)
+}
+
+// searchByName streams stored and remote profiles matching a real name
+func (ss *searchSession) searchByName(ctx context.Context, name string) *searchProgress {
+	progress := &searchProgress{}
+
+	profiles, err := ss.server.container.ProfileService.SearchProfilesByName(ctx, name)
+	if err != nil {
+		ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageError, Error: err.Error()})
+		return progress
+	}
+
+	progress.addTotal(len(profiles))
+	ss.sendStarted(ctx, name, progress)
+
+	for _, profile := range profiles {
+		ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageProfile, Profile: profile})
+		ss.sendProgress(ctx, progress.checkProfile(true))
+	}
+
+	return progress
+}
+
+// searchByUsername streams platform profiles and site scan results for a
+// username. Platform lookups and the site scan run concurrently.
+func (ss *searchSession) searchByUsername(ctx context.Context, username string, platforms []string) *searchProgress {
+	progress := &searchProgress{}
+	profileService := ss.server.container.ProfileService
+
+	if len(platforms) == 0 {
+		platforms = profileService.GetSupportedPlatforms()
+	}
+	progress.addTotal(len(platforms))
+
+	job, err := ss.server.container.ScanJobService.Enqueue(ctx, dto.CreateScanJobDTO{Username: username})
+	if err != nil {
+		ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageError, Error: err.Error()})
+	} else {
+		progress.addTotal(job.Stats.Total)
+	}
+
+	ss.sendStarted(ctx, username, progress)
+
+	var wg sync.WaitGroup
+	for _, platform := range platforms {
+		wg.Add(1)
+		go func(platform string) {
+			defer wg.Done()
+			profile, err := profileService.GetProfileByUsername(ctx, username, platform)
+			found := err == nil && profile != nil
+			if found {
+				ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageProfile, Profile: profile})
+			}
+			ss.sendProgress(ctx, progress.checkProfile(found))
+		}(platform)
+	}
+
+	if job != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ss.streamScan(ctx, job.ID, progress)
+		}()
+	}
+
+	wg.Wait()
+	return progress
+}
+
+// streamScan forwards the results of a scan job, cancelling it if the
+// search is cancelled first
+func (ss *searchSession) streamScan(ctx context.Context, jobID string, progress *searchProgress) {
+	scanJobService := ss.server.container.ScanJobService
+
+	events, unsubscribe, err := scanJobService.Subscribe(ctx, jobID)
+	if err != nil {
+		ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageError, Error: err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			scanJobService.CancelJob(context.Background(), jobID)
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Type != appservice.ScanEventResult {
+				continue
+			}
+
+			found := event.Result.Exists
+			if found {
+				ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageSite, Result: event.Result})
+			}
+			ss.sendProgress(ctx, progress.checkSite(found))
+		}
+	}
+}
+
+// sendStarted sends the started message with the initial counters
+func (ss *searchSession) sendStarted(ctx context.Context, query string, progress *searchProgress) {
+	snapshot := progress.snapshot()
+	ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageStarted, Query: query, Progress: &snapshot})
+}
+
+// sendProgress sends a progress message
+func (ss *searchSession) sendProgress(ctx context.Context, progress dto.SearchProgressDTO) {
+	ss.send(ctx, dto.SearchMessageDTO{Type: searchMessageProgress, Progress: &progress})
+}
+
+// send writes a message to the client, dropping it if the search was cancelled
+func (ss *searchSession) send(ctx context.Context, message dto.SearchMessageDTO) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, searchSocketWriteTimeout)
+	defer cancel()
+
+	if err := wsjson.Write(ctx, ss.conn, message); err != nil && ctx.Err() == nil {
+		log.Printf("Error writing WebSocket message: %v", err)
+	}
+}
+
+// addTotal increases the number of expected checks
+func (p *searchProgress) addTotal(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Total += n
+}
+
+// checkProfile records a platform lookup and returns the updated counters
+func (p *searchProgress) checkProfile(found bool) dto.SearchProgressDTO {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Checked++
+	if found {
+		p.progress.Profiles++
+	}
+	return p.progress
+}
+
+// checkSite records a site check and returns the updated counters
+func (p *searchProgress) checkSite(found bool) dto.SearchProgressDTO {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Checked++
+	if found {
+		p.progress.Sites++
+	}
+	return p.progress
+}
+
+// snapshot returns the current counters
+func (p *searchProgress) snapshot() dto.SearchProgressDTO {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.progress
+}
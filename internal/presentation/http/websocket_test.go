@@ -0,0 +1,175 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/go-chi/chi/v5"
+
+	"github.com/accio/internal/application/dto"
+	"github.com/accio/internal/domain/model"
+	domainservice "github.com/accio/internal/domain/service"
+)
+
+// stubProfileService answers name searches and lookups with fixed profiles,
+// which have no snapshots
+type stubProfileService struct {
+	domainservice.ProfileService
+	profiles []*dto.ProfileDTO
+}
+
+func (s *stubProfileService) SearchProfilesByName(ctx context.Context, name string) ([]*dto.ProfileDTO, error) {
+	return s.profiles, nil
+}
+
+func (s *stubProfileService) GetProfileByUsername(ctx context.Context, username, platform string) (*dto.ProfileDTO, error) {
+	for _, profile := range s.profiles {
+		if profile.Username == username && profile.Platform == platform {
+			return profile, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubProfileService) ListSnapshots(ctx context.Context, profileID uint, limit, offset int) ([]*dto.ProfileSnapshotDTO, int64, error) {
+	return []*dto.ProfileSnapshotDTO{}, 0, nil
+}
+
+// newSearchSocketTestServer serves the live search route behind the scan
+// scope and returns its WebSocket URL with a read and a scan key
+func newSearchSocketTestServer(t *testing.T) (wsURL, readKey, scanKey string) {
+	t.Helper()
+
+	server, apiKeyService := newAuthTestServer(t)
+	server.container.ProfileService = &stubProfileService{profiles: []*dto.ProfileDTO{
+		{Username: "johndoe", Platform: "GitHub", RealName: "John Doe"},
+		{Username: "jdoe", Platform: "Twitter", RealName: "John Doe"},
+	}}
+	server.router = chi.NewRouter()
+	server.router.With(server.requireScope(model.ScopeScan)).Get("/ws/search", server.handleSearchSocket())
+
+	ctx := context.Background()
+	var err error
+	if readKey, _, err = apiKeyService.CreateKey(ctx, "reader", []string{model.ScopeRead}, 0, 0); err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+	if scanKey, _, err = apiKeyService.CreateKey(ctx, "scanner", []string{model.ScopeScan}, 0, 0); err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	httpServer := httptest.NewServer(server.router)
+	t.Cleanup(httpServer.Close)
+	return "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws/search", readKey, scanKey
+}
+
+func TestSearchSocketScope(t *testing.T) {
+	wsURL, readKey, scanKey := newSearchSocketTestServer(t)
+
+	tests := []struct {
+		name   string
+		url    string
+		cookie string
+		status int
+	}{
+		{"missing key", wsURL, "", http.StatusUnauthorized},
+		{"read key", wsURL + "?api_key=" + readKey, "", http.StatusForbidden},
+		{"scan key", wsURL + "?api_key=" + scanKey, "", http.StatusSwitchingProtocols},
+		{"scan session cookie", wsURL, scanKey, http.StatusSwitchingProtocols},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			options := &websocket.DialOptions{HTTPHeader: http.Header{}}
+			if tt.cookie != "" {
+				options.HTTPHeader.Set("Cookie", apiKeyCookieName+"="+tt.cookie)
+			}
+			conn, resp, err := websocket.Dial(ctx, tt.url, options)
+			if conn != nil {
+				defer conn.Close(websocket.StatusNormalClosure, "")
+			}
+			if resp == nil {
+				t.Fatalf("Expected a response, got error %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d (%v)", tt.status, resp.StatusCode, err)
+			}
+		})
+	}
+}
+
+func TestSearchSocketStreamsNameSearch(t *testing.T) {
+	wsURL, _, scanKey := newSearchSocketTestServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, wsURL+"?api_key="+scanKey, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	request := dto.SearchRequestMessageDTO{Action: "search", Type: "name", Query: " John Doe "}
+	if err := wsjson.Write(ctx, conn, request); err != nil {
+		t.Fatalf("Failed to send search: %v", err)
+	}
+
+	var messages []dto.SearchMessageDTO
+	for {
+		var message dto.SearchMessageDTO
+		if err := wsjson.Read(ctx, conn, &message); err != nil {
+			t.Fatalf("Failed to read message: %v", err)
+		}
+		messages = append(messages, message)
+		if message.Type == searchMessageComplete || message.Type == searchMessageError {
+			break
+		}
+	}
+
+	expected := []string{
+		searchMessageStarted,
+		searchMessageProfile, searchMessageProgress,
+		searchMessageProfile, searchMessageProgress,
+		searchMessageComplete,
+	}
+	if len(messages) != len(expected) {
+		t.Fatalf("Expected %d messages, got %+v", len(expected), messages)
+	}
+	for i, message := range messages {
+		if message.Type != expected[i] {
+			t.Errorf("Message %d: expected type %s, got %s", i, expected[i], message.Type)
+		}
+	}
+
+	if messages[0].Query != "John Doe" || messages[0].Progress.Total != 2 {
+		t.Errorf("Expected started message for the trimmed query with 2 checks, got %+v", messages[0])
+	}
+	if messages[1].Profile == nil || messages[1].Profile.Username != "johndoe" {
+		t.Errorf("Expected the first profile, got %+v", messages[1].Profile)
+	}
+	complete := messages[len(messages)-1].Progress
+	if complete == nil || complete.Checked != 2 || complete.Profiles != 2 {
+		t.Errorf("Expected 2 checked profiles on completion, got %+v", complete)
+	}
+
+	// Unknown actions are reported without closing the connection
+	if err := wsjson.Write(ctx, conn, dto.SearchRequestMessageDTO{Action: "pause"}); err != nil {
+		t.Fatalf("Failed to send action: %v", err)
+	}
+	var message dto.SearchMessageDTO
+	if err := wsjson.Read(ctx, conn, &message); err != nil {
+		t.Fatalf("Failed to read message: %v", err)
+	}
+	if message.Type != searchMessageError || message.Error != "unknown action" {
+		t.Errorf("Expected unknown action error, got %+v", message)
+	}
+}
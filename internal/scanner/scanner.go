@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/accio/internal/checker"
+	"github.com/accio/internal/output"
+	"github.com/accio/internal/sites"
+	httputil "github.com/accio/pkg/http"
+)
+
+// Default scan settings
+const (
+	DefaultTimeout = 10 // seconds
+	DefaultRetries = 2
+)
+
+// Options configures a Scanner
+type Options struct {
+	Timeout     int  // Timeout in seconds for each HTTP request
+	Concurrency int  // Number of sites checked in parallel
+	Retries     int  // Number of attempts per site
+	Verbose     bool // Log each check to stdout
+}
+
+// Scanner checks a username across all configured sites
+type Scanner struct {
+	options Options
+	sites   []sites.Site
+}
+
+// NewScanner creates a new Scanner checking every known site
+func NewScanner(options Options) *Scanner {
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = runtime.NumCPU()
+	}
+	if options.Retries <= 0 {
+		options.Retries = DefaultRetries
+	}
+
+	return &Scanner{
+		options: options,
+		sites:   sites.GetSites(),
+	}
+}
+
+// WithSites restricts the scanner to the given sites
+func (s *Scanner) WithSites(siteList []sites.Site) *Scanner {
+	s.sites = siteList
+	return s
+}
+
+// Sites returns the sites this scanner checks
+func (s *Scanner) Sites() []sites.Site {
+	return s.sites
+}
+
+// Scan checks the username on every site and calls onResult as each check
+// completes. onResult may be called from multiple goroutines concurrently.
+// Sites not yet checked when the context is cancelled are skipped.
+func (s *Scanner) Scan(ctx context.Context, username string, onResult func(output.Result)) checker.CheckStats {
+	c := checker.NewChecker(s.options.Timeout, s.options.Verbose)
+
+	siteChan := make(chan sites.Site)
+	var wg sync.WaitGroup
+
+	for i := 0; i < s.options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for site := range siteChan {
+				url := httputil.FormatURL(site.URLFormat, username)
+				exists, err := c.CheckUsernameWithRetryContext(ctx, username, site.Name, url, s.options.Retries)
+				if ctx.Err() != nil {
+					// Results of checks interrupted by cancellation are meaningless
+					return
+				}
+
+				onResult(output.Result{
+					Username: username,
+					Site:     site.Name,
+					URL:      url,
+					Exists:   exists,
+					Error:    err,
+				})
+			}
+		}()
+	}
+
+feed:
+	for _, site := range s.sites {
+		select {
+		case siteChan <- site:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(siteChan)
+
+	wg.Wait()
+	return c.GetStats()
+}
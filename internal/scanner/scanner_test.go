@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/accio/internal/output"
+	"github.com/accio/internal/sites"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/johndoe") {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+}
+
+func TestScan(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	testSites := []sites.Site{
+		{Name: "SiteA", URLFormat: server.URL + "/a/{}"},
+		{Name: "SiteB", URLFormat: server.URL + "/b/{}"},
+		{Name: "SiteC", URLFormat: server.URL + "/c/{}"},
+	}
+
+	var mu sync.Mutex
+	var results []output.Result
+	stats := NewScanner(Options{Timeout: 5, Concurrency: 2, Retries: 1}).
+		WithSites(testSites).
+		Scan(context.Background(), "johndoe", func(result output.Result) {
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, result)
+		})
+
+	if len(results) != len(testSites) {
+		t.Fatalf("Expected %d results, got %d", len(testSites), len(results))
+	}
+
+	for _, result := range results {
+		if !result.Exists {
+			t.Errorf("Expected %s to exist", result.Site)
+		}
+		if !strings.HasSuffix(result.URL, "/johndoe") {
+			t.Errorf("Expected URL to contain the username, got %s", result.URL)
+		}
+	}
+
+	if stats.Found != len(testSites) {
+		t.Errorf("Expected Found to be %d, got %d", len(testSites), stats.Found)
+	}
+}
+
+func TestScanCancelled(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var called bool
+	NewScanner(Options{Timeout: 5, Concurrency: 1, Retries: 1}).
+		WithSites([]sites.Site{{Name: "SiteA", URLFormat: server.URL + "/a/{}"}}).
+		Scan(ctx, "johndoe", func(result output.Result) {
+			called = true
+		})
+
+	if called {
+		t.Error("Expected no results after cancellation")
+	}
+}
@@ -3,6 +3,7 @@ package sites
 // Site represents a website where a username can be checked
 type Site struct {
 	Name        string // Name of the site
+	Category    string // Kind of site, such as "social" or "development"
 	URL         string // URL for display purposes
 	ErrorType   string // Type of error to check for (status_code, message, etc.)
 	ErrorMsg    string // Error message to look for
@@ -17,6 +18,7 @@ func GetSites() []Site {
 	return []Site{
 		{
 			Name:        "GitHub",
+			Category:    "development",
 			URL:         "https://github.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -25,6 +27,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Twitter",
+			Category:    "social",
 			URL:         "https://twitter.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -33,6 +36,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Instagram",
+			Category:    "social",
 			URL:         "https://www.instagram.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -41,6 +45,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Facebook",
+			Category:    "social",
 			URL:         "https://www.facebook.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -49,6 +54,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "YouTube",
+			Category:    "video",
 			URL:         "https://www.youtube.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -57,6 +63,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Pinterest",
+			Category:    "social",
 			URL:         "https://www.pinterest.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -65,6 +72,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Reddit",
+			Category:    "forum",
 			URL:         "https://www.reddit.com/user/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -73,6 +81,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Twitch",
+			Category:    "video",
 			URL:         "https://www.twitch.tv/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -81,6 +90,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Medium",
+			Category:    "blogging",
 			URL:         "https://medium.com/@{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -89,6 +99,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Quora",
+			Category:    "forum",
 			URL:         "https://www.quora.com/profile/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -97,6 +108,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Flickr",
+			Category:    "art",
 			URL:         "https://www.flickr.com/people/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -105,6 +117,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Steam",
+			Category:    "gaming",
 			URL:         "https://steamcommunity.com/id/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -113,6 +126,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Vimeo",
+			Category:    "video",
 			URL:         "https://vimeo.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -121,6 +135,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "SoundCloud",
+			Category:    "music",
 			URL:         "https://soundcloud.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -129,6 +144,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Disqus",
+			Category:    "forum",
 			URL:         "https://disqus.com/by/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -137,6 +153,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Hackernews",
+			Category:    "forum",
 			URL:         "https://news.ycombinator.com/user?id={}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -145,6 +162,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Deviantart",
+			Category:    "art",
 			URL:         "https://{}.deviantart.com",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -153,6 +171,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Patreon",
+			Category:    "creator",
 			URL:         "https://www.patreon.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -161,6 +180,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "BitBucket",
+			Category:    "development",
 			URL:         "https://bitbucket.org/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -169,6 +189,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "GitLab",
+			Category:    "development",
 			URL:         "https://gitlab.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -177,6 +198,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Spotify",
+			Category:    "music",
 			URL:         "https://open.spotify.com/user/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -185,6 +207,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Behance",
+			Category:    "art",
 			URL:         "https://www.behance.net/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -193,6 +216,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Goodreads",
+			Category:    "hobby",
 			URL:         "https://www.goodreads.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -201,6 +225,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Instructables",
+			Category:    "hobby",
 			URL:         "https://www.instructables.com/member/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -209,6 +234,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Keybase",
+			Category:    "development",
 			URL:         "https://keybase.io/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -217,6 +243,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Kongregate",
+			Category:    "gaming",
 			URL:         "https://www.kongregate.com/accounts/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -225,6 +252,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Livejournal",
+			Category:    "blogging",
 			URL:         "https://{}.livejournal.com",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -233,6 +261,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "AngelList",
+			Category:    "professional",
 			URL:         "https://angel.co/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -241,6 +270,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Last.fm",
+			Category:    "music",
 			URL:         "https://www.last.fm/user/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -249,6 +279,7 @@ func GetSites() []Site {
 		},
 		{
 			Name:        "Dribbble",
+			Category:    "art",
 			URL:         "https://dribbble.com/{}",
 			ErrorType:   "status_code",
 			URLProbe:    false,
@@ -258,6 +289,18 @@ func GetSites() []Site {
 	}
 }
 
+// CategoryOther is the category of sites without one
+const CategoryOther = "other"
+
+// GetCategory returns the category of a site by its name, or CategoryOther
+// if the site is unknown
+func GetCategory(name string) string {
+	if site, ok := GetSiteByName(name); ok && site.Category != "" {
+		return site.Category
+	}
+	return CategoryOther
+}
+
 // GetSiteByName returns a site by its name
 func GetSiteByName(name string) (Site, bool) {
 	for _, site := range GetSites() {
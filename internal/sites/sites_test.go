@@ -26,6 +26,9 @@ func TestGetSites(t *testing.T) {
 		if site.CheckMethod == "" {
 			t.Errorf("Site at index %d has empty CheckMethod", i)
 		}
+		if site.Category == "" {
+			t.Errorf("Site at index %d has empty Category", i)
+		}
 	}
 }
 
@@ -45,3 +48,12 @@ func TestGetSiteByName(t *testing.T) {
 		t.Error("Expected not to find NonExistentSite, but it was found")
 	}
 }
+
+func TestGetCategory(t *testing.T) {
+	if category := GetCategory("GitHub"); category != "development" {
+		t.Errorf("Expected GitHub to be in development, got %s", category)
+	}
+	if category := GetCategory("NonExistentSite"); category != CategoryOther {
+		t.Errorf("Expected unknown site to be in %s, got %s", CategoryOther, category)
+	}
+}
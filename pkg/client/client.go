@@ -0,0 +1,26 @@
+// Package client is a typed Go client for the accio HTTP API.
+//
+// The types and request methods in client.gen.go are generated from the
+// server's OpenAPI document; run `go generate ./pkg/client` after changing it.
+//
+//	c, err := client.NewClientWithResponses("http://localhost:8080", client.WithAPIKey(key))
+//	if err != nil {
+//		return err
+//	}
+//	resp, err := c.GetProfileWithResponse(ctx, "Twitter", "johndoe", nil)
+package client
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 -config oapi-codegen.yaml ../../internal/presentation/http/openapi.yaml
+
+import (
+	"context"
+	"net/http"
+)
+
+// WithAPIKey authenticates every request with an API key
+func WithAPIKey(apiKey string) ClientOption {
+	return WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		return nil
+	})
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetProfileWithAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/profiles/Twitter/johndoe" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"real_name":      "John Doe",
+			"username":       "johndoe",
+			"platform":       "Twitter",
+			"profile_url":    "https://twitter.com/johndoe",
+			"image_url":      "",
+			"bio":            "",
+			"verified":       true,
+			"follower_count": 42,
+		})
+	}))
+	defer server.Close()
+
+	c, err := NewClientWithResponses(server.URL, WithAPIKey("test-key"))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := c.GetProfileWithResponse(context.Background(), "Twitter", "johndoe", nil)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.JSON200 == nil {
+		t.Fatalf("Expected profile, got status %d", resp.StatusCode())
+	}
+	if resp.JSON200.RealName != "John Doe" || !resp.JSON200.Verified || resp.JSON200.FollowerCount != 42 {
+		t.Errorf("Unexpected profile %+v", resp.JSON200)
+	}
+}
+
+func TestCreateScanError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error": map[string]any{"status": 403, "code": "forbidden", "message": "API key lacks the scan scope"},
+		})
+	}))
+	defer server.Close()
+
+	c, err := NewClientWithResponses(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := c.CreateScanWithResponse(context.Background(), CreateScanJSONRequestBody{Username: "johndoe"})
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.JSON403 == nil || resp.JSON403.Error.Code != "forbidden" {
+		t.Errorf("Expected forbidden error, got status %d", resp.StatusCode())
+	}
+}
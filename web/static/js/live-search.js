@@ -0,0 +1,196 @@
+// Live search over WebSocket. Renders profile cards and site hits as they
+// arrive instead of waiting for the full /search response. Falls back to the
+// plain htmx form when WebSockets are unavailable or the server refuses the
+// connection, e.g. because live search requires an API key.
+(function () {
+  'use strict';
+
+  if (!('WebSocket' in window)) {
+    return;
+  }
+
+  var socket = null;
+  var unavailable = false;
+
+  function connect(onOpen, onRefused) {
+    if (socket && socket.readyState === WebSocket.OPEN) {
+      onOpen();
+      return;
+    }
+
+    var scheme = window.location.protocol === 'https:' ? 'wss://' : 'ws://';
+    var opened = false;
+    socket = new WebSocket(scheme + window.location.host + '/ws/search');
+    socket.addEventListener('open', function () {
+      opened = true;
+      onOpen();
+    });
+    socket.addEventListener('message', function (event) {
+      handleMessage(JSON.parse(event.data));
+    });
+    socket.addEventListener('close', function () {
+      socket = null;
+      if (!opened) {
+        onRefused();
+      }
+    });
+  }
+
+  function element(tag, className, text) {
+    var el = document.createElement(tag);
+    if (className) {
+      el.className = className;
+    }
+    if (text !== undefined) {
+      el.textContent = text;
+    }
+    return el;
+  }
+
+  function resultsSection() {
+    return document.getElementById('results');
+  }
+
+  function renderStarted(message) {
+    var results = resultsSection();
+    results.textContent = '';
+
+    var wrapper = element('div', 'results live-results');
+    wrapper.appendChild(element('h3', null, 'Search Results for ' + message.query));
+
+    var progress = element('div', 'live-progress');
+    progress.appendChild(element('progress', 'live-progress-bar'));
+    progress.appendChild(element('span', 'live-progress-text'));
+    wrapper.appendChild(progress);
+
+    wrapper.appendChild(element('div', 'profiles-grid live-profiles'));
+    wrapper.appendChild(element('ul', 'site-list live-sites'));
+    results.appendChild(wrapper);
+
+    renderProgress(message.progress);
+  }
+
+  function renderProgress(progress) {
+    var bar = resultsSection().querySelector('.live-progress-bar');
+    var text = resultsSection().querySelector('.live-progress-text');
+    if (!bar || !progress) {
+      return;
+    }
+
+    bar.max = Math.max(progress.total, 1);
+    bar.value = progress.checked;
+    text.textContent = 'Checked ' + progress.checked + ' of ' + progress.total +
+      ' (' + progress.profiles + ' profiles, ' + progress.sites + ' sites)';
+  }
+
+  function renderProfile(profile) {
+    var grid = resultsSection().querySelector('.live-profiles');
+    if (!grid) {
+      return;
+    }
+
+    var card = element('div', 'profile-card');
+    card.setAttribute('hx-get', '/profile/' + encodeURIComponent(profile.platform) + '/' + encodeURIComponent(profile.username));
+    card.setAttribute('hx-target', '#results');
+
+    var imageWrapper = element('div', 'profile-image');
+    var image = element('img');
+    image.src = profile.image_url;
+    image.alt = profile.real_name;
+    imageWrapper.appendChild(image);
+    card.appendChild(imageWrapper);
+
+    var info = element('div', 'profile-info');
+    info.appendChild(element('h4', null, profile.real_name));
+    info.appendChild(element('p', null, '@' + profile.username + ' on ' + profile.platform));
+    info.appendChild(element('p', null, profile.follower_count + ' followers'));
+    if (profile.flagged) {
+      info.appendChild(element('p', 'profile-flagged', 'Reported as an incorrect match'));
+    }
+    card.appendChild(info);
+
+    grid.appendChild(card);
+    if (window.htmx) {
+      window.htmx.process(card);
+    }
+  }
+
+  function renderSite(result) {
+    var list = resultsSection().querySelector('.live-sites');
+    if (!list) {
+      return;
+    }
+
+    var item = element('li', 'site-hit');
+    var link = element('a', null, result.site);
+    link.href = result.url;
+    link.target = '_blank';
+    link.rel = 'noopener noreferrer';
+    item.appendChild(link);
+    list.appendChild(item);
+  }
+
+  function renderComplete(message) {
+    renderProgress(message.progress);
+    var progress = resultsSection().querySelector('.live-progress');
+    if (progress) {
+      progress.classList.add('complete');
+    }
+  }
+
+  function renderError(message) {
+    var results = resultsSection();
+    var error = element('p', 'live-error', message.error);
+    results.insertBefore(error, results.firstChild);
+  }
+
+  function handleMessage(message) {
+    switch (message.type) {
+      case 'started':
+        renderStarted(message);
+        break;
+      case 'profile':
+        renderProfile(message.profile);
+        break;
+      case 'site':
+        renderSite(message.result);
+        break;
+      case 'progress':
+        renderProgress(message.progress);
+        break;
+      case 'complete':
+        renderComplete(message);
+        break;
+      case 'error':
+        renderError(message);
+        break;
+    }
+  }
+
+  // Handle the submit before htmx sees it
+  document.addEventListener('submit', function (event) {
+    var form = event.target;
+    if (form.id !== 'search-form' || unavailable) {
+      return;
+    }
+
+    event.preventDefault();
+    event.stopPropagation();
+
+    var data = new FormData(form);
+    var request = {
+      action: 'search',
+      type: data.get('type'),
+      query: data.get('query'),
+      platforms: data.getAll('platforms')
+    };
+
+    connect(function () {
+      socket.send(JSON.stringify(request));
+    }, function () {
+      // Let htmx submit the form from now on
+      unavailable = true;
+      form.requestSubmit();
+    });
+  }, true);
+})();